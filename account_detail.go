@@ -0,0 +1,405 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/liability"
+
+	"github.com/Rhymond/go-money"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+)
+
+// accountRef identifies an asset or a plaid account, as their IDs can overlap.
+type accountRef struct {
+	accountType string
+	id          int64
+}
+
+// accountEntry is a transaction of an account and the balance of the account after it.
+type accountEntry struct {
+	t *lm.Transaction
+	// balance is nil when it cannot be worked out, such as before a transaction in another
+	// currency than the account
+	balance *money.Money
+}
+
+// accountDetail holds the account shown in the account details and its transactions.
+type accountDetail struct {
+	ref         accountRef
+	account     Account
+	isLiability bool
+	// entries are the transactions of the account in the selected period, newest first
+	entries []accountEntry
+	cursor  int
+	loading bool
+}
+
+// getAccountTransactionsMsg holds the transactions the account details are worked out from.
+type getAccountTransactionsMsg struct {
+	cacheInfo
+	ts     []*lm.Transaction
+	ref    accountRef
+	period Period
+}
+
+// findAccount returns the account with the ID and whether it is a liability. An empty
+// account type finds an asset before a plaid account with the same ID.
+func findAccount(
+	assets []*lm.Asset,
+	plaidAccounts []*lm.PlaidAccount,
+	ref accountRef,
+	classifier liability.Classifier,
+) (Account, bool, bool) {
+	if ref.accountType != plaidAccountType {
+		for _, a := range assets {
+			if a.ID == ref.id {
+				return convertAssetToAccount(a), classifier.IsLiability(liability.FromAsset(a)), true
+			}
+		}
+	}
+	if ref.accountType != assetAccountType {
+		for _, a := range plaidAccounts {
+			if a.ID == ref.id {
+				return convertPlaidAccountToAccount(a), classifier.IsLiability(liability.FromPlaidAccount(a)), true
+			}
+		}
+	}
+	return Account{}, false, false
+}
+
+// accountTransactionsPeriod returns the period the transactions of an account are loaded
+// for to show it in the period p. The running balance is worked out back from the current
+// balance, so the period runs until today when p ends earlier.
+func accountTransactionsPeriod(p Period, now time.Time) Period {
+	end := p.end
+	if now.After(end) {
+		end = now
+	}
+	return newCustomPeriod(p.start, end)
+}
+
+// accountEntries returns the transactions of the account in the period, newest first, with
+// the balance after each. ts must hold every transaction since the start of the period,
+// see accountTransactionsPeriod.
+func accountEntries(
+	ts []*lm.Transaction,
+	account Account,
+	isLiability, debitsAsNegative bool,
+	p Period,
+) []accountEntry {
+	var matched []*lm.Transaction
+	for _, t := range ts {
+		// the transactions of a group are on the account, not the group itself
+		if t.IsGroup || !onAccount(t, account) {
+			continue
+		}
+		matched = append(matched, t)
+	}
+	slices.SortStableFunc(matched, func(a, b *lm.Transaction) int {
+		return cmp.Or(strings.Compare(b.Date, a.Date), cmp.Compare(b.ID, a.ID))
+	})
+
+	balance, err := parseAmount(account.Balance, account.Currency)
+	if err != nil {
+		balance = nil
+	}
+
+	var entries []accountEntry
+	for _, e := range runningBalances(matched, balance, isLiability, debitsAsNegative) {
+		if e.t.Date >= p.startDate() && e.t.Date <= p.endDate() {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func onAccount(t *lm.Transaction, account Account) bool {
+	if account.AccountType == plaidAccountType {
+		return t.PlaidAccountID == account.ID
+	}
+	return t.AssetID == account.ID
+}
+
+// runningBalances works back from the current balance of an account to its balance after
+// each transaction, newest first. A debit lowers the balance of an asset and raises the
+// balance owed on a liability.
+func runningBalances(ts []*lm.Transaction, balance *money.Money, isLiability, debitsAsNegative bool) []accountEntry {
+	entries := make([]accountEntry, len(ts))
+	for i, t := range ts {
+		entries[i] = accountEntry{t: t, balance: balance}
+		if balance == nil {
+			continue
+		}
+
+		amount, err := parseAmount(t.Amount, t.Currency)
+		if err != nil || !amount.SameCurrency(balance) {
+			log.Debug("stopping the running balance", "transaction", t.ID, "currency", t.Currency)
+			balance = nil
+			continue
+		}
+
+		// money.Negative makes amounts negative rather than flipping their sign
+		debit := amount
+		if debitsAsNegative {
+			debit = amount.Multiply(-1)
+		}
+		// the balance before the transaction, a debit was taken from an asset
+		if isLiability {
+			balance, _ = balance.Subtract(debit)
+		} else {
+			balance, _ = balance.Add(debit)
+		}
+	}
+	return entries
+}
+
+// accountLabel names an account in the account picker.
+func accountLabel(a Account) string {
+	label := a.Name
+	if a.InstitutionName != "" {
+		label += " (" + a.InstitutionName + ")"
+	}
+	if a.Type != "" {
+		label += " · " + a.Type
+	}
+	return label
+}
+
+// newAssetRef is picked in the account picker to create a manually managed asset.
+var newAssetRef = accountRef{accountType: assetAccountType}
+
+// showAccountForm opens the account picker, which leads to the account details or to
+// the form for a new manually managed asset.
+func showAccountForm(m *model) (tea.Model, tea.Cmd) {
+	accounts := convertAccounts(slices.Collect(maps.Values(m.assets)), slices.Collect(maps.Values(m.plaidAccounts)))
+
+	options := make([]huh.Option[accountRef], 0, len(accounts)+1)
+	for _, a := range accounts {
+		options = append(options, huh.NewOption(accountLabel(a), accountRef{accountType: a.AccountType, id: a.ID}))
+	}
+	options = append(options, huh.NewOption("+ New manual asset", newAssetRef))
+
+	pick := &accountRef{}
+	if m.accountDetail != nil {
+		*pick = m.accountDetail.ref
+	}
+	m.accountPick = pick
+	m.accountForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[accountRef]().Title("Account").Options(options...).Value(pick),
+		),
+	).WithShowHelp(true)
+
+	if m.sessionState != accountPickerState {
+		m.previousSessionState = m.sessionState
+	}
+	m.sessionState = accountPickerState
+	return m, m.accountForm.Init()
+}
+
+func (m model) handleAccountFormState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.accountForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.accountForm = f
+	} else {
+		log.Debug("accountForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.accountForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	ref := *m.accountPick
+	m.accountPick = nil
+	if ref == newAssetRef {
+		return showAssetForm(m, nil)
+	}
+
+	account, isLiability, ok := findAccount(
+		slices.Collect(maps.Values(m.assets)), slices.Collect(maps.Values(m.plaidAccounts)), ref, m.config.Liabilities,
+	)
+	if !ok {
+		m.sessionState = m.previousSessionState
+		return m, nil
+	}
+
+	return showAccountDetail(m, ref, account, isLiability)
+}
+
+// showAccountDetail opens the account details and loads the transactions of the account.
+func showAccountDetail(m model, ref accountRef, account Account, isLiability bool) (tea.Model, tea.Cmd) {
+	m.accountDetail = &accountDetail{ref: ref, account: account, isLiability: isLiability, loading: true}
+	m.previousSessionState = m.sessionState
+	m.sessionState = accountDetailState
+	return m, m.getAccountTransactions
+}
+
+func (m model) getAccountTransactions() tea.Msg {
+	ref := m.accountDetail.ref
+	period := m.selectedPeriod()
+	loadPeriod := accountTransactionsPeriod(period, time.Now())
+
+	query := m.lmCache.transactionsQuery(loadPeriod.startDate(), loadPeriod.endDate(), m.debitsAsNegative)
+	return loadCached(m.lmCache, query, func(ts []*lm.Transaction, info cacheInfo) tea.Msg {
+		return getAccountTransactionsMsg{cacheInfo: info, ts: ts, ref: ref, period: period}
+	})()
+}
+
+func (m model) handleGetAccountTransactions(msg getAccountTransactionsMsg) (tea.Model, tea.Cmd) {
+	// the user moved to another account or period since the transactions were requested
+	detail := m.accountDetail
+	if detail == nil || detail.ref != msg.ref {
+		return m, nil
+	}
+	if expected := m.selectedPeriod(); expected.String() != msg.period.String() {
+		return m, nil
+	}
+
+	detail.entries = accountEntries(
+		m.withoutHiddenTransactions(msg.ts), detail.account, detail.isLiability, m.debitsAsNegative, msg.period,
+	)
+	detail.cursor = min(detail.cursor, max(len(detail.entries)-1, 0))
+	detail.loading = false
+	m.period = msg.period
+	return m, msg.refresh
+}
+
+func updateAccountDetail(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	detail := m.accountDetail
+	if !ok || detail == nil {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		detail.cursor = max(detail.cursor-1, 0)
+	case "down", "j":
+		detail.cursor = min(detail.cursor+1, max(len(detail.entries)-1, 0))
+	case "a":
+		return showAccountForm(&m)
+	case "e":
+		// only manually managed assets can be changed
+		if detail.ref.accountType == assetAccountType {
+			return showAssetForm(m, &detail.account)
+		}
+	}
+	return m, nil
+}
+
+func accountDetailView(m model) string {
+	detail := m.accountDetail
+	if detail == nil {
+		return "No account selected"
+	}
+
+	styles := createDetailedTransactionStyles(m.theme)
+	header := styles.headerStyle.Render("Account Details")
+	info := styles.containerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, accountDetailRows(detail, styles)...))
+	keys := "'↑'/'↓' move, 'a' other account, '['/']' period, 'esc' back"
+	if detail.ref.accountType == assetAccountType {
+		keys = "'↑'/'↓' move, 'e' edit asset, 'a' other account, '['/']' period, 'esc' back"
+	}
+	instructions := styles.instructionStyle.Render(keys)
+
+	if detail.loading {
+		return lipgloss.JoinVertical(lipgloss.Left, header, info, "Loading transactions...", instructions)
+	}
+	if len(detail.entries) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, header, info, "No transactions in this period", instructions)
+	}
+
+	// only render the rows around the cursor so the table fits the screen
+	rows := max(m.transactions.Height()-accountDetailHeightOffset, accountDetailMinRows)
+	offset := min(max(detail.cursor-rows/2, 0), max(len(detail.entries)-rows, 0))
+	end := min(offset+rows, len(detail.entries))
+
+	selectedStyle := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true).Padding(0, 1)
+	cellStyle := lipgloss.NewStyle().Foreground(m.theme.Text).Padding(0, 1)
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(m.theme.Border)).
+		Headers("DATE", "PAYEE", "CATEGORY", "AMOUNT", "BALANCE", "STATUS").
+		StyleFunc(func(row, _ int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return cellStyle.Bold(true)
+			case offset+row == detail.cursor:
+				return selectedStyle
+			default:
+				return cellStyle
+			}
+		})
+	for _, e := range detail.entries[offset:end] {
+		t.Row(accountEntryRow(e, m.idToCategory)...)
+	}
+
+	summary := fmt.Sprintf("%d transactions in %s", len(detail.entries), m.period.String())
+	return lipgloss.JoinVertical(lipgloss.Left, header, info, t.String(), summary, instructions)
+}
+
+// accountDetailRows renders what is known about the account above its transactions.
+func accountDetailRows(detail *accountDetail, styles detailedTransactionStyles) []string {
+	a := detail.account
+	kind := a.Type
+	if a.Subtype != "" {
+		kind += " / " + a.Subtype
+	}
+	if detail.isLiability {
+		kind += " (liability)"
+	}
+
+	balance := a.Balance + " " + strings.ToUpper(a.Currency)
+	if parsed, err := parseAmount(a.Balance, a.Currency); err == nil {
+		balance = parsed.Display()
+	}
+
+	rows := []string{
+		createDetailRow("Name:", a.Name, styles),
+		createDetailRow("Institution:", cmp.Or(a.InstitutionName, "-"), styles),
+		createDetailRow("Type:", kind, styles),
+		createDetailRow("Balance:", balance, styles),
+		createDetailRow("Balance as of:", formatAccountTime(a.BalanceAsOf), styles),
+	}
+	if a.AccountType == plaidAccountType {
+		rows = append(rows, createDetailRow("Last import:", formatAccountTime(a.LastImport), styles))
+	}
+	return append(rows, createDetailRow("Status:", a.Status, styles))
+}
+
+// accountEntryRow renders the table cells of a transaction in the account details.
+func accountEntryRow(e accountEntry, categories map[int64]*lm.Category) []string {
+	amount := e.t.Amount
+	if parsed, err := e.t.ParsedAmount(); err == nil {
+		amount = parsed.Display()
+	}
+	balance := "-"
+	if e.balance != nil {
+		balance = e.balance.Display()
+	}
+	category := transactionCategoryName(e.t)
+	if c, ok := categories[e.t.CategoryID]; ok {
+		category = c.Name
+	}
+	return []string{e.t.Date, e.t.Payee, category, amount, balance, e.t.Status}
+}
+
+// formatAccountTime formats when an account was updated, or "never" for a zero time.
+func formatAccountTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
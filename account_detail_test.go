@@ -0,0 +1,148 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/liability"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestAccountEntries(t *testing.T) {
+	ts := []*lm.Transaction{
+		{ID: 1, Date: "2024-01-05", Amount: "20.00", Currency: "usd", AssetID: 7},
+		{ID: 2, Date: "2024-01-20", Amount: "-100.00", Currency: "usd", AssetID: 7},
+		{ID: 3, Date: "2024-01-10", Amount: "5.00", Currency: "usd", AssetID: 8},
+		{ID: 4, Date: "2024-02-03", Amount: "30.00", Currency: "usd", AssetID: 7},
+		{ID: 5, Date: "2024-01-12", Amount: "15.00", Currency: "usd", AssetID: 7, IsGroup: true},
+		{ID: 6, Date: "2024-01-12", Amount: "15.00", Currency: "usd", PlaidAccountID: 7},
+	}
+	january := newCustomPeriod(
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+
+	tests := []struct {
+		name             string
+		account          Account
+		isLiability      bool
+		debitsAsNegative bool
+		expectedIDs      []int64
+		expectedBalances []string
+	}{
+		{
+			name:             "asset",
+			account:          Account{ID: 7, Balance: "500.00", Currency: "usd", AccountType: assetAccountType},
+			expectedIDs:      []int64{2, 1},
+			expectedBalances: []string{"530.00", "430.00"},
+		},
+		{
+			name:             "liability",
+			account:          Account{ID: 7, Balance: "500.00", Currency: "usd", AccountType: assetAccountType},
+			isLiability:      true,
+			expectedIDs:      []int64{2, 1},
+			expectedBalances: []string{"470.00", "570.00"},
+		},
+		{
+			name:             "debits as negative",
+			account:          Account{ID: 7, Balance: "500.00", Currency: "usd", AccountType: assetAccountType},
+			debitsAsNegative: true,
+			expectedIDs:      []int64{2, 1},
+			expectedBalances: []string{"470.00", "570.00"},
+		},
+		{
+			name:             "plaid account",
+			account:          Account{ID: 7, Balance: "50.00", Currency: "usd", AccountType: plaidAccountType},
+			expectedIDs:      []int64{6},
+			expectedBalances: []string{"50.00"},
+		},
+		{
+			name:             "other currency",
+			account:          Account{ID: 7, Balance: "500.00", Currency: "eur", AccountType: assetAccountType},
+			expectedIDs:      []int64{2, 1},
+			expectedBalances: []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := accountEntries(ts, tt.account, tt.isLiability, tt.debitsAsNegative, january)
+			be.Equal(t, len(tt.expectedIDs), len(entries))
+			for i, e := range entries {
+				be.Equal(t, tt.expectedIDs[i], e.t.ID)
+				balance := ""
+				if e.balance != nil {
+					balance = formatAmount(e.balance)
+				}
+				be.Equal(t, tt.expectedBalances[i], balance)
+			}
+		})
+	}
+}
+
+func TestAccountTransactionsPeriod(t *testing.T) {
+	january := newCustomPeriod(
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+
+	past := accountTransactionsPeriod(january, time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC))
+	be.Equal(t, "2024-01-01", past.startDate())
+	be.Equal(t, "2024-03-15", past.endDate())
+
+	current := accountTransactionsPeriod(january, time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC))
+	be.Equal(t, "2024-01-01", current.startDate())
+	be.Equal(t, "2024-01-31", current.endDate())
+}
+
+func TestFindAccount(t *testing.T) {
+	assets := []*lm.Asset{{ID: 1, Name: "Car loan", TypeName: "loan"}}
+	plaidAccounts := []*lm.PlaidAccount{
+		{ID: 1, Name: "Checking", Type: "depository"},
+		{ID: 2, Name: "Visa", Type: "credit"},
+	}
+
+	tests := []struct {
+		name              string
+		ref               accountRef
+		expectedName      string
+		expectedLiability bool
+		expectedFound     bool
+	}{
+		{
+			name:              "asset first",
+			ref:               accountRef{id: 1},
+			expectedName:      "Car loan",
+			expectedLiability: true,
+			expectedFound:     true,
+		},
+		{
+			name:          "plaid account by type",
+			ref:           accountRef{accountType: plaidAccountType, id: 1},
+			expectedName:  "Checking",
+			expectedFound: true,
+		},
+		{
+			name:              "plaid account",
+			ref:               accountRef{id: 2},
+			expectedName:      "Visa",
+			expectedLiability: true,
+			expectedFound:     true,
+		},
+		{
+			name: "not found",
+			ref:  accountRef{accountType: assetAccountType, id: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, isLiability, found := findAccount(assets, plaidAccounts, tt.ref, liability.Classifier{})
+			be.Equal(t, tt.expectedFound, found)
+			be.Equal(t, tt.expectedName, account.Name)
+			be.Equal(t, tt.expectedLiability, isLiability)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"golang.org/x/sync/errgroup"
+)
+
+// BatchAIProvider is implemented by providers that can recommend categories for
+// several transactions in a single request.
+type BatchAIProvider interface {
+	// RecommendCategories returns the recommendations keyed by transaction ID.
+	// Transactions the provider skipped are missing from the map.
+	// The examples are keyed by the ID of the transaction they are similar to.
+	RecommendCategories(
+		ctx context.Context,
+		transactions []*lm.Transaction,
+		categories []*lm.Category,
+		examples map[int64][]CategorizationExample,
+	) (map[int64]*CategoryRecommendation, error)
+}
+
+// aiBatchResult is the outcome of a batch recommendation for one transaction.
+type aiBatchResult struct {
+	Transaction    *lm.Transaction
+	Recommendation *CategoryRecommendation
+	Err            error
+}
+
+// RecommendCategories recommends categories for many transactions. Transactions are sent
+// in groups of aiBatchGroupSize with at most aiBatchConcurrency groups in flight. Errors
+// are reported per transaction so one failed group does not lose the others.
+func (r *AIRecommender) RecommendCategories(
+	ctx context.Context,
+	transactions []*lm.Transaction,
+	categories []*lm.Category,
+) []aiBatchResult {
+	results := make([]aiBatchResult, len(transactions))
+
+	var g errgroup.Group
+	g.SetLimit(aiBatchConcurrency)
+	for start := 0; start < len(transactions); start += aiBatchGroupSize {
+		end := min(start+aiBatchGroupSize, len(transactions))
+		g.Go(func() error {
+			r.recommendGroup(ctx, transactions[start:end], categories, results[start:end])
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
+
+// recommendGroup fills results with the recommendations for a group of transactions. Providers
+// without batch support get one request per transaction.
+func (r *AIRecommender) recommendGroup(
+	ctx context.Context,
+	transactions []*lm.Transaction,
+	categories []*lm.Category,
+	results []aiBatchResult,
+) {
+	batch, ok := r.provider.(BatchAIProvider)
+	if !ok {
+		for i, t := range transactions {
+			rec, err := r.recommend(ctx, t, categories)
+			if rec != nil {
+				rec.TransactionID = t.ID
+			}
+			results[i] = aiBatchResult{Transaction: t, Recommendation: rec, Err: err}
+		}
+		return
+	}
+
+	examples := make(map[int64][]CategorizationExample, len(transactions))
+	for _, t := range transactions {
+		examples[t.ID] = r.history.examples(ctx, t, aiExampleLimit)
+	}
+
+	recs, err := batch.RecommendCategories(ctx, transactions, categories, examples)
+	for i, t := range transactions {
+		results[i] = aiBatchResult{Transaction: t, Err: err}
+		if err != nil {
+			continue
+		}
+		if rec, found := recs[t.ID]; found {
+			rec.Examples = citedExamples(rec.exampleIDs, examples[t.ID])
+			results[i].Recommendation = rec
+		} else {
+			results[i].Err = errors.New("no recommendation returned")
+		}
+	}
+}
+
+// assignableCategories drops the category groups, which cannot be set on a transaction.
+func assignableCategories(categories []*lm.Category) []*lm.Category {
+	assignable := make([]*lm.Category, 0, len(categories))
+	for _, c := range categories {
+		if !c.IsGroup {
+			assignable = append(assignable, c)
+		}
+	}
+	return assignable
+}
+
+// buildBatchCategorizationPrompt constructs the prompt for recommending categories for several transactions.
+func buildBatchCategorizationPrompt(
+	transactions []*lm.Transaction,
+	categories []*lm.Category,
+	examples map[int64][]CategorizationExample,
+) string {
+	var sb strings.Builder
+	for _, t := range transactions {
+		fmt.Fprintf(&sb, "Transaction ID: %d\n%s\n", t.ID, formatTransactionForAI(t))
+		if e := formatExamplesForAI(examples[t.ID]); e != "" {
+			sb.WriteString(e)
+		}
+		sb.WriteString("\n")
+	}
+
+	return fmt.Sprintf(`You are a financial transaction categorization expert.
+Please analyze each of the following transactions and recommend the most appropriate category
+from the available options.
+
+%s%s
+
+Please respond with ONLY a JSON array containing one object per transaction in this exact format:
+[
+  {
+    "transaction_id": <number>,
+    "category_id": <number>,
+    "confidence": <number between 0-100>,
+    "reasoning": "<brief explanation>",
+    "example_ids": [<number>]
+  }
+]
+
+%s`, sb.String(), formatCategoriesForAI(categories), categorizationGuidelines)
+}
+
+// parseBatchCategoryRecommendations parses a batch AI response into recommendations keyed by
+// transaction ID. Entries that cannot be parsed are skipped.
+func parseBatchCategoryRecommendations(
+	response string,
+	categories []*lm.Category,
+) (map[int64]*CategoryRecommendation, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("no JSON array found in response: %s", response)
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal([]byte(response[start:end+1]), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	recommendations := make(map[int64]*CategoryRecommendation, len(entries))
+	for _, entry := range entries {
+		var id struct {
+			TransactionID json.Number `json:"transaction_id"`
+		}
+		if err := json.Unmarshal(entry, &id); err != nil {
+			log.Debug("skipping batch entry without transaction id", "entry", string(entry), "error", err)
+			continue
+		}
+		transactionID, err := id.TransactionID.Int64()
+		if err != nil {
+			log.Debug("skipping batch entry with invalid transaction id", "entry", string(entry))
+			continue
+		}
+
+		rec, err := parseCategoryRecommendation(string(entry), categories)
+		if err != nil {
+			log.Debug("skipping invalid batch entry", "entry", string(entry), "error", err)
+			continue
+		}
+		rec.TransactionID = transactionID
+		recommendations[transactionID] = rec
+	}
+
+	return recommendations, nil
+}
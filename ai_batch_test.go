@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+// fakeAIProvider recommends the category with the same ID as the transaction.
+type fakeAIProvider struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *fakeAIProvider) RecommendCategory(
+	_ context.Context,
+	t *lm.Transaction,
+	_ []*lm.Category,
+	_ []CategorizationExample,
+) (*CategoryRecommendation, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+
+	if t.ID == 3 {
+		return nil, errors.New("boom")
+	}
+	return &CategoryRecommendation{CategoryID: t.ID, Confidence: 90}, nil
+}
+
+// fakeBatchAIProvider skips transaction 2 and fails groups that contain transaction 13.
+type fakeBatchAIProvider struct {
+	fakeAIProvider
+	groups [][]int64
+}
+
+func (p *fakeBatchAIProvider) RecommendCategories(
+	_ context.Context,
+	ts []*lm.Transaction,
+	_ []*lm.Category,
+	_ map[int64][]CategorizationExample,
+) (map[int64]*CategoryRecommendation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var ids []int64
+	recs := make(map[int64]*CategoryRecommendation)
+	for _, t := range ts {
+		ids = append(ids, t.ID)
+		if t.ID == 13 {
+			return nil, errors.New("group failed")
+		}
+		if t.ID != 2 {
+			recs[t.ID] = &CategoryRecommendation{TransactionID: t.ID, CategoryID: t.ID}
+		}
+	}
+	p.groups = append(p.groups, ids)
+	return recs, nil
+}
+
+func testTransactions(n int) []*lm.Transaction {
+	ts := make([]*lm.Transaction, n)
+	for i := range ts {
+		ts[i] = &lm.Transaction{ID: int64(i + 1)}
+	}
+	return ts
+}
+
+func TestRecommendCategoriesSingle(t *testing.T) {
+	provider := &fakeAIProvider{}
+	results := NewAIRecommender(provider, nil).RecommendCategories(t.Context(), testTransactions(12), nil)
+
+	be.Equal(t, 12, len(results))
+	be.Equal(t, 12, provider.calls)
+	for i, r := range results {
+		be.Equal(t, int64(i+1), r.Transaction.ID)
+		if r.Transaction.ID == 3 {
+			be.Equal(t, "boom", r.Err.Error())
+			continue
+		}
+		be.NilErr(t, r.Err)
+		be.Equal(t, r.Transaction.ID, r.Recommendation.TransactionID)
+	}
+}
+
+func TestRecommendCategoriesBatch(t *testing.T) {
+	provider := &fakeBatchAIProvider{}
+	results := NewAIRecommender(provider, nil).RecommendCategories(t.Context(), testTransactions(25), nil)
+
+	be.Equal(t, 25, len(results))
+	// the group with transaction 13 failed, so only two groups returned
+	be.Equal(t, 2, len(provider.groups))
+	be.Equal(t, 0, provider.calls)
+
+	be.Equal(t, int64(1), results[0].Recommendation.CategoryID)
+	be.Equal(t, "no recommendation returned", results[1].Err.Error())
+	for _, r := range results[10:20] {
+		be.Equal(t, "group failed", r.Err.Error())
+	}
+	be.Equal(t, int64(25), results[24].Recommendation.CategoryID)
+}
+
+func TestParseBatchCategoryRecommendations(t *testing.T) {
+	categories := []*lm.Category{{ID: 1, Name: "Groceries"}, {ID: 2, Name: "Coffee Shops"}}
+	response := "Here you go:\n```json\n[\n" +
+		`{"transaction_id": 10, "category_id": 2, "confidence": 90, "reasoning": "coffee"},` +
+		`{"transaction_id": "11", "category_id": "1", "confidence": 40},` +
+		`{"transaction_id": 12, "category_id": 99, "confidence": 40},` +
+		`{"category_id": 1}` +
+		"\n]\n```"
+
+	recs, err := parseBatchCategoryRecommendations(response, categories)
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(recs))
+	be.Equal(t, "Coffee Shops", recs[10].CategoryName)
+	be.Equal(t, int64(10), recs[10].TransactionID)
+	be.Equal(t, "Groceries", recs[11].CategoryName)
+
+	_, err = parseBatchCategoryRecommendations("no idea", categories)
+	be.Nonzero(t, err)
+}
+
+func TestAssignableCategories(t *testing.T) {
+	categories := assignableCategories([]*lm.Category{{ID: 1}, {ID: 2, IsGroup: true}, {ID: 3}})
+	be.Equal(t, 2, len(categories))
+	be.Equal(t, int64(3), categories[1].ID)
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+)
+
+// CategorizationExample is a past transaction the user categorized, included in
+// prompts so recommendations follow the user's own habits.
+type CategorizationExample struct {
+	TransactionID int64  `json:"transaction_id"`
+	Date          string `json:"date"`
+	Payee         string `json:"payee"`
+	Amount        string `json:"amount"`
+	CategoryID    int64  `json:"category_id"`
+	CategoryName  string `json:"category_name"`
+}
+
+// historyFetcher returns the transactions between two dates (YYYY-MM-DD).
+type historyFetcher func(ctx context.Context, start, end string) ([]*lm.Transaction, error)
+
+// categorizationHistory selects examples from the categorized transactions of a lookback
+// window. The transactions are fetched once and shared by every recommendation.
+type categorizationHistory struct {
+	fetch        historyFetcher
+	lookbackDays int
+
+	mu     sync.Mutex
+	loaded bool
+	past   []*lm.Transaction
+	// loading is the fetch in progress, if any
+	loading chan struct{}
+	// failedAt is when the last fetch failed, to wait before fetching again
+	failedAt time.Time
+}
+
+// newCategorizationHistory creates a history over the last lookbackDays days.
+func newCategorizationHistory(fetch historyFetcher, lookbackDays int) *categorizationHistory {
+	return &categorizationHistory{fetch: fetch, lookbackDays: lookbackDays}
+}
+
+// newAIHistory creates the history configured by ai.history_days, or nil when it is disabled.
+func newAIHistory(config AIConfig, lmc *lm.Client, debitsAsNegative bool) *categorizationHistory {
+	if config.HistoryDays <= 0 {
+		return nil
+	}
+	return newCategorizationHistory(func(ctx context.Context, start, end string) ([]*lm.Transaction, error) {
+		return lmc.GetTransactions(ctx, newTransactionFilters(start, end, debitsAsNegative))
+	}, config.HistoryDays)
+}
+
+// load returns the categorized transactions of the lookback window. The first call starts
+// a fetch that every caller waits for without holding the lock, and that is not cancelled
+// with any one caller. A failed fetch is logged and retried once aiHistoryRetryDelay has
+// passed, so recommendations go without examples in the meantime.
+func (h *categorizationHistory) load(ctx context.Context) []*lm.Transaction {
+	h.mu.Lock()
+	if h.loaded {
+		h.mu.Unlock()
+		return h.past
+	}
+	if h.loading == nil {
+		if !h.failedAt.IsZero() && time.Since(h.failedAt) < aiHistoryRetryDelay {
+			h.mu.Unlock()
+			return nil
+		}
+		h.loading = make(chan struct{})
+		go h.fetchPast(h.loading)
+	}
+	loading := h.loading
+	h.mu.Unlock()
+
+	select {
+	case <-loading:
+	case <-ctx.Done():
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.past
+}
+
+// fetchPast fetches the transactions of the lookback window and closes done when finished.
+func (h *categorizationHistory) fetchPast(done chan struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), aiHistoryLoadTimeout)
+	defer cancel()
+
+	now := time.Now()
+	start := now.AddDate(0, 0, -h.lookbackDays).Format(time.DateOnly)
+	ts, err := h.fetch(ctx, start, now.Format(time.DateOnly))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.loading = nil
+
+	if err != nil {
+		log.Error("failed to load categorization history", "error", err, "retry_in", aiHistoryRetryDelay)
+		h.failedAt = time.Now()
+		return
+	}
+
+	h.past = make([]*lm.Transaction, 0, len(ts))
+	for _, t := range ts {
+		if t.CategoryID != 0 && t.Payee != "" {
+			h.past = append(h.past, t)
+		}
+	}
+	h.loaded = true
+
+	log.Debug("loaded categorization history", "days", h.lookbackDays, "transactions", len(h.past))
+}
+
+// examples returns up to limit past transactions with the most similar payee and amount.
+// Past transactions with an unrelated payee are never used.
+func (h *categorizationHistory) examples(ctx context.Context, t *lm.Transaction, limit int) []CategorizationExample {
+	if h == nil {
+		return nil
+	}
+
+	type scored struct {
+		t     *lm.Transaction
+		score float64
+	}
+
+	payee := payeeTokens(t.Payee)
+	var candidates []scored
+	for _, past := range h.load(ctx) {
+		if past.ID == t.ID {
+			continue
+		}
+		payeeScore := payeeSimilarity(payee, payeeTokens(past.Payee))
+		if payeeScore == 0 {
+			continue
+		}
+		candidates = append(candidates, scored{
+			t:     past,
+			score: aiPayeeWeight*payeeScore + amountSimilarity(t.Amount, past.Amount),
+		})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), strings.Compare(b.t.Date, a.t.Date))
+	})
+
+	// one example per payee and category is enough to show the habit
+	seen := make(map[string]bool)
+	var examples []CategorizationExample
+	for _, c := range candidates {
+		if len(examples) == limit {
+			break
+		}
+		key := fmt.Sprintf("%s/%d", strings.Join(payeeTokens(c.t.Payee), " "), c.t.CategoryID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		examples = append(examples, CategorizationExample{
+			TransactionID: c.t.ID,
+			Date:          c.t.Date,
+			Payee:         html.UnescapeString(c.t.Payee),
+			Amount:        c.t.Amount,
+			CategoryID:    c.t.CategoryID,
+			CategoryName:  html.UnescapeString(c.t.CategoryName),
+		})
+	}
+	return examples
+}
+
+// payeeTokens splits a payee into lowercase words, dropping numbers such as store
+// or reference numbers so "STARBUCKS #1234" and "Starbucks" compare equal.
+func payeeTokens(payee string) []string {
+	words := strings.FieldsFunc(strings.ToLower(html.UnescapeString(payee)), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	return slices.DeleteFunc(words, func(w string) bool { return len(w) < 2 })
+}
+
+// payeeSimilarity is the share of words two payees have in common, from 0 to 1.
+func payeeSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for _, w := range a {
+		if slices.Contains(b, w) {
+			common++
+		}
+	}
+	return float64(common) / float64(max(len(a), len(b)))
+}
+
+// amountSimilarity is 1 for equal amounts and approaches 0 as they differ, regardless of sign.
+func amountSimilarity(a, b string) float64 {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA != nil || errB != nil {
+		return 0
+	}
+
+	x, y = math.Abs(x), math.Abs(y)
+	if x == y {
+		return 1
+	}
+	return 1 - math.Abs(x-y)/math.Max(x, y)
+}
+
+// citedExamples returns the examples whose transaction IDs the provider reported as
+// influencing the recommendation. IDs that were not in the prompt are ignored.
+func citedExamples(ids []int64, examples []CategorizationExample) []CategorizationExample {
+	var cited []CategorizationExample
+	for _, e := range examples {
+		if slices.Contains(ids, e.TransactionID) {
+			cited = append(cited, e)
+		}
+	}
+	return cited
+}
+
+// formatExamplesForAI formats past categorizations for AI analysis.
+func formatExamplesForAI(examples []CategorizationExample) string {
+	if len(examples) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Similar transactions previously categorized by the user:\n")
+	for _, e := range examples {
+		fmt.Fprintf(&sb, "- Example ID: %d, Payee: %s, Amount: %s, Date: %s, Category ID: %d (%s)\n",
+			e.TransactionID, e.Payee, e.Amount, e.Date, e.CategoryID, e.CategoryName)
+	}
+	return sb.String()
+}
+
+// describeExamples lists the examples that influenced a recommendation for display.
+func describeExamples(examples []CategorizationExample) string {
+	if len(examples) == 0 {
+		return ""
+	}
+
+	described := make([]string, len(examples))
+	for i, e := range examples {
+		described[i] = fmt.Sprintf("%s → %s (%s)", e.Payee, e.CategoryName, e.Date)
+	}
+	return "Based on: " + strings.Join(described, ", ")
+}
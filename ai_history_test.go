@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestPayeeSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "store numbers ignored", a: "STARBUCKS #1234", b: "Starbucks", want: 1},
+		{name: "partial match", a: "Blue Bottle Coffee", b: "Blue Bottle", want: 2.0 / 3},
+		{name: "html escaped", a: "Trader Joe&#39;s", b: "trader joe's", want: 1},
+		{name: "unrelated", a: "Shell", b: "Starbucks", want: 0},
+		{name: "empty", a: "", b: "Starbucks", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.Equal(t, tt.want, payeeSimilarity(payeeTokens(tt.a), payeeTokens(tt.b)))
+		})
+	}
+}
+
+func TestAmountSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "equal", a: "4.5000", b: "4.50", want: 1},
+		{name: "sign ignored", a: "-10.00", b: "10.00", want: 1},
+		{name: "half", a: "5.00", b: "10.00", want: 0.5},
+		{name: "invalid", a: "abc", b: "10.00", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.Equal(t, tt.want, amountSimilarity(tt.a, tt.b))
+		})
+	}
+}
+
+func TestCategorizationHistoryExamples(t *testing.T) {
+	past := []*lm.Transaction{
+		{ID: 1, Date: "2025-01-01", Payee: "Starbucks", Amount: "5.00", CategoryID: 10, CategoryName: "Coffee"},
+		{ID: 2, Date: "2025-01-02", Payee: "STARBUCKS #42", Amount: "5.10", CategoryID: 10, CategoryName: "Coffee"},
+		{ID: 3, Date: "2025-01-03", Payee: "Starbucks Reserve", Amount: "40.00", CategoryID: 11, CategoryName: "Food"},
+		{ID: 4, Date: "2025-01-04", Payee: "Shell", Amount: "5.00", CategoryID: 12, CategoryName: "Gas"},
+		{ID: 5, Date: "2025-01-05", Payee: "Starbucks", Amount: "5.00"},
+		{ID: 6, Date: "2025-01-06", Payee: "Starbucks", Amount: "5.00", CategoryID: 10, CategoryName: "Coffee"},
+	}
+
+	fetches := 0
+	h := newCategorizationHistory(func(_ context.Context, _, _ string) ([]*lm.Transaction, error) {
+		fetches++
+		return past, nil
+	}, 30)
+
+	current := &lm.Transaction{ID: 6, Payee: "Starbucks", Amount: "5.00"}
+	examples := h.examples(t.Context(), current, 5)
+
+	// the transaction itself, uncategorized and unrelated payees are skipped and
+	// the duplicate Starbucks/Coffee example is dropped
+	be.Equal(t, 2, len(examples))
+	be.Equal(t, int64(1), examples[0].TransactionID)
+	be.Equal(t, int64(3), examples[1].TransactionID)
+
+	be.Equal(t, 1, len(h.examples(t.Context(), current, 1)))
+	be.Equal(t, 1, fetches)
+}
+
+func TestCategorizationHistoryRetriesFailedLoad(t *testing.T) {
+	past := []*lm.Transaction{{ID: 1, Payee: "Starbucks", Amount: "5.00", CategoryID: 10}}
+	var fetches atomic.Int32
+	fail := true
+	release := make(chan struct{})
+	h := newCategorizationHistory(func(ctx context.Context, _, _ string) ([]*lm.Transaction, error) {
+		fetches.Add(1)
+		if fail {
+			return nil, errors.New("boom")
+		}
+		<-release
+		return past, ctx.Err()
+	}, 30)
+	current := &lm.Transaction{ID: 2, Payee: "Starbucks", Amount: "5.00"}
+
+	// concurrent callers share one fetch and a failure is not retried straight away
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			be.Zero(t, len(h.examples(t.Context(), current, 5)))
+		}()
+	}
+	wg.Wait()
+	be.Zero(t, len(h.examples(t.Context(), current, 5)))
+	be.Equal(t, int32(1), fetches.Load())
+
+	// the fetch does not use the context of the caller that started it
+	fail = false
+	h.failedAt = h.failedAt.Add(-aiHistoryRetryDelay)
+	cancelled, cancel := context.WithCancel(t.Context())
+	cancel()
+	be.Zero(t, len(h.examples(cancelled, current, 5)))
+	close(release)
+	be.Equal(t, 1, len(h.examples(t.Context(), current, 5)))
+	be.Equal(t, int32(2), fetches.Load())
+
+	var disabled *categorizationHistory
+	be.Zero(t, len(disabled.examples(t.Context(), current, 5)))
+}
+
+func TestParseCategoryRecommendationExamples(t *testing.T) {
+	categories := []*lm.Category{{ID: 10, Name: "Coffee"}}
+	examples := []CategorizationExample{{TransactionID: 1}, {TransactionID: 2}}
+
+	rec, err := parseCategoryRecommendation(
+		`{"category_id": 10, "confidence": 90, "reasoning": "habit", "example_ids": [2, "99"]}`, categories)
+	be.NilErr(t, err)
+	cited := citedExamples(rec.exampleIDs, examples)
+	be.Equal(t, 1, len(cited))
+	be.Equal(t, int64(2), cited[0].TransactionID)
+}
+
+func TestDescribeExamples(t *testing.T) {
+	be.Equal(t, "", describeExamples(nil))
+	described := describeExamples([]CategorizationExample{
+		{Payee: "Starbucks", CategoryName: "Coffee", Date: "2025-01-01"},
+		{Payee: "Shell", CategoryName: "Gas", Date: "2025-01-04"},
+	})
+	be.Equal(t, "Based on: Starbucks → Coffee (2025-01-01), Shell → Gas (2025-01-04)", described)
+}
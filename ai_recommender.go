@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,19 +16,28 @@ import (
 type AIProvider interface {
 	// RecommendCategory returns a recommended category ID for the given transaction
 	// Returns the category ID, confidence score (0-100), and any error
+	// The examples are similar transactions the user categorized before
 	RecommendCategory(
 		ctx context.Context,
 		transaction *lm.Transaction,
 		categories []*lm.Category,
+		examples []CategorizationExample,
 	) (*CategoryRecommendation, error)
 }
 
 // CategoryRecommendation represents an AI recommendation for a transaction category.
 type CategoryRecommendation struct {
-	CategoryID   int64   `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	Confidence   float64 `json:"confidence"` // 0-100 confidence score
-	Reasoning    string  `json:"reasoning"`  // Why this category was recommended
+	// TransactionID is set for recommendations made in a batch
+	TransactionID int64   `json:"transaction_id,omitempty"`
+	CategoryID    int64   `json:"category_id"`
+	CategoryName  string  `json:"category_name"`
+	Confidence    float64 `json:"confidence"` // 0-100 confidence score
+	Reasoning     string  `json:"reasoning"`  // Why this category was recommended
+	// Examples are the past categorizations the provider said influenced the recommendation
+	Examples []CategorizationExample `json:"examples,omitempty"`
+
+	// exampleIDs are the example transaction IDs reported by the provider
+	exampleIDs []int64
 }
 
 // AIRecommendationMsg is sent when AI recommendation is completed.
@@ -45,14 +56,38 @@ type AIRecommendationLoadingMsg struct {
 type AIRecommender struct {
 	provider AIProvider
 	enabled  bool
+	// history provides the examples included in prompts, nil disables them
+	history *categorizationHistory
 }
 
 // NewAIRecommender creates a new AI recommender with the given provider.
-func NewAIRecommender(provider AIProvider) *AIRecommender {
+// The history may be nil to recommend without examples.
+func NewAIRecommender(provider AIProvider, history *categorizationHistory) *AIRecommender {
 	return &AIRecommender{
 		provider: provider,
 		enabled:  provider != nil,
+		history:  history,
+	}
+}
+
+// recommend gets the recommendation for a single transaction with examples from the history.
+func (r *AIRecommender) recommend(
+	ctx context.Context,
+	transaction *lm.Transaction,
+	categories []*lm.Category,
+) (*CategoryRecommendation, error) {
+	examples := r.history.examples(ctx, transaction, aiExampleLimit)
+	recommendation, err := r.provider.RecommendCategory(ctx, transaction, categories, examples)
+	if err != nil {
+		return nil, err
 	}
+
+	recommendation.Examples = citedExamples(recommendation.exampleIDs, examples)
+	log.Debug("recommendation examples",
+		"transaction_id", transaction.ID,
+		"examples", len(examples),
+		"cited", len(recommendation.Examples))
+	return recommendation, nil
 }
 
 // IsEnabled returns true if AI recommendations are available.
@@ -75,7 +110,7 @@ func (r *AIRecommender) RecommendCategory(transaction *lm.Transaction, categorie
 		ctx, cancel := context.WithTimeout(context.Background(), aiRecommendationTimeout)
 		defer cancel()
 
-		recommendation, err := r.provider.RecommendCategory(ctx, transaction, categories)
+		recommendation, err := r.recommend(ctx, transaction, categories)
 
 		if err != nil {
 			log.Error("AIRecommender recommendation failed", "error", err, "transaction_id", transaction.ID)
@@ -147,3 +182,123 @@ func formatCategoriesForAI(categories []*lm.Category) string {
 	}
 	return sb.String()
 }
+
+// categorizationGuidelines are shared by the single and batch categorization prompts.
+const categorizationGuidelines = `Guidelines:
+- Choose the category that best matches the transaction based on the payee, amount, and context
+- Confidence should reflect how certain you are (100 = very certain, 50 = moderate, 0 = just guessing)
+- Keep reasoning brief (1-2 sentences max)
+- If no category seems appropriate, choose the closest match and set confidence low
+- Consider common spending patterns and merchant categories
+- Prefer the categories the user chose for similar past transactions, when any are given
+- List the IDs of the examples that influenced the recommendation in example_ids (empty if none)`
+
+// buildCategorizationPrompt constructs the prompt for category recommendation.
+func buildCategorizationPrompt(
+	transaction *lm.Transaction,
+	categories []*lm.Category,
+	examples []CategorizationExample,
+) string {
+	transactionInfo := formatTransactionForAI(transaction)
+	if len(examples) > 0 {
+		transactionInfo += "\n\n" + strings.TrimSpace(formatExamplesForAI(examples))
+	}
+	categoriesInfo := formatCategoriesForAI(categories)
+
+	return fmt.Sprintf(`You are a financial transaction categorization expert. 
+Please analyze the following transaction and recommend the most appropriate category from the available options.
+
+%s
+
+%s
+
+Please respond with ONLY a JSON object in this exact format:
+{
+  "category_id": <number>,
+  "confidence": <number between 0-100>,
+  "reasoning": "<brief explanation>",
+  "example_ids": [<number>]
+}
+
+%s`, transactionInfo, categoriesInfo, categorizationGuidelines)
+}
+
+// parseCategoryRecommendation parses the AI response and extracts the recommendation.
+func parseCategoryRecommendation(response string, categories []*lm.Category) (*CategoryRecommendation, error) {
+	// Clean up the response - remove any markdown formatting or extra text
+	response = strings.TrimSpace(response)
+
+	// Find JSON content between braces
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+
+	if start == -1 || end == -1 {
+		return nil, fmt.Errorf("no JSON found in response: %s", response)
+	}
+
+	jsonStr := response[start : end+1]
+
+	var result struct {
+		CategoryID int64         `json:"category_id"`
+		Confidence float64       `json:"confidence"`
+		Reasoning  string        `json:"reasoning"`
+		ExampleIDs []json.Number `json:"example_ids"`
+	}
+
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		// Try to parse as string category_id in case the AI returned it as string
+		var altResult struct {
+			CategoryID string        `json:"category_id"`
+			Confidence float64       `json:"confidence"`
+			Reasoning  string        `json:"reasoning"`
+			ExampleIDs []json.Number `json:"example_ids"`
+		}
+		if err2 := json.Unmarshal([]byte(jsonStr), &altResult); err2 != nil {
+			return nil, fmt.Errorf("failed to parse JSON response: %w (original: %s)", err, jsonStr)
+		}
+		// Convert string to int64
+		if id, parseErr := strconv.ParseInt(altResult.CategoryID, 10, 64); parseErr == nil {
+			result.CategoryID = id
+			result.Confidence = altResult.Confidence
+			result.Reasoning = altResult.Reasoning
+			result.ExampleIDs = altResult.ExampleIDs
+		} else {
+			return nil, fmt.Errorf("invalid category_id format: %s", altResult.CategoryID)
+		}
+	}
+
+	// Find the category name
+	var categoryName string
+	for _, cat := range categories {
+		if cat.ID == result.CategoryID {
+			categoryName = cat.Name
+			break
+		}
+	}
+
+	if categoryName == "" {
+		return nil, fmt.Errorf("recommended category ID %d not found in available categories", result.CategoryID)
+	}
+
+	// Clamp confidence to 0-100 range
+	if result.Confidence < 0 {
+		result.Confidence = 0
+	} else if result.Confidence > maxConfidenceScore {
+		result.Confidence = maxConfidenceScore
+	}
+
+	var exampleIDs []int64
+	for _, n := range result.ExampleIDs {
+		if id, parseErr := n.Int64(); parseErr == nil {
+			exampleIDs = append(exampleIDs, id)
+		}
+	}
+
+	return &CategoryRecommendation{
+		CategoryID:   result.CategoryID,
+		CategoryName: categoryName,
+		Confidence:   result.Confidence,
+		Reasoning:    result.Reasoning,
+		exampleIDs:   exampleIDs,
+	}, nil
+}
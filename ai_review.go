@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+)
+
+// aiReviewItem is a transaction and its recommendation in the AI review table.
+type aiReviewItem struct {
+	item     transactionItem
+	rec      *CategoryRecommendation
+	err      error
+	accepted bool
+}
+
+// aiBatchReview holds the batch recommendations while they are reviewed.
+type aiBatchReview struct {
+	items  []aiReviewItem
+	cursor int
+	// threshold is the confidence at or above which recommendations are accepted
+	// automatically, zero disables auto-accepting
+	threshold float64
+	loading   bool
+}
+
+// aiBatchMsg is sent when the batch recommendations for a review are ready.
+type aiBatchMsg struct {
+	review  *aiBatchReview
+	results []aiBatchResult
+}
+
+// autoAccept accepts the recommendations at or above the threshold and rejects the rest.
+func (r *aiBatchReview) autoAccept() {
+	for i := range r.items {
+		it := &r.items[i]
+		it.accepted = r.threshold > 0 && it.rec != nil && it.rec.Confidence >= r.threshold
+	}
+}
+
+// setAccepted accepts or rejects every recommendation.
+func (r *aiBatchReview) setAccepted(accepted bool) {
+	for i := range r.items {
+		r.items[i].accepted = accepted && r.items[i].rec != nil
+	}
+}
+
+// accepted returns the items whose recommendation was accepted.
+func (r *aiBatchReview) accepted() []aiReviewItem {
+	var accepted []aiReviewItem
+	for _, it := range r.items {
+		if it.accepted {
+			accepted = append(accepted, it)
+		}
+	}
+	return accepted
+}
+
+// uncategorizedTransactionItems returns the visible transactions that have no category.
+// Pending transactions are skipped because they cannot be updated.
+func uncategorizedTransactionItems(m model) []transactionItem {
+	var items []transactionItem
+	for _, li := range m.transactions.VisibleItems() {
+		if t, ok := li.(transactionItem); ok && t.t.CategoryID == 0 && t.t.Status != pendingStatus {
+			items = append(items, t)
+		}
+	}
+	return items
+}
+
+// startAICategorization requests recommendations for every visible uncategorized
+// transaction and opens the review table.
+func startAICategorization(m model) (tea.Model, tea.Cmd) {
+	if m.aiRecommender == nil || !m.aiRecommender.IsEnabled() {
+		return m, m.transactions.NewStatusMessage("AI recommendations are not configured, see the [ai] config section")
+	}
+
+	items := uncategorizedTransactionItems(m)
+	if len(items) == 0 {
+		return m, m.transactions.NewStatusMessage("No uncategorized transactions to categorize")
+	}
+
+	review := &aiBatchReview{threshold: m.config.AI.AutoAcceptConfidence, loading: true}
+	ts := make([]*lm.Transaction, len(items))
+	for i, item := range items {
+		review.items = append(review.items, aiReviewItem{item: item})
+		ts[i] = item.t
+	}
+
+	log.Debug("starting AI batch categorization", "transactions", len(ts))
+
+	m.aiReview = review
+	m.previousSessionState = m.sessionState
+	m.sessionState = aiReviewState
+
+	recommender := m.aiRecommender
+	categories := assignableCategories(m.categories)
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), aiBatchTimeout)
+		defer cancel()
+		return aiBatchMsg{review: review, results: recommender.RecommendCategories(ctx, ts, categories)}
+	}
+}
+
+func (m model) handleAIBatchMsg(msg aiBatchMsg) (tea.Model, tea.Cmd) {
+	// results of a cancelled review are ignored
+	if m.aiReview == nil || msg.review != m.aiReview {
+		return m, nil
+	}
+
+	for i, result := range msg.results {
+		m.aiReview.items[i].rec = result.Recommendation
+		m.aiReview.items[i].err = result.Err
+	}
+	m.aiReview.loading = false
+	m.aiReview.autoAccept()
+	return m, nil
+}
+
+// updateAIReview handles the keys of the AI review table.
+func updateAIReview(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	review := m.aiReview
+	if !ok || review == nil || review.loading {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		review.cursor = max(review.cursor-1, 0)
+	case "down", "j":
+		review.cursor = min(review.cursor+1, len(review.items)-1)
+	case " ":
+		if it := &review.items[review.cursor]; it.rec != nil {
+			it.accepted = !it.accepted
+		}
+	case "a":
+		review.setAccepted(true)
+	case "x":
+		review.setAccepted(false)
+	case "+", "=":
+		review.threshold = min(review.threshold+aiThresholdStep, maxConfidenceScore)
+		review.autoAccept()
+	case "-":
+		review.threshold = max(review.threshold-aiThresholdStep, 0)
+		review.autoAccept()
+	case "enter":
+		return applyAIReview(m)
+	}
+	return m, nil
+}
+
+// applyAIReview categorizes the accepted transactions using the bulk runner.
+func applyAIReview(m model) (tea.Model, tea.Cmd) {
+	accepted := m.aiReview.accepted()
+	m.aiReview = nil
+	m.previousSessionState = m.sessionState
+
+	if len(accepted) == 0 {
+		m.sessionState = transactions
+		return m, m.transactions.NewStatusMessage("No AI recommendations accepted")
+	}
+
+	job := &bulkJob{action: bulkAICategorize}
+	for _, it := range accepted {
+		job.items = append(job.items, it.item)
+		job.updates = append(job.updates,
+			newBulkUpdate(bulkAICategorize, it.item, bulkOptions{categoryID: it.rec.CategoryID}))
+	}
+
+	log.Debug("applying AI recommendations", "count", len(job.items))
+
+	m.bulkJob = job
+	m.sessionState = bulkUpdating
+	return m, m.runBulkUpdate(0)
+}
+
+// formatThreshold describes the auto-accept threshold.
+func formatThreshold(threshold float64) string {
+	if threshold <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%.0f%%", threshold)
+}
+
+func aiReviewView(m model) string {
+	review := m.aiReview
+	if review == nil {
+		return "No AI categorization in progress"
+	}
+
+	styles := createDetailedTransactionStyles(m.theme)
+	header := styles.headerStyle.Render("AI categorization")
+
+	if review.loading {
+		return lipgloss.JoinVertical(lipgloss.Left, header,
+			fmt.Sprintf("🤖 Getting AI recommendations for %d transactions...", len(review.items)),
+			styles.instructionStyle.Render("Press 'esc' to cancel"),
+		)
+	}
+
+	// only render the rows around the cursor so the table fits the screen
+	rows := max(m.transactions.Height()-aiReviewHeightOffset, aiReviewMinRows)
+	offset := min(max(review.cursor-rows/2, 0), max(len(review.items)-rows, 0))
+	end := min(offset+rows, len(review.items))
+
+	selectedStyle := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true).Padding(0, 1)
+	cellStyle := lipgloss.NewStyle().Foreground(m.theme.Text).Padding(0, 1)
+	errStyle := lipgloss.NewStyle().Foreground(m.theme.Error).Padding(0, 1)
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(m.theme.Border)).
+		Headers("", "DATE", "PAYEE", "AMOUNT", "SUGGESTION", "CONFIDENCE").
+		StyleFunc(func(row, _ int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return cellStyle.Bold(true)
+			case offset+row == review.cursor:
+				return selectedStyle
+			case review.items[offset+row].err != nil:
+				return errStyle
+			default:
+				return cellStyle
+			}
+		})
+
+	for _, it := range review.items[offset:end] {
+		t.Row(aiReviewRow(it)...)
+	}
+
+	accepted := len(review.accepted())
+	summary := fmt.Sprintf("%d of %d accepted, auto-accept: %s",
+		accepted, len(review.items), formatThreshold(review.threshold))
+
+	lines := []string{header, t.String()}
+	if rec := review.items[review.cursor].rec; rec != nil {
+		lines = append(lines, styles.valueStyle.Render(rec.Reasoning))
+		if examples := describeExamples(rec.Examples); examples != "" {
+			lines = append(lines, styles.valueStyle.Render(examples))
+		}
+	}
+	lines = append(lines, summary, styles.instructionStyle.Render(
+		"'space' accept/reject, 'a' accept all, 'x' reject all, '+'/'-' auto-accept threshold,\n"+
+			"'enter' apply accepted, 'esc' cancel",
+	))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// aiReviewRow renders the table cells of a review item.
+func aiReviewRow(it aiReviewItem) []string {
+	mark := "[ ]"
+	if it.accepted {
+		mark = "[x]"
+	}
+
+	amount := it.item.t.Amount
+	if parsed, err := it.item.t.ParsedAmount(); err == nil {
+		amount = parsed.Display()
+	}
+
+	suggestion, confidence := "-", "-"
+	switch {
+	case it.err != nil:
+		// truncated by rune so payees and categories in the error keep whole characters
+		runes := []rune("error: " + strings.TrimSpace(it.err.Error()))
+		if len(runes) > aiReviewErrorWidth {
+			runes = append(runes[:aiReviewErrorWidth], []rune("...")...)
+		}
+		suggestion = string(runes)
+	case it.rec != nil:
+		suggestion = it.rec.CategoryName
+		confidence = fmt.Sprintf("%.0f%%", it.rec.Confidence)
+	}
+
+	return []string{mark, it.item.t.Date, it.item.t.Payee, amount, suggestion, confidence}
+}
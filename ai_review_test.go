@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/carlmjohnson/be"
+	tea "github.com/charmbracelet/bubbletea"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestAIBatchReview(t *testing.T) {
+	review := &aiBatchReview{
+		loading:   true,
+		threshold: 80,
+		items: []aiReviewItem{
+			{item: transactionItem{t: &lm.Transaction{ID: 1}}},
+			{item: transactionItem{t: &lm.Transaction{ID: 2}}},
+			{item: transactionItem{t: &lm.Transaction{ID: 3}}},
+		},
+	}
+	m := model{aiReview: review, sessionState: aiReviewState}
+
+	// results of another review are ignored
+	result, _ := m.handleAIBatchMsg(aiBatchMsg{review: &aiBatchReview{}})
+	be.True(t, result.(model).aiReview.loading)
+
+	result, _ = m.handleAIBatchMsg(aiBatchMsg{review: review, results: []aiBatchResult{
+		{Recommendation: &CategoryRecommendation{CategoryID: 10, Confidence: 95}},
+		{Recommendation: &CategoryRecommendation{CategoryID: 11, Confidence: 60}},
+		{Err: errors.New("boom")},
+	}})
+	m = result.(model)
+	be.False(t, review.loading)
+	be.Equal(t, 1, len(review.accepted()))
+
+	// lowering the threshold auto-accepts more recommendations
+	for range 4 {
+		result, _ = updateAIReview(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}}, m)
+		m = result.(model)
+	}
+	be.Equal(t, 60.0, review.threshold)
+	be.Equal(t, 2, len(review.accepted()))
+
+	// failed recommendations cannot be accepted
+	review.cursor = 2
+	result, _ = updateAIReview(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}, m)
+	m = result.(model)
+	be.Equal(t, 2, len(review.accepted()))
+
+	result, _ = updateAIReview(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}, m)
+	m = result.(model)
+	be.Equal(t, 0, len(review.accepted()))
+
+	result, _ = updateAIReview(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}, m)
+	m = result.(model)
+	be.Equal(t, 2, len(review.accepted()))
+
+	// accepted recommendations are applied with the bulk runner
+	result, cmd := updateAIReview(tea.KeyMsg{Type: tea.KeyEnter}, m)
+	m = result.(model)
+	be.Nonzero(t, cmd)
+	be.Zero(t, m.aiReview)
+	be.Equal(t, bulkUpdating, m.sessionState)
+	be.Equal(t, 2, len(m.bulkJob.items))
+	be.Equal(t, 11, *m.bulkJob.updates[1].CategoryID)
+}
+
+func TestAIReviewRowTruncatesError(t *testing.T) {
+	it := aiReviewItem{
+		item: transactionItem{t: &lm.Transaction{Date: "2025-01-01", Payee: "Café", Amount: "4.50"}},
+		err:  errors.New("no category matches Crème Brûlée Café for this transaction"),
+	}
+
+	suggestion := aiReviewRow(it)[4]
+	be.True(t, utf8.ValidString(suggestion))
+	be.Equal(t, "error: no category matches Crème Brûlée ...", suggestion)
+}
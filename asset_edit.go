@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/liability"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+)
+
+// assetEdit holds the values of the asset form, for a new manually managed asset or a
+// change to an existing one.
+type assetEdit struct {
+	// account is the asset being changed, nil for a new asset
+	account *Account
+	// today is the date the balance is as of unless it is changed
+	today string
+
+	name        string
+	typeName    string
+	subtype     string
+	institution string
+	balance     string
+	currency    string
+	asOf        string
+
+	// err is why the last attempt to save the asset failed
+	err error
+}
+
+// saveAssetMsg is sent once an asset has been created or changed.
+type saveAssetMsg struct {
+	edit  *assetEdit
+	asset *lm.Asset
+	err   error
+}
+
+// newAssetEdit fills the asset form with the account, or with defaults for a new asset.
+func newAssetEdit(account *Account, currency string, now time.Time) *assetEdit {
+	today := now.Format(dateLayout)
+	if account == nil {
+		return &assetEdit{today: today, typeName: "investment", currency: currency, asOf: today}
+	}
+
+	return &assetEdit{
+		account:     account,
+		today:       today,
+		name:        account.Name,
+		typeName:    account.Type,
+		subtype:     account.Subtype,
+		institution: account.InstitutionName,
+		balance:     account.Balance,
+		currency:    account.Currency,
+		asOf:        today,
+	}
+}
+
+// create returns the request that creates the new asset.
+func (e *assetEdit) create() *assetCreate {
+	return &assetCreate{
+		TypeName:        e.typeName,
+		SubtypeName:     strings.TrimSpace(e.subtype),
+		Name:            strings.TrimSpace(e.name),
+		Balance:         strings.TrimSpace(e.balance),
+		BalanceAsOf:     e.asOf,
+		Currency:        strings.ToLower(strings.TrimSpace(e.currency)),
+		InstitutionName: strings.TrimSpace(e.institution),
+	}
+}
+
+// update returns the fields of the asset that were changed in the form. The balance is
+// sent with the date it is as of when either of them changed.
+func (e *assetEdit) update() *lm.UpdateAsset {
+	a := e.account
+	update := &lm.UpdateAsset{}
+	changed := func(before, after string) *string {
+		if after = strings.TrimSpace(after); after != before {
+			return &after
+		}
+		return nil
+	}
+
+	update.Name = changed(a.Name, e.name)
+	update.TypeName = changed(a.Type, e.typeName)
+	update.SubtypeName = changed(a.Subtype, e.subtype)
+	update.InstitutionName = changed(a.InstitutionName, e.institution)
+	update.Currency = changed(a.Currency, strings.ToLower(e.currency))
+
+	before, _ := strconv.ParseFloat(a.Balance, 64)
+	after, _ := strconv.ParseFloat(strings.TrimSpace(e.balance), 64)
+	if before != after || e.asOf != e.today {
+		update.Balance = ptr(strings.TrimSpace(e.balance))
+		update.BalanceAsOf = ptr(e.asOf)
+	}
+
+	return update
+}
+
+// showAssetForm opens the form to create a manually managed asset, or to change the
+// account when it is not nil.
+func showAssetForm(m model, account *Account) (tea.Model, tea.Cmd) {
+	currency := ""
+	if m.user != nil {
+		currency = m.user.PrimaryCurrency
+	}
+	return openAssetForm(m, newAssetEdit(account, currency, time.Now()))
+}
+
+func openAssetForm(m model, edit *assetEdit) (tea.Model, tea.Cmd) {
+	m.assetEdit = edit
+	m.assetForm = newAssetForm(edit)
+	// the account picker returns to where it was opened from
+	if m.sessionState != accountPickerState && m.sessionState != assetFormState {
+		m.previousSessionState = m.sessionState
+	}
+	m.sessionState = assetFormState
+	return m, m.assetForm.Init()
+}
+
+func newAssetForm(edit *assetEdit) *huh.Form {
+	title := "New manual asset"
+	if edit.account != nil {
+		title = "Edit " + edit.account.Name
+	}
+	description := ""
+	if edit.err != nil {
+		description = "Error saving the asset: " + edit.err.Error()
+	}
+
+	types := assetTypes
+	if !slices.Contains(types, edit.typeName) {
+		types = append([]string{edit.typeName}, types...)
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("Name").Value(&edit.name).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("name is required")
+					}
+					return nil
+				}),
+			huh.NewSelect[string]().Title("Type").Options(huh.NewOptions(types...)...).Value(&edit.typeName),
+			huh.NewInput().Title("Subtype").Description("Optional, such as retirement or brokerage").
+				Value(&edit.subtype),
+			huh.NewInput().Title("Institution").Description("Optional").Value(&edit.institution),
+			huh.NewInput().Title("Balance").Value(&edit.balance).
+				Validate(func(s string) error {
+					return validateAssetBalance(strings.TrimSpace(s))
+				}),
+			huh.NewInput().Title("Currency").Value(&edit.currency),
+			huh.NewInput().Title("Balance as of").Description("YYYY-MM-DD").Value(&edit.asOf).
+				Validate(validateAsOf),
+			huh.NewConfirm().Title("Save").Key("submit"),
+		).Title(title).Description(description),
+	).WithShowHelp(true).WithShowErrors(true)
+}
+
+func (m model) handleAssetFormState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.assetForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.assetForm = f
+	} else {
+		log.Debug("assetForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.assetForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	m.sessionState = m.previousSessionState
+	edit := m.assetEdit
+	if !m.assetForm.GetBool("submit") || (edit.account != nil && *edit.update() == lm.UpdateAsset{}) {
+		m.assetEdit = nil
+		return m, nil
+	}
+	return m, m.submitAssetEdit(edit)
+}
+
+// submitAssetEdit creates or changes the asset through the API.
+func (m model) submitAssetEdit(edit *assetEdit) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), transactionLoadTimeout)
+		defer cancel()
+
+		msg := saveAssetMsg{edit: edit}
+		if edit.account == nil {
+			msg.asset, msg.err = createAsset(ctx, m.lmc, edit.create())
+		} else {
+			msg.asset, msg.err = m.lmc.UpdateAsset(ctx, edit.account.ID, edit.update())
+		}
+
+		if msg.err != nil {
+			log.Debug("error saving asset", "name", edit.name, "error", msg.err)
+		}
+		return msg
+	}
+}
+
+// handleSaveAssetMsg opens the account details of the saved asset, or the form again with
+// the error so the values are not lost.
+func (m model) handleSaveAssetMsg(msg saveAssetMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		msg.edit.err = msg.err
+		return openAssetForm(m, msg.edit)
+	}
+
+	m.assetEdit = nil
+	account := convertAssetToAccount(msg.asset)
+	isLiability := m.config.Liabilities.IsLiability(liability.FromAsset(msg.asset))
+	log.Debug("saved asset", "id", msg.asset.ID, "name", account.Name)
+	ref := accountRef{accountType: assetAccountType, id: msg.asset.ID}
+	model, cmd := showAccountDetail(m, ref, account, isLiability)
+	// the overview and the account picker show the new balance once the accounts reload
+	return model, tea.Batch(cmd, m.getAccounts)
+}
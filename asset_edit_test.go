@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestAssetEditUpdate(t *testing.T) {
+	account := &Account{
+		ID:          5,
+		Name:        "Brokerage",
+		Type:        "investment",
+		Balance:     "1000.0000",
+		Currency:    "usd",
+		AccountType: assetAccountType,
+	}
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		edit     func(e *assetEdit)
+		expected lm.UpdateAsset
+	}{
+		{
+			name:     "unchanged",
+			edit:     func(*assetEdit) {},
+			expected: lm.UpdateAsset{},
+		},
+		{
+			name:     "same balance written differently",
+			edit:     func(e *assetEdit) { e.balance = "1000" },
+			expected: lm.UpdateAsset{},
+		},
+		{
+			name:     "new balance",
+			edit:     func(e *assetEdit) { e.balance = " 1100.25 " },
+			expected: lm.UpdateAsset{Balance: ptr("1100.25"), BalanceAsOf: ptr("2024-03-01")},
+		},
+		{
+			name:     "new date",
+			edit:     func(e *assetEdit) { e.asOf = "2024-02-29" },
+			expected: lm.UpdateAsset{Balance: ptr("1000.0000"), BalanceAsOf: ptr("2024-02-29")},
+		},
+		{
+			name: "other fields",
+			edit: func(e *assetEdit) {
+				e.name = "Brokerage account"
+				e.institution = "Vanguard"
+				e.currency = "USD"
+			},
+			expected: lm.UpdateAsset{Name: ptr("Brokerage account"), InstitutionName: ptr("Vanguard")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit := newAssetEdit(account, "usd", now)
+			tt.edit(edit)
+			be.DeepEqual(t, tt.expected, *edit.update())
+		})
+	}
+}
+
+func TestAssetEditCreate(t *testing.T) {
+	edit := newAssetEdit(nil, "usd", time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+	edit.name = " House "
+	edit.typeName = "real estate"
+	edit.balance = "350000"
+	edit.currency = "EUR"
+
+	be.Equal(t, assetCreate{
+		TypeName:    "real estate",
+		Name:        "House",
+		Balance:     "350000",
+		BalanceAsOf: "2024-03-01",
+		Currency:    "eur",
+	}, *edit.create())
+}
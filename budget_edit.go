@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+)
+
+// budgetEdit is a change to the budget of a category for the current month.
+type budgetEdit struct {
+	item      budgetItem
+	startDate string
+	currency  string
+	// clear removes the budget instead of setting amount
+	clear  bool
+	amount string
+}
+
+// updateBudgetMsg is sent once a budget has been changed.
+type updateBudgetMsg struct {
+	category string
+	cleared  bool
+	err      error
+}
+
+// showBudgetForm opens the form to edit or clear the budget of the selected category.
+// Budgets are set per month, so they can only be changed in the monthly period.
+func showBudgetForm(m model, clearBudget bool) (tea.Model, tea.Cmd) {
+	item, ok := m.budgets.SelectedItem().(budgetItem)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case m.periodType != monthlyPeriodType:
+		return m, m.budgets.NewStatusMessage("Switch to a monthly period to change budgets")
+	case item.b.IsGroup:
+		return m, m.budgets.NewStatusMessage("Budgets are set on the categories of a group")
+	case clearBudget && item.summary.budgeted == nil:
+		return m, m.budgets.NewStatusMessage(fmt.Sprintf("%s has no budget", item.b.CategoryName))
+	}
+
+	edit := &budgetEdit{
+		item:      item,
+		startDate: m.period.startDate(),
+		currency:  m.budgetCurrency(item),
+		clear:     clearBudget,
+	}
+	for _, data := range item.b.Data {
+		if data != nil && data.BudgetAmount != "" {
+			edit.amount = data.BudgetAmount.String()
+		}
+	}
+
+	m.budgetEdit = edit
+	m.budgetForm = newBudgetForm(edit)
+	m.previousSessionState = m.sessionState
+	m.sessionState = editBudgetState
+	return m, m.budgetForm.Init()
+}
+
+// budgetCurrency returns the currency of the budget, or the primary currency without one.
+func (m model) budgetCurrency(item budgetItem) string {
+	for _, data := range item.b.Data {
+		if data != nil && data.BudgetCurrency != "" {
+			return data.BudgetCurrency
+		}
+	}
+	if m.user != nil {
+		return m.user.PrimaryCurrency
+	}
+	return ""
+}
+
+func newBudgetForm(edit *budgetEdit) *huh.Form {
+	if edit.clear {
+		return huh.NewForm(
+			huh.NewGroup(
+				huh.NewConfirm().Key("submit").
+					Title(fmt.Sprintf("Clear the %s budget for %s?", edit.item.b.CategoryName, edit.startDate)),
+			),
+		).WithShowHelp(true)
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title(fmt.Sprintf("%s budget", edit.item.b.CategoryName)).
+				Description(fmt.Sprintf("Budget for the month starting %s, leave empty to clear it", edit.startDate)).
+				Value(&edit.amount).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return nil
+					}
+					_, err := parseAmount(s, edit.currency)
+					return err
+				}),
+			huh.NewConfirm().Title("Save").Key("submit"),
+		),
+	).WithShowHelp(true).WithShowErrors(true)
+}
+
+func (m model) handleBudgetFormState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.budgetForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.budgetForm = f
+	} else {
+		log.Debug("budgetForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.budgetForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	m.previousSessionState = m.sessionState
+	m.sessionState = budgets
+	if !m.budgetForm.GetBool("submit") {
+		return m, m.budgets.NewStatusMessage("Budget not changed")
+	}
+
+	edit := m.budgetEdit
+	if strings.TrimSpace(edit.amount) == "" {
+		edit.clear = true
+	}
+	return m, m.submitBudgetEdit(edit)
+}
+
+// submitBudgetEdit sets or clears the budget through the API.
+func (m model) submitBudgetEdit(edit *budgetEdit) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), transactionLoadTimeout)
+		defer cancel()
+
+		categoryID := int64(edit.item.b.CategoryID)
+		msg := updateBudgetMsg{category: edit.item.b.CategoryName, cleared: edit.clear}
+		if edit.clear {
+			msg.err = removeBudget(ctx, m.lmc, edit.startDate, categoryID)
+		} else {
+			msg.err = upsertBudget(ctx, m.lmc, &budgetUpdate{
+				StartDate:  edit.startDate,
+				CategoryID: categoryID,
+				Amount:     strings.TrimSpace(edit.amount),
+				Currency:   edit.currency,
+			})
+		}
+
+		if msg.err != nil {
+			log.Debug("error changing budget", "category", categoryID, "error", msg.err)
+		}
+		return msg
+	}
+}
+
+func (m model) handleUpdateBudgetMsg(msg updateBudgetMsg) (tea.Model, tea.Cmd) {
+	m.budgetEdit = nil
+	if msg.err != nil {
+		return m, m.budgets.NewStatusMessage(fmt.Sprintf("Error changing budget: %s", msg.err.Error()))
+	}
+
+	status := fmt.Sprintf("Updated the %s budget", msg.category)
+	if msg.cleared {
+		status = fmt.Sprintf("Cleared the %s budget", msg.category)
+	}
+	return m, tea.Batch(m.getBudgets, m.budgets.NewStatusMessage(status))
+}
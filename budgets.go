@@ -2,68 +2,238 @@ package main
 
 import (
 	"fmt"
-	"strconv"
+	"math"
 
+	"github.com/Rhymond/go-money"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	lm "github.com/icco/lunchmoney"
 )
 
 type budgetItem struct {
 	b        *lm.Budget
 	category *lm.Category
+	summary  budgetSummary
+	// grouped is set on categories listed under their category group
+	grouped bool
 }
 
 // Title implements list.Item interface for budgetItem.
 func (b budgetItem) Title() string {
-	return b.b.CategoryName
+	title := b.b.CategoryName
+	switch {
+	case b.b.IsGroup:
+		title = "▾ " + title
+	case b.grouped:
+		title = "  " + title
+	}
+
+	if b.summary.over() && !b.b.IsIncome {
+		title += " (over budget)"
+	}
+	return title
 }
 
 func (b budgetItem) Description() string {
-	if len(b.b.Data) == 0 {
-		return "No budget data"
+	if b.grouped {
+		return "  " + b.summary.describe(b.b.IsIncome)
 	}
+	return b.summary.describe(b.b.IsIncome)
+}
 
-	// Get the first available budget data entry
-	for _, data := range b.b.Data {
-		if data == nil {
-			continue
+func (b budgetItem) FilterValue() string {
+	return b.b.CategoryName
+}
+
+// budgetSummary is the budget and the spending of a category over the current period,
+// which can span several budget months.
+type budgetSummary struct {
+	// budgeted is nil when the category has no budget
+	budgeted     *money.Money
+	spent        *money.Money
+	transactions int
+}
+
+// summarizeBudget adds up the budget data of every month in the period. Amounts are in
+// the primary currency. Income is summarized as a positive amount received.
+func summarizeBudget(b *lm.Budget, currency string) budgetSummary {
+	summary := budgetSummary{spent: money.New(0, currency)}
+	for _, data := range b.Data {
+		if data != nil {
+			summary = summary.add(summarizeBudgetMonth(data, b.IsIncome, currency))
+		}
+	}
+	return summary
+}
+
+// summarizeBudgetMonth summarizes the budget data of a single month.
+func summarizeBudgetMonth(data *lm.BudgetData, income bool, currency string) budgetSummary {
+	spent := data.SpendingToBase
+	if income {
+		spent = math.Abs(spent)
+	}
+
+	summary := budgetSummary{spent: newMoney(spent, currency), transactions: data.NumTransactions}
+	if data.BudgetAmount != "" {
+		summary.budgeted = newMoney(data.BudgetToBase, currency)
+	}
+	return summary
+}
+
+// add adds the budget and spending of other to the summary.
+func (s budgetSummary) add(other budgetSummary) budgetSummary {
+	s.spent, _ = s.spent.Add(other.spent)
+	s.transactions += other.transactions
+	if other.budgeted != nil {
+		if s.budgeted == nil {
+			s.budgeted = other.budgeted
+		} else {
+			s.budgeted, _ = s.budgeted.Add(other.budgeted)
+		}
+	}
+	return s
+}
+
+// remaining returns the budget left, negative when the budget is exceeded.
+func (s budgetSummary) remaining() *money.Money {
+	if s.budgeted == nil {
+		return nil
+	}
+	remaining, _ := s.budgeted.Subtract(s.spent)
+	return remaining
+}
+
+// over reports whether more than the budget has been spent.
+func (s budgetSummary) over() bool {
+	remaining := s.remaining()
+	return remaining != nil && remaining.IsNegative()
+}
+
+// progressBar renders how much of the budget has been spent, full when it is exceeded.
+func (s budgetSummary) progressBar(width int) string {
+	if s.budgeted == nil {
+		return ""
+	}
+
+	filled := width
+	if s.budgeted.IsPositive() {
+		ratio := float64(s.spent.Amount()) / float64(s.budgeted.Amount())
+		filled = int(math.Round(math.Min(math.Max(ratio, 0), 1) * float64(width)))
+	}
+	return renderProgressBar(filled, width, width)
+}
+
+// describe describes the budget variance, with income described as an amount to receive.
+func (s budgetSummary) describe(income bool) string {
+	count := fmt.Sprintf("%d transactions", s.transactions)
+	if s.budgeted == nil {
+		verb := "spent"
+		if income {
+			verb = "received"
+		}
+		return fmt.Sprintf("No budget | %s %s | %s", s.spent.Display(), verb, count)
+	}
+
+	remaining := s.remaining()
+	var variance string
+	switch {
+	case income && remaining.IsNegative():
+		variance = remaining.Absolute().Display() + " above budget"
+	case income:
+		variance = remaining.Display() + " to go"
+	case remaining.IsNegative():
+		variance = remaining.Absolute().Display() + " over"
+	default:
+		variance = remaining.Display() + " left"
+	}
+
+	return fmt.Sprintf("%s %s of %s | %s | %s",
+		s.progressBar(budgetProgressWidth), s.spent.Display(), s.budgeted.Display(), variance, count)
+}
+
+// newBudgetItems lists the budgets of the period with every category under its category
+// group. Categories excluded from the budget are left out.
+func newBudgetItems(budgets []*lm.Budget, categories map[int64]*lm.Category, currency string) []list.Item {
+	children := make(map[int][]*lm.Budget)
+	groups := make(map[int]bool)
+	for _, b := range budgets {
+		if b.IsGroup {
+			groups[b.CategoryID] = true
+		}
+	}
+	for _, b := range budgets {
+		if b.GroupID != 0 && groups[b.GroupID] {
+			children[b.GroupID] = append(children[b.GroupID], b)
 		}
+	}
 
-		amount := "0"
-		if data.BudgetAmount != "" {
-			amount = string(data.BudgetAmount)
+	newItem := func(b *lm.Budget, grouped bool) budgetItem {
+		return budgetItem{
+			b:        b,
+			category: categories[int64(b.CategoryID)],
+			summary:  summarizeBudget(b, currency),
+			grouped:  grouped,
 		}
+	}
 
-		m, err := data.ParsedAmount()
-		if err != nil {
-			return fmt.Sprintf("Budget: %s %s | Spent: %s | Transactions: %d",
-				amount, data.BudgetCurrency, "N/A", data.NumTransactions)
+	items := make([]list.Item, 0, len(budgets))
+	for _, b := range budgets {
+		if b.ExcludeFromBudget || (b.GroupID != 0 && groups[b.GroupID]) {
+			continue
 		}
+		items = append(items, newItem(b, false))
 
-		spent := strconv.FormatFloat(data.SpendingToBase, 'f', 2, 64)
-		spentMoney, err := lm.ParseCurrency(spent, data.BudgetCurrency)
-		if err != nil {
-			return fmt.Sprintf("Budget: %s %s | Spent: %s | Transactions: %d",
-				amount, data.BudgetCurrency, "N/A", data.NumTransactions)
+		for _, child := range children[b.CategoryID] {
+			if !child.ExcludeFromBudget {
+				items = append(items, newItem(child, true))
+			}
 		}
+	}
+	return items
+}
 
-		return fmt.Sprintf("Budget: %s | Spent: %s | Transactions: %d",
-			m.Display(), spentMoney.Display(), data.NumTransactions)
+// budgetTotals adds up the budgets and spending of the expense categories in items.
+// Groups are skipped as their categories are already counted.
+func budgetTotals(items []list.Item, currency string) budgetSummary {
+	totals := budgetSummary{spent: money.New(0, currency)}
+	for _, item := range items {
+		b, ok := item.(budgetItem)
+		if !ok || b.b.IsGroup || b.b.IsIncome {
+			continue
+		}
+		totals = totals.add(b.summary)
 	}
+	return totals
+}
 
-	return "No budget data available"
+type budgetListKeyMap struct {
+	editBudget  key.Binding
+	clearBudget key.Binding
 }
 
-func (b budgetItem) FilterValue() string {
-	return b.b.CategoryName
+func newBudgetListKeyMap() *budgetListKeyMap {
+	return &budgetListKeyMap{
+		editBudget: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit budget"),
+		),
+		clearBudget: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "clear budget"),
+		),
+	}
 }
 
 // createBudgetList creates a new list model for budgets.
-func createBudgetList(delegate list.DefaultDelegate) list.Model {
+func createBudgetList(delegate list.DefaultDelegate, keys *budgetListKeyMap) list.Model {
 	budgetList := list.New([]list.Item{}, delegate, 0, 0)
 	budgetList.SetShowTitle(false)
 	budgetList.StatusMessageLifetime = budgetStatusMessageLifetime
+	budgetList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.editBudget, keys.clearBudget}
+	}
 	return budgetList
 }
 
@@ -71,6 +241,15 @@ func createBudgetList(delegate list.DefaultDelegate) list.Model {
 func updateBudgets(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	// Period navigation and other keys are handled in handleKeyPress
 	// so we just need to handle the list updates here
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.budgets.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(keyMsg, m.budgetListKeys.editBudget):
+			return showBudgetForm(m, false)
+		case key.Matches(keyMsg, m.budgetListKeys.clearBudget):
+			return showBudgetForm(m, true)
+		}
+	}
+
 	var cmd tea.Cmd
 	m.budgets, cmd = m.budgets.Update(msg)
 	return m, cmd
@@ -78,5 +257,25 @@ func updateBudgets(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 
 // budgetsView renders the budgets view.
 func budgetsView(m model) string {
-	return m.budgets.View()
+	totals := m.budgetTotals
+	if totals.spent == nil {
+		return m.budgets.View()
+	}
+
+	summary := fmt.Sprintf("Spent %s", totals.spent.Display())
+	if remaining := totals.remaining(); remaining != nil {
+		style := lipgloss.NewStyle().Foreground(m.theme.Success)
+		variance := remaining.Display() + " left"
+		if remaining.IsNegative() {
+			style = lipgloss.NewStyle().Foreground(m.theme.Error)
+			variance = remaining.Absolute().Display() + " over"
+		}
+		summary = fmt.Sprintf("Budgeted %s | Spent %s | %s",
+			totals.budgeted.Display(), totals.spent.Display(), style.Render(variance))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().MarginLeft(standardMargin).MarginBottom(1).Render(summary),
+		m.budgets.View(),
+	)
 }
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+// budgetMonth returns the budget data of a month with a budget of amount in the primary currency.
+func budgetMonth(amount string, spent float64, transactions int) *lm.BudgetData {
+	budget, _ := strconv.ParseFloat(amount, 64)
+	return &lm.BudgetData{
+		BudgetAmount:    json.Number(amount),
+		BudgetToBase:    budget,
+		SpendingToBase:  spent,
+		NumTransactions: transactions,
+	}
+}
+
+func TestSummarizeBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		budget       *lm.Budget
+		expected     string
+		over         bool
+		transactions int
+	}{
+		{
+			name: "under budget",
+			budget: &lm.Budget{Data: map[string]*lm.BudgetData{
+				"2025-01-01": budgetMonth("500", 123.45, 4),
+			}},
+			expected: "[█████░░░░░░░░░░░░░░░] " +
+				"$123.45 of $500.00 | $376.55 left | 4 transactions",
+			transactions: 4,
+		},
+		{
+			name: "over budget across months",
+			budget: &lm.Budget{Data: map[string]*lm.BudgetData{
+				"2025-01-01": budgetMonth("100", 150, 2),
+				"2025-02-01": budgetMonth("100", 75, 1),
+			}},
+			expected: "[████████████████████] " +
+				"$225.00 of $200.00 | $25.00 over | 3 transactions",
+			over:         true,
+			transactions: 3,
+		},
+		{
+			name: "no budget",
+			budget: &lm.Budget{Data: map[string]*lm.BudgetData{
+				"2025-01-01": {SpendingToBase: 42, NumTransactions: 1},
+			}},
+			expected:     "No budget | $42.00 spent | 1 transactions",
+			transactions: 1,
+		},
+		{
+			name: "income to go",
+			budget: &lm.Budget{IsIncome: true, Data: map[string]*lm.BudgetData{
+				"2025-01-01": budgetMonth("4000", -3000, 1),
+			}},
+			expected: "[███████████████░░░░░] " +
+				"$3,000.00 of $4,000.00 | $1,000.00 to go | 1 transactions",
+			transactions: 1,
+		},
+		{
+			name: "income above budget",
+			budget: &lm.Budget{IsIncome: true, Data: map[string]*lm.BudgetData{
+				"2025-01-01": budgetMonth("4000", -4500, 2),
+			}},
+			expected: "[████████████████████] " +
+				"$4,500.00 of $4,000.00 | $500.00 above budget | 2 transactions",
+			over:         true,
+			transactions: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := summarizeBudget(tt.budget, "usd")
+			be.Equal(t, tt.expected, summary.describe(tt.budget.IsIncome))
+			be.Equal(t, tt.over, summary.over())
+			be.Equal(t, tt.transactions, summary.transactions)
+		})
+	}
+}
+
+func TestNewBudgetItems(t *testing.T) {
+	budgets := []*lm.Budget{
+		{CategoryID: 1, CategoryName: "Food", IsGroup: true},
+		{CategoryID: 2, CategoryName: "Rent", Data: map[string]*lm.BudgetData{
+			"2025-01-01": {BudgetAmount: "1000", BudgetToBase: 1000, SpendingToBase: 1000},
+		}},
+		{CategoryID: 3, CategoryName: "Groceries", GroupID: 1, Data: map[string]*lm.BudgetData{
+			"2025-01-01": {BudgetAmount: "300", BudgetToBase: 300, SpendingToBase: 350},
+		}},
+		{CategoryID: 4, CategoryName: "Transfers", ExcludeFromBudget: true},
+		{CategoryID: 5, CategoryName: "Restaurants", GroupID: 1, Data: map[string]*lm.BudgetData{
+			"2025-01-01": {SpendingToBase: 80},
+		}},
+		{CategoryID: 6, CategoryName: "Salary", IsIncome: true, Data: map[string]*lm.BudgetData{
+			"2025-01-01": {BudgetAmount: "4000", BudgetToBase: 4000, SpendingToBase: -4000},
+		}},
+	}
+
+	items := newBudgetItems(budgets, map[int64]*lm.Category{}, "usd")
+
+	titles := make([]string, len(items))
+	for i, item := range items {
+		titles[i] = item.(budgetItem).Title()
+	}
+	be.AllEqual(t, []string{
+		"▾ Food",
+		"  Groceries (over budget)",
+		"  Restaurants",
+		"Rent",
+		"Salary",
+	}, titles)
+
+	totals := budgetTotals(items, "usd")
+	be.Equal(t, "$1,300.00", totals.budgeted.Display())
+	be.Equal(t, "$1,430.00", totals.spent.Display())
+	be.Equal(t, "-$130.00", totals.remaining().Display())
+}
+
+func TestUpsertBudget(t *testing.T) {
+	lmc, requests := newTestGroupClient(t, http.StatusOK, `{"category_group":null}`)
+
+	err := upsertBudget(t.Context(), lmc, &budgetUpdate{
+		StartDate:  "2025-01-01",
+		CategoryID: 3,
+		Amount:     "250.00",
+		Currency:   "usd",
+	})
+	be.NilErr(t, err)
+
+	be.Equal(t, 1, len(*requests))
+	req := (*requests)[0]
+	be.Equal(t, http.MethodPut, req.method)
+	be.Equal(t, "/v1/budgets", req.path)
+
+	var body map[string]any
+	be.NilErr(t, json.Unmarshal(req.body, &body))
+	be.Equal[any](t, "2025-01-01", body["start_date"])
+	be.Equal[any](t, float64(3), body["category_id"])
+	be.Equal[any](t, "250.00", body["amount"])
+	be.Equal[any](t, "usd", body["currency"])
+}
+
+func TestRemoveBudget(t *testing.T) {
+	t.Run("removes the budget", func(t *testing.T) {
+		lmc, requests := newTestGroupClient(t, http.StatusOK, "true")
+
+		be.NilErr(t, removeBudget(t.Context(), lmc, "2025-01-01", 3))
+
+		be.Equal(t, 1, len(*requests))
+		req := (*requests)[0]
+		be.Equal(t, http.MethodDelete, req.method)
+		be.Equal(t, "/v1/budgets", req.path)
+		be.Equal(t, "category_id=3&start_date=2025-01-01", req.query)
+		be.Equal(t, "Bearer token", req.auth)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		lmc, _ := newTestGroupClient(t, http.StatusOK, `{"error":"Budget not found"}`)
+
+		err := removeBudget(t.Context(), lmc, "2025-01-01", 3)
+		be.In(t, "Budget not found", err.Error())
+	})
+}
@@ -0,0 +1,494 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+
+	"github.com/Rshep3087/lunchtui/rules"
+)
+
+// bulkAction is an action that can be applied to every marked transaction.
+type bulkAction string
+
+const (
+	bulkCategorize  bulkAction = "categorize"
+	bulkReview      bulkAction = "review"
+	bulkUnreview    bulkAction = "unreview"
+	bulkAddTags     bulkAction = "add tags"
+	bulkAppendNotes bulkAction = "append notes"
+	bulkApplyRules  bulkAction = "apply rules"
+	// bulkAICategorize applies accepted AI recommendations, each with its own category
+	bulkAICategorize bulkAction = "AI categorize"
+	// bulkGroup groups the marked transactions into a single new transaction
+	bulkGroup bulkAction = "group"
+)
+
+// bulkOptions holds the values chosen in the bulk action form.
+type bulkOptions struct {
+	categoryID int64
+	tagIDs     []int
+	notes      string
+}
+
+// bulkFailure records a transaction the bulk action could not update.
+type bulkFailure struct {
+	item transactionItem
+	err  error
+}
+
+// bulkJob tracks the progress of a bulk action. Transactions are updated one at a
+// time so the progress can be shown and a failure does not stop the others.
+type bulkJob struct {
+	action   bulkAction
+	items    []transactionItem
+	updates  []*transactionUpdate
+	next     int
+	failures []bulkFailure
+}
+
+func (j *bulkJob) running() bool {
+	return j.next < len(j.items)
+}
+
+// bulkUpdateMsg is sent after each transaction of a bulk job is updated.
+type bulkUpdateMsg struct {
+	index int
+	err   error
+}
+
+// newBulkUpdate builds the update that applies the action to a single transaction, or nil
+// for the actions that are not applied one transaction at a time.
+func newBulkUpdate(action bulkAction, item transactionItem, opts bulkOptions) *transactionUpdate {
+	update := &transactionUpdate{}
+	switch action {
+	case bulkCategorize, bulkAICategorize:
+		// categorizing a transaction also reviews it, like the single categorize form
+		update.CategoryID = ptr(int(opts.categoryID))
+		update.Status = ptr(clearedStatus)
+	case bulkReview:
+		update.Status = ptr(clearedStatus)
+	case bulkUnreview:
+		update.Status = ptr(unclearedStatus)
+	case bulkAddTags:
+		update.Tags = ptr(mergeTagIDs(item.t.Tags, opts.tagIDs, nil))
+	case bulkAppendNotes:
+		update.Notes = ptr(appendNote(item.t.Notes, opts.notes))
+	case bulkApplyRules, bulkGroup:
+		// rule updates are built by applyRulesToVisible, and groups are created in a single
+		// request by groupMarkedTransactions
+		return nil
+	}
+	return update
+}
+
+// appendNote appends note to the existing notes, separated by a space.
+func appendNote(notes, note string) string {
+	note = strings.TrimSpace(note)
+	if notes == "" {
+		return note
+	}
+	if note == "" {
+		return notes
+	}
+	return notes + " " + note
+}
+
+// markedTransactionItems returns the marked transactions in list order.
+func markedTransactionItems(items []list.Item) []transactionItem {
+	var marked []transactionItem
+	for _, item := range items {
+		if t, ok := item.(transactionItem); ok && t.marked {
+			marked = append(marked, t)
+		}
+	}
+	return marked
+}
+
+// setTransactionItem replaces the item for the same transaction in the list and in
+// originalTransactions so marks survive the uncleared and uncategorized filters.
+func setTransactionItem(m *model, item transactionItem) tea.Cmd {
+	for i, li := range m.originalTransactions {
+		if t, ok := li.(transactionItem); ok && t.t.ID == item.t.ID {
+			m.originalTransactions[i] = item
+		}
+	}
+
+	for i, li := range m.transactions.Items() {
+		if t, ok := li.(transactionItem); ok && t.t.ID == item.t.ID {
+			return m.transactions.SetItem(i, item)
+		}
+	}
+	return nil
+}
+
+// toggleMark marks or unmarks the selected transaction and moves to the next one.
+func toggleMark(m model) (tea.Model, tea.Cmd) {
+	t, ok := m.transactions.SelectedItem().(transactionItem)
+	if !ok {
+		return m, nil
+	}
+
+	t.marked = !t.marked
+	cmd := setTransactionItem(&m, t)
+	m.transactionsStats = newTransactionStats(m.transactions.Items())
+	m.transactions.CursorDown()
+	return m, cmd
+}
+
+// toggleMarkAll marks every visible transaction, or unmarks them if they are all marked.
+func toggleMarkAll(m model) (tea.Model, tea.Cmd) {
+	visible := m.transactions.VisibleItems()
+	mark := len(markedTransactionItems(visible)) != len(visible)
+
+	var cmds []tea.Cmd
+	for _, item := range visible {
+		t, ok := item.(transactionItem)
+		if !ok || t.marked == mark {
+			continue
+		}
+		t.marked = mark
+		cmds = append(cmds, setTransactionItem(&m, t))
+	}
+
+	m.transactionsStats = newTransactionStats(m.transactions.Items())
+	return m, tea.Batch(cmds...)
+}
+
+// showBulkActionForm opens the bulk action menu for the marked transactions.
+func showBulkActionForm(m model) (tea.Model, tea.Cmd) {
+	marked := markedTransactionItems(m.originalTransactions)
+	if len(marked) == 0 {
+		return m, m.transactions.NewStatusMessage("No transactions marked, press space to mark")
+	}
+
+	m.bulkActionForm = m.newBulkActionForm(len(marked))
+	m.previousSessionState = m.sessionState
+	m.sessionState = bulkActions
+	return m, m.bulkActionForm.Init()
+}
+
+func (m model) newBulkActionForm(count int) *huh.Form {
+	action := bulkCategorize
+
+	actionOpts := []huh.Option[bulkAction]{
+		huh.NewOption("Categorize", bulkCategorize),
+		huh.NewOption("Review (mark cleared)", bulkReview),
+		huh.NewOption("Unreview (mark uncleared)", bulkUnreview),
+		huh.NewOption("Add tags", bulkAddTags),
+		huh.NewOption("Append notes", bulkAppendNotes),
+	}
+	if count >= minGroupTransactions {
+		actionOpts = append(actionOpts, huh.NewOption("Group into one transaction", bulkGroup))
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[bulkAction]().
+				Title(fmt.Sprintf("Action for %d marked transactions", count)).
+				Key("action").
+				Options(actionOpts...).
+				Value(&action),
+		),
+		huh.NewGroup(
+			huh.NewInput().Title("Payee").Key("payee").Description("The payee for the group transaction").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("payee cannot be empty")
+					}
+					return nil
+				}),
+		).WithHideFunc(func() bool { return action != bulkGroup }),
+		huh.NewGroup(
+			huh.NewSelect[int64]().Title("Category").Key("category").
+				Height(categoryFormHeight).Options(m.generateCategoryOptions()...),
+		).WithHideFunc(func() bool { return action != bulkCategorize && action != bulkGroup }),
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().Title("Tags").Key("tags").
+				Description("Tags to add to each transaction").Options(m.generateTagOptions()...),
+		).WithHideFunc(func() bool { return action != bulkAddTags }),
+		huh.NewGroup(
+			huh.NewInput().Title("Notes").Key("notes").
+				Description("Text to append to the notes of each transaction"),
+		).WithHideFunc(func() bool { return action != bulkAppendNotes }),
+		huh.NewGroup(
+			huh.NewInput().Title("Notes").Key("group_notes").
+				Description("Optional notes for the group transaction"),
+		).WithHideFunc(func() bool { return action != bulkGroup }),
+		huh.NewGroup(
+			huh.NewConfirm().Title(fmt.Sprintf("Apply to %d transactions?", count)).Key("confirm"),
+		),
+	).WithShowHelp(true).WithShowErrors(true)
+}
+
+func (m model) handleBulkActionFormState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.bulkActionForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.bulkActionForm = f
+	} else {
+		log.Debug("bulkActionForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.bulkActionForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	m.previousSessionState = m.sessionState
+	if !m.bulkActionForm.GetBool("confirm") {
+		m.sessionState = transactions
+		return m, m.transactions.NewStatusMessage("Bulk action cancelled")
+	}
+
+	action, _ := m.bulkActionForm.Get("action").(bulkAction)
+	opts := bulkOptions{notes: m.bulkActionForm.GetString("notes")}
+	opts.categoryID, _ = m.bulkActionForm.Get("category").(int64)
+	opts.tagIDs, _ = m.bulkActionForm.Get("tags").([]int)
+
+	if action == bulkGroup {
+		m.sessionState = transactions
+		return m, m.groupMarkedTransactions(
+			m.bulkActionForm.GetString("payee"), opts.categoryID, m.bulkActionForm.GetString("group_notes"))
+	}
+
+	job := &bulkJob{action: action}
+	for _, item := range markedTransactionItems(m.originalTransactions) {
+		if update := newBulkUpdate(action, item, opts); update != nil {
+			job.items = append(job.items, item)
+			job.updates = append(job.updates, update)
+		}
+	}
+	if len(job.items) == 0 {
+		m.sessionState = transactions
+		return m, m.transactions.NewStatusMessage(fmt.Sprintf("Nothing to %s", action))
+	}
+
+	log.Debug("starting bulk action", "action", action, "count", len(job.items))
+
+	m.bulkJob = job
+	m.sessionState = bulkUpdating
+	return m, m.runBulkUpdate(0)
+}
+
+// runBulkUpdate updates the transaction at index of the current bulk job.
+func (m model) runBulkUpdate(index int) tea.Cmd {
+	job := m.bulkJob
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), transactionLoadTimeout)
+		defer cancel()
+
+		item := job.items[index]
+		resp, err := updateTransaction(ctx, m.lmc, item.t.ID, job.updates[index])
+		if err == nil && !resp.Updated {
+			err = errors.New("transaction not updated")
+		}
+		if err != nil {
+			log.Debug("bulk update failed", "transaction", item.t.ID, "error", err)
+		}
+		return bulkUpdateMsg{index: index, err: err}
+	}
+}
+
+func (m model) handleBulkUpdateMsg(msg bulkUpdateMsg) (tea.Model, tea.Cmd) {
+	job := m.bulkJob
+	if job == nil || msg.index != job.next {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		job.failures = append(job.failures, bulkFailure{item: job.items[msg.index], err: msg.err})
+	}
+
+	job.next++
+	if job.running() {
+		return m, m.runBulkUpdate(job.next)
+	}
+
+	log.Debug("bulk action finished", "action", job.action, "failures", len(job.failures))
+	return m, nil
+}
+
+// rulesPreview holds the changes the rules make to the visible transactions until they
+// are confirmed.
+type rulesPreview struct {
+	job     *bulkJob
+	changes []ruleChange
+}
+
+// applyRulesToVisible previews the changes the configured rules make to the visible
+// transactions. Once confirmed they are applied with the bulk runner so progress and
+// failures are shown the same way.
+func applyRulesToVisible(m model) (tea.Model, tea.Cmd) {
+	if len(m.config.Rules) == 0 {
+		return m, m.transactions.NewStatusMessage("No rules configured, add [[rules]] to the config file")
+	}
+
+	tags := make([]*lm.Tag, 0, len(m.tags))
+	for _, tag := range m.tags {
+		tags = append(tags, tag)
+	}
+
+	engine, err := rules.Compile(m.config.Rules, m.categories, tags)
+	if err != nil {
+		return m, m.transactions.NewStatusMessage(fmt.Sprintf("Invalid rule %s", err.Error()))
+	}
+
+	job := &bulkJob{action: bulkApplyRules}
+	var matches []ruleMatch
+	for _, item := range m.transactions.VisibleItems() {
+		t, ok := item.(transactionItem)
+		if !ok {
+			continue
+		}
+		for _, match := range matchRules(engine, []*lm.Transaction{t.t}) {
+			job.items = append(job.items, t)
+			job.updates = append(job.updates, match.update)
+			matches = append(matches, match)
+		}
+	}
+
+	if len(job.items) == 0 {
+		return m, m.transactions.NewStatusMessage("No rules matched the visible transactions")
+	}
+
+	log.Debug("previewing rules", "rules", engine.Len(), "matches", len(job.items))
+
+	m.rulesPreview = &rulesPreview{job: job, changes: ruleChanges(matches, newUpdateNames(m.categories, tags))}
+	m.rulesPreviewForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().Title(fmt.Sprintf("Apply rules to %d transactions?", len(job.items))).Key("confirm"),
+		),
+	).WithShowHelp(true)
+	m.previousSessionState = m.sessionState
+	m.sessionState = rulesPreviewState
+	return m, m.rulesPreviewForm.Init()
+}
+
+func (m model) handleRulesPreviewState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.rulesPreviewForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.rulesPreviewForm = f
+	} else {
+		log.Debug("rulesPreviewForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.rulesPreviewForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	preview := m.rulesPreview
+	m.rulesPreview = nil
+	m.previousSessionState = m.sessionState
+	if !m.rulesPreviewForm.GetBool("confirm") {
+		m.sessionState = transactions
+		return m, m.transactions.NewStatusMessage("Rules not applied")
+	}
+
+	log.Debug("applying rules", "matches", len(preview.job.items))
+
+	m.bulkJob = preview.job
+	m.sessionState = bulkUpdating
+	return m, m.runBulkUpdate(0)
+}
+
+// rulesPreviewView lists the changes the rules make, with the payee of each transaction,
+// above the confirmation.
+func rulesPreviewView(m model) string {
+	preview := m.rulesPreview
+	if preview == nil {
+		return "No rules to apply"
+	}
+
+	styles := createDetailedTransactionStyles(m.theme)
+	lines := []string{styles.headerStyle.Render("Apply rules"), ""}
+	for i, c := range preview.changes {
+		if i == rulesPreviewMaxRows {
+			lines = append(lines, fmt.Sprintf("  ... and %d more changes", len(preview.changes)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s: %s %s → %s (%s)",
+			c.Date, c.Payee, c.Field, cmp.Or(c.Before, "-"), c.After, c.Rule))
+	}
+	lines = append(lines, "", m.rulesPreviewForm.View())
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// updateBulkUpdating handles keys once the bulk job has finished.
+func updateBulkUpdating(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
+		return finishBulkJob(&m)
+	}
+	return m, nil
+}
+
+// finishBulkJob returns to the transactions list and reloads it, which also clears the marks.
+func finishBulkJob(m *model) (tea.Model, tea.Cmd) {
+	if m.bulkJob != nil && m.bulkJob.running() {
+		return m, nil
+	}
+
+	job := m.bulkJob
+	m.bulkJob = nil
+	m.previousSessionState = m.sessionState
+	m.sessionState = transactions
+
+	status := "Bulk action finished"
+	if job != nil {
+		status = fmt.Sprintf("Bulk %s: %d updated, %d failed",
+			job.action, len(job.items)-len(job.failures), len(job.failures))
+	}
+	return m, tea.Batch(m.getTransactions, m.transactions.NewStatusMessage(status))
+}
+
+// renderProgressBar renders a text progress bar of the given width.
+func renderProgressBar(done, total, width int) string {
+	filled := width
+	if total > 0 {
+		filled = done * width / total
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+func bulkUpdatingView(m model) string {
+	job := m.bulkJob
+	if job == nil {
+		return "No bulk action in progress"
+	}
+
+	styles := createDetailedTransactionStyles(m.theme)
+	header := styles.headerStyle.Render(fmt.Sprintf("Bulk %s", job.action))
+	bar := renderProgressBar(job.next, len(job.items), bulkProgressWidth)
+	progress := fmt.Sprintf("%s %d/%d",
+		lipgloss.NewStyle().Foreground(m.theme.Primary).Render(bar),
+		job.next,
+		len(job.items),
+	)
+
+	lines := []string{header, progress}
+	if job.running() {
+		lines = append(lines, styles.instructionStyle.Render("Updating transactions..."))
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
+	summary := fmt.Sprintf("%d of %d transactions updated", len(job.items)-len(job.failures), len(job.items))
+	lines = append(lines, "", summary)
+	for _, f := range job.failures {
+		lines = append(lines, m.styles.errorStyle.Render(
+			fmt.Sprintf("  %s (%d): %s", f.item.t.Payee, f.item.t.ID, f.err.Error()),
+		))
+	}
+	lines = append(lines, styles.instructionStyle.Render("Press 'enter' or 'esc' to return to transactions"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	lm "github.com/icco/lunchmoney"
+
+	"github.com/Rshep3087/lunchtui/rules"
+)
+
+func TestNewBulkUpdate(t *testing.T) {
+	item := transactionItem{t: &lm.Transaction{
+		ID:    1,
+		Notes: "lunch",
+		Tags:  []lm.Tag{{ID: 1, Name: "food"}},
+	}}
+	opts := bulkOptions{categoryID: 20, tagIDs: []int{1, 2}, notes: " team "}
+
+	tests := []struct {
+		name     string
+		action   bulkAction
+		expected *transactionUpdate
+	}{
+		{
+			name:   "categorize also reviews",
+			action: bulkCategorize,
+			expected: &transactionUpdate{UpdateTransaction: lm.UpdateTransaction{
+				CategoryID: ptr(20),
+				Status:     ptr(clearedStatus),
+			}},
+		},
+		{
+			name:   "review",
+			action: bulkReview,
+			expected: &transactionUpdate{UpdateTransaction: lm.UpdateTransaction{
+				Status: ptr(clearedStatus),
+			}},
+		},
+		{
+			name:   "unreview",
+			action: bulkUnreview,
+			expected: &transactionUpdate{UpdateTransaction: lm.UpdateTransaction{
+				Status: ptr(unclearedStatus),
+			}},
+		},
+		{
+			name:     "add tags keeps existing tags",
+			action:   bulkAddTags,
+			expected: &transactionUpdate{Tags: &[]int{1, 2}},
+		},
+		{
+			name:     "apply rules is not a single update",
+			action:   bulkApplyRules,
+			expected: nil,
+		},
+		{
+			name:     "group is not a single update",
+			action:   bulkGroup,
+			expected: nil,
+		},
+		{
+			name:   "append notes",
+			action: bulkAppendNotes,
+			expected: &transactionUpdate{UpdateTransaction: lm.UpdateTransaction{
+				Notes: ptr("lunch team"),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.DeepEqual(t, tt.expected, newBulkUpdate(tt.action, item, opts))
+		})
+	}
+}
+
+func TestAppendNote(t *testing.T) {
+	be.Equal(t, "new", appendNote("", "new"))
+	be.Equal(t, "old", appendNote("old", "  "))
+	be.Equal(t, "old new", appendNote("old", "new"))
+}
+
+func TestRenderProgressBar(t *testing.T) {
+	be.Equal(t, "[░░░░]", renderProgressBar(0, 4, 4))
+	be.Equal(t, "[██░░]", renderProgressBar(2, 4, 4))
+	be.Equal(t, "[████]", renderProgressBar(4, 4, 4))
+	be.Equal(t, "[████]", renderProgressBar(0, 0, 4))
+}
+
+func TestToggleMarkAll(t *testing.T) {
+	items := []list.Item{
+		transactionItem{t: &lm.Transaction{ID: 1}},
+		transactionItem{t: &lm.Transaction{ID: 2}, marked: true},
+	}
+	m := model{
+		transactions:         list.New(items, list.NewDefaultDelegate(), 0, 0),
+		originalTransactions: append([]list.Item{}, items...),
+	}
+
+	// marks everything when some items are unmarked
+	result, _ := toggleMarkAll(m)
+	m = result.(model)
+	be.Equal(t, 2, len(markedTransactionItems(m.transactions.Items())))
+	be.Equal(t, 2, len(markedTransactionItems(m.originalTransactions)))
+	be.Equal(t, 2, m.transactionsStats.marked)
+
+	// unmarks everything when all items are marked
+	result, _ = toggleMarkAll(m)
+	m = result.(model)
+	be.Equal(t, 0, len(markedTransactionItems(m.transactions.Items())))
+	be.Equal(t, 0, len(markedTransactionItems(m.originalTransactions)))
+}
+
+func TestHandleBulkUpdateMsg(t *testing.T) {
+	job := &bulkJob{
+		action: bulkReview,
+		items: []transactionItem{
+			{t: &lm.Transaction{ID: 1}},
+			{t: &lm.Transaction{ID: 2}},
+		},
+		updates: []*transactionUpdate{{}, {}},
+	}
+	m := model{bulkJob: job, sessionState: bulkUpdating}
+
+	// the next transaction is updated after each result
+	result, cmd := m.handleBulkUpdateMsg(bulkUpdateMsg{index: 0, err: errors.New("boom")})
+	be.Nonzero(t, cmd)
+	be.True(t, job.running())
+
+	// stale results are ignored
+	_, cmd = result.(model).handleBulkUpdateMsg(bulkUpdateMsg{index: 0})
+	be.Zero(t, cmd)
+
+	_, cmd = result.(model).handleBulkUpdateMsg(bulkUpdateMsg{index: 1})
+	be.Zero(t, cmd)
+	be.False(t, job.running())
+	be.Equal(t, 1, len(job.failures))
+	be.Equal(t, int64(1), job.failures[0].item.t.ID)
+}
+
+func TestApplyRulesToVisible(t *testing.T) {
+	items := []list.Item{
+		transactionItem{t: &lm.Transaction{ID: 1, Date: "2024-01-02", Payee: "Cafe", Status: unclearedStatus}},
+		transactionItem{t: &lm.Transaction{ID: 2, Date: "2024-01-03", Payee: "Store", Status: unclearedStatus}},
+	}
+	m := model{
+		transactions: list.New(items, list.NewDefaultDelegate(), 0, 0),
+		categories:   []*lm.Category{{ID: 5, Name: "Coffee"}},
+		config:       Config{Rules: []rules.Rule{{Name: "coffee", Payee: "cafe", Category: "Coffee"}}},
+		sessionState: transactions,
+	}
+
+	// the changes are previewed before anything is updated
+	result, _ := applyRulesToVisible(m)
+	m = result.(model)
+	be.Equal(t, rulesPreviewState, m.sessionState)
+	be.Zero(t, m.bulkJob)
+	be.Equal(t, 1, len(m.rulesPreview.job.items))
+	be.In(t, "2024-01-02 Cafe: category Uncategorized → Coffee (coffee)", rulesPreviewView(m))
+
+	// escape returns to the transactions without applying the rules
+	_, _ = handleEscape(tea.KeyMsg{Type: tea.KeyEsc}, &m)
+	be.Equal(t, transactions, m.sessionState)
+	be.Zero(t, m.bulkJob)
+	be.Zero(t, m.rulesPreview)
+}
@@ -0,0 +1,194 @@
+// Package cache stores Lunch Money API responses on disk so data can be shown
+// before the API answers and without a network connection.
+//
+// Entries are JSON files grouped by resource, for example
+// `transactions/2025-01-01_2025-01-31.json`, and each records when it was fetched
+// so the freshness of every period is tracked separately.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Resources cached by lunchtui.
+const (
+	Transactions      = "transactions"
+	Categories        = "categories"
+	Tags              = "tags"
+	Accounts          = "accounts"
+	Budgets           = "budgets"
+	RecurringExpenses = "recurring_expenses"
+	User              = "user"
+)
+
+// AllKey is the key of resources that are not split by period.
+const AllKey = "all"
+
+// ErrNotCached is returned when an entry does not exist.
+var ErrNotCached = errors.New("not cached")
+
+// accountDirLength is the number of hex characters of the token hash used as directory name.
+const accountDirLength = 16
+
+// invalidatedFile records when the entries of a resource were last invalidated.
+const invalidatedFile = "invalidated"
+
+// Entry is a cached API response.
+type Entry[T any] struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Data      T         `json:"data"`
+
+	// invalidated is set when the resource changed after the entry was fetched
+	invalidated bool
+}
+
+// Fresh reports whether the entry was fetched less than maxAge ago and the
+// resource has not been invalidated since.
+func (e Entry[T]) Fresh(maxAge time.Duration) bool {
+	return !e.invalidated && time.Since(e.FetchedAt) < maxAge
+}
+
+// Invalidated reports whether the resource changed after the entry was fetched.
+func (e Entry[T]) Invalidated() bool {
+	return e.invalidated
+}
+
+// Store reads and writes entries below a directory. A nil Store caches nothing.
+type Store struct {
+	dir string
+}
+
+// New returns a store that keeps its entries in dir.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the cache directory for an API token inside the user cache
+// directory. The token is hashed so different budgets never share entries.
+func DefaultDir(token string) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("find user cache directory: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	return filepath.Join(base, "lunchtui", hex.EncodeToString(sum[:])[:accountDirLength]), nil
+}
+
+// PeriodKey returns the key of an entry covering the dates from start to end (YYYY-MM-DD).
+// Variants, such as the sign used for debits, are appended so they are cached separately.
+func PeriodKey(start, end string, variants ...string) string {
+	return strings.Join(append([]string{start, end}, variants...), "_")
+}
+
+// Dir returns the directory of the store, or "" for a nil store.
+func (s *Store) Dir() string {
+	if s == nil {
+		return ""
+	}
+	return s.dir
+}
+
+func (s *Store) path(resource, key string) string {
+	return filepath.Join(s.dir, resource, key+".json")
+}
+
+// Load reads the entry of a resource. It returns ErrNotCached when there is no entry.
+func Load[T any](s *Store, resource, key string) (Entry[T], error) {
+	var entry Entry[T]
+	if s == nil {
+		return entry, ErrNotCached
+	}
+
+	data, err := os.ReadFile(s.path(resource, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return entry, ErrNotCached
+	}
+	if err != nil {
+		return entry, fmt.Errorf("read %s cache: %w", resource, err)
+	}
+
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return entry, fmt.Errorf("decode %s cache: %w", resource, err)
+	}
+
+	if invalidatedAt, ok := s.invalidatedAt(resource); ok {
+		entry.invalidated = !entry.FetchedAt.After(invalidatedAt)
+	}
+	return entry, nil
+}
+
+// Save writes the entry of a resource, fetched now. The file is replaced atomically
+// so concurrent readers never see a partial entry.
+func Save[T any](s *Store, resource, key string, data T) error {
+	if s == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(Entry[T]{FetchedAt: time.Now(), Data: data})
+	if err != nil {
+		return fmt.Errorf("encode %s cache: %w", resource, err)
+	}
+
+	path := s.path(resource, key)
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create %s cache directory: %w", resource, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write %s cache: %w", resource, err)
+	}
+
+	_, err = tmp.Write(encoded)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write %s cache: %w", resource, err)
+	}
+	return nil
+}
+
+// Invalidate marks every entry of a resource as stale. The entries are kept so
+// they can still be shown until they are fetched again, or when offline.
+func (s *Store) Invalidate(resource string) error {
+	if s == nil {
+		return nil
+	}
+
+	dir := filepath.Join(s.dir, resource)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("invalidate %s cache: %w", resource, err)
+	}
+
+	now := time.Now().Format(time.RFC3339Nano)
+	if err := os.WriteFile(filepath.Join(dir, invalidatedFile), []byte(now), 0o600); err != nil {
+		return fmt.Errorf("invalidate %s cache: %w", resource, err)
+	}
+	return nil
+}
+
+// invalidatedAt returns when the entries of a resource were last invalidated.
+func (s *Store) invalidatedAt(resource string) (time.Time, bool) {
+	data, err := os.ReadFile(filepath.Join(s.dir, resource, invalidatedFile))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, string(data))
+	return t, err == nil
+}
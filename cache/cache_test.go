@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+)
+
+type item struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestSaveLoad(t *testing.T) {
+	s := New(t.TempDir())
+
+	_, err := Load[[]item](s, Transactions, PeriodKey("2025-01-01", "2025-01-31"))
+	be.True(t, errors.Is(err, ErrNotCached))
+
+	want := []item{{ID: 1, Name: "Coffee"}, {ID: 2, Name: "Groceries"}}
+	be.NilErr(t, Save(s, Transactions, PeriodKey("2025-01-01", "2025-01-31"), want))
+
+	entry, err := Load[[]item](s, Transactions, PeriodKey("2025-01-01", "2025-01-31"))
+	be.NilErr(t, err)
+	be.AllEqual(t, want, entry.Data)
+	be.True(t, entry.Fresh(time.Minute))
+	be.False(t, entry.Fresh(0))
+
+	// every period is cached separately
+	_, err = Load[[]item](s, Transactions, PeriodKey("2025-02-01", "2025-02-28"))
+	be.True(t, errors.Is(err, ErrNotCached))
+}
+
+func TestInvalidate(t *testing.T) {
+	s := New(t.TempDir())
+	be.NilErr(t, Save(s, Categories, AllKey, []item{{ID: 1}}))
+	be.NilErr(t, Save(s, Tags, AllKey, []item{{ID: 2}}))
+
+	be.NilErr(t, s.Invalidate(Categories))
+
+	// invalidated entries are kept, but no longer fresh
+	categories, err := Load[[]item](s, Categories, AllKey)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(categories.Data))
+	be.True(t, categories.Invalidated())
+	be.False(t, categories.Fresh(time.Hour))
+
+	tags, err := Load[[]item](s, Tags, AllKey)
+	be.NilErr(t, err)
+	be.False(t, tags.Invalidated())
+
+	// saving again makes the entry fresh
+	be.NilErr(t, Save(s, Categories, AllKey, []item{{ID: 1}}))
+	categories, err = Load[[]item](s, Categories, AllKey)
+	be.NilErr(t, err)
+	be.True(t, categories.Fresh(time.Hour))
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+	be.NilErr(t, Save(s, User, AllKey, item{ID: 1}))
+	be.NilErr(t, s.Invalidate(User))
+
+	_, err := Load[item](s, User, AllKey)
+	be.True(t, errors.Is(err, ErrNotCached))
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	first, err := DefaultDir("first-token")
+	be.NilErr(t, err)
+	second, err := DefaultDir("second-token")
+	be.NilErr(t, err)
+
+	be.Unequal(t, first, second)
+	be.In(t, "lunchtui", first)
+	be.NotIn(t, "first-token", first)
+}
+
+func TestPeriodKey(t *testing.T) {
+	be.Equal(t, "2025-01-01_2025-01-31", PeriodKey("2025-01-01", "2025-01-31"))
+	be.Equal(t, "2025-01-01_2025-01-31_negative", PeriodKey("2025-01-01", "2025-01-31", "negative"))
+}
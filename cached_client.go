@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/cache"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"golang.org/x/sync/errgroup"
+)
+
+// errOffline is returned for API requests made in offline mode.
+var errOffline = errors.New("the Lunch Money API is not available in offline mode")
+
+// cachedClient reads Lunch Money data through the local cache. Online, reads fetch
+// from the API and update the cache. Offline, reads are served from the cache only.
+type cachedClient struct {
+	lmc   *lm.Client
+	store *cache.Store
+	// offline serves every read from the cache
+	offline bool
+	// maxAge is how long cached data is used without refreshing it
+	maxAge time.Duration
+}
+
+func newCachedClient(lmc *lm.Client, store *cache.Store, offline bool, maxAge time.Duration) *cachedClient {
+	return &cachedClient{lmc: lmc, store: store, offline: offline, maxAge: maxAge}
+}
+
+// newCacheStore returns the cache for an API token, in dir when it is set or in the user
+// cache directory otherwise. It returns nil, which caches nothing, without a cache directory.
+func newCacheStore(dir, token string) *cache.Store {
+	if dir != "" {
+		return cache.New(dir)
+	}
+
+	dir, err := cache.DefaultDir(token)
+	if err != nil {
+		log.Warn("caching disabled", "error", err)
+		return nil
+	}
+	return cache.New(dir)
+}
+
+// isOffline reports whether reads are served from the cache only.
+func (c *cachedClient) isOffline() bool {
+	return c != nil && c.offline
+}
+
+// invalidate marks the cached data of a resource as stale so the next read fetches it.
+func (c *cachedClient) invalidate(resource string) {
+	if c == nil {
+		return
+	}
+	if err := c.store.Invalidate(resource); err != nil {
+		log.Warn("failed to invalidate cache", "resource", resource, "error", err)
+	}
+}
+
+// cachedQuery is an API read and the cache entry it is stored in.
+type cachedQuery[T any] struct {
+	resource string
+	key      string
+	fetch    func(ctx context.Context) (T, error)
+}
+
+// cachedAccounts holds the plaid accounts and assets, which are always fetched together.
+type cachedAccounts struct {
+	PlaidAccounts []*lm.PlaidAccount `json:"plaid_accounts"`
+	Assets        []*lm.Asset        `json:"assets"`
+}
+
+// fetchCached runs the query against the API and caches the result. Offline it returns
+// the cached result instead, or an error wrapping cache.ErrNotCached.
+func fetchCached[T any](ctx context.Context, c *cachedClient, q cachedQuery[T]) (T, error) {
+	if c.offline {
+		entry, err := cache.Load[T](c.store, q.resource, q.key)
+		if errors.Is(err, cache.ErrNotCached) {
+			return entry.Data, fmt.Errorf("no cached %s, run lunchtui without --offline first: %w", q.resource, err)
+		}
+		return entry.Data, err
+	}
+
+	data, err := q.fetch(ctx)
+	if err != nil {
+		return data, err
+	}
+
+	if err = cache.Save(c.store, q.resource, q.key, data); err != nil {
+		log.Warn("failed to cache data", "resource", q.resource, "error", err)
+	}
+	return data, nil
+}
+
+// peekCached returns the cached result of the query without calling the API.
+func peekCached[T any](c *cachedClient, q cachedQuery[T]) (cache.Entry[T], bool) {
+	entry, err := cache.Load[T](c.store, q.resource, q.key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotCached) {
+			log.Warn("failed to read cached data", "resource", q.resource, "error", err)
+		}
+		return entry, false
+	}
+	return entry, true
+}
+
+// cacheInfo describes where the data of a message came from.
+type cacheInfo struct {
+	// cachedAt is when cached data was fetched, zero for data from the API
+	cachedAt time.Time
+	// refresh fetches newer data in the background when the cached data is stale
+	refresh tea.Cmd
+	// background is set on the data fetched by a refresh
+	background bool
+}
+
+// loadCached returns a command that sends the cached result of the query right away and
+// refreshes it in the background when it is stale. Without cached data it waits for the API.
+func loadCached[T any](c *cachedClient, q cachedQuery[T], newMsg func(T, cacheInfo) tea.Msg) tea.Cmd {
+	fetch := func(background bool) tea.Cmd {
+		return func() tea.Msg {
+			data, err := fetchCached(context.Background(), c, q)
+			switch {
+			case is401Error(err):
+				return handleAuthError(err)
+			case errors.Is(err, cache.ErrNotCached):
+				// offline without cached data, show nothing rather than loading forever
+				log.Debug("no cached data", "resource", q.resource, "key", q.key)
+			case err != nil:
+				log.Error("failed to load data", "resource", q.resource, "error", err)
+				return nil
+			}
+			return newMsg(data, cacheInfo{background: background})
+		}
+	}
+
+	return func() tea.Msg {
+		// data known to have changed is not worth showing, unless it is all there is
+		entry, ok := peekCached(c, q)
+		if !ok || (entry.Invalidated() && !c.offline) {
+			return fetch(false)()
+		}
+
+		info := cacheInfo{cachedAt: entry.FetchedAt}
+		if !c.offline && !entry.Fresh(c.maxAge) {
+			log.Debug("refreshing stale cached data", "resource", q.resource, "key", q.key)
+			info.refresh = fetch(true)
+		}
+		return newMsg(entry.Data, info)
+	}
+}
+
+func (c *cachedClient) categoriesQuery() cachedQuery[[]*lm.Category] {
+	return cachedQuery[[]*lm.Category]{resource: cache.Categories, key: cache.AllKey, fetch: c.lmc.GetCategories}
+}
+
+func (c *cachedClient) tagsQuery() cachedQuery[[]*lm.Tag] {
+	return cachedQuery[[]*lm.Tag]{resource: cache.Tags, key: cache.AllKey, fetch: c.lmc.GetTags}
+}
+
+func (c *cachedClient) userQuery() cachedQuery[*lm.User] {
+	return cachedQuery[*lm.User]{resource: cache.User, key: cache.AllKey, fetch: c.lmc.GetUser}
+}
+
+func (c *cachedClient) recurringExpensesQuery() cachedQuery[[]*lm.RecurringExpense] {
+	return cachedQuery[[]*lm.RecurringExpense]{
+		resource: cache.RecurringExpenses,
+		key:      cache.AllKey,
+		fetch: func(ctx context.Context) ([]*lm.RecurringExpense, error) {
+			return c.lmc.GetRecurringExpenses(ctx, nil)
+		},
+	}
+}
+
+func (c *cachedClient) accountsQuery() cachedQuery[cachedAccounts] {
+	return cachedQuery[cachedAccounts]{
+		resource: cache.Accounts,
+		key:      cache.AllKey,
+		fetch: func(ctx context.Context) (cachedAccounts, error) {
+			var accounts cachedAccounts
+			g, ctx := errgroup.WithContext(ctx)
+			g.Go(func() error {
+				var err error
+				accounts.PlaidAccounts, err = c.lmc.GetPlaidAccounts(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to fetch plaid accounts: %w", err)
+				}
+				return nil
+			})
+			g.Go(func() error {
+				var err error
+				accounts.Assets, err = c.lmc.GetAssets(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to fetch assets: %w", err)
+				}
+				return nil
+			})
+			return accounts, g.Wait()
+		},
+	}
+}
+
+// transactionsQuery returns the transactions between two dates. Debits as negative
+// changes the amounts, so both settings are cached separately.
+func (c *cachedClient) transactionsQuery(
+	startDate, endDate string,
+	debitsAsNegative bool,
+) cachedQuery[[]*lm.Transaction] {
+	var variants []string
+	if debitsAsNegative {
+		variants = append(variants, "debits_as_negative")
+	}
+
+	return cachedQuery[[]*lm.Transaction]{
+		resource: cache.Transactions,
+		key:      cache.PeriodKey(startDate, endDate, variants...),
+		fetch: func(ctx context.Context) ([]*lm.Transaction, error) {
+			return c.lmc.GetTransactions(ctx, newTransactionFilters(startDate, endDate, debitsAsNegative))
+		},
+	}
+}
+
+func (c *cachedClient) budgetsQuery(startDate, endDate string) cachedQuery[[]*lm.Budget] {
+	return cachedQuery[[]*lm.Budget]{
+		resource: cache.Budgets,
+		key:      cache.PeriodKey(startDate, endDate),
+		fetch: func(ctx context.Context) ([]*lm.Budget, error) {
+			return c.lmc.GetBudgets(ctx, &lm.BudgetFilters{StartDate: startDate, EndDate: endDate})
+		},
+	}
+}
+
+// GetCategories fetches the categories through the cache.
+func (c *cachedClient) GetCategories(ctx context.Context) ([]*lm.Category, error) {
+	return fetchCached(ctx, c, c.categoriesQuery())
+}
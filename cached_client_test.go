@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/cache"
+	"github.com/carlmjohnson/be"
+	tea "github.com/charmbracelet/bubbletea"
+	lm "github.com/icco/lunchmoney"
+)
+
+// newTestCachedClient returns a cached client for a fake API that counts the tag requests.
+func newTestCachedClient(t *testing.T, maxAge time.Duration) (*cachedClient, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			requests.Add(1)
+			_, _ = w.Write([]byte(`[{"id": 1, "name": "coffee"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"updated": true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	lmc, err := lm.NewClient("token")
+	be.NilErr(t, err)
+	lmc.Base, err = url.Parse(server.URL)
+	be.NilErr(t, err)
+
+	store := cache.New(t.TempDir())
+	lmc.HTTP.Transport = newCacheInvalidatingTransport(lmc.HTTP.Transport, store)
+	return newCachedClient(lmc, store, false, maxAge), &requests
+}
+
+func TestFetchCached(t *testing.T) {
+	c, requests := newTestCachedClient(t, time.Hour)
+
+	tags, err := fetchCached(t.Context(), c, c.tagsQuery())
+	be.NilErr(t, err)
+	be.Equal(t, "coffee", tags[0].Name)
+	be.Equal(t, int32(1), requests.Load())
+
+	// offline reads never reach the API
+	offline := newCachedClient(c.lmc, c.store, true, time.Hour)
+	tags, err = fetchCached(t.Context(), offline, offline.tagsQuery())
+	be.NilErr(t, err)
+	be.Equal(t, "coffee", tags[0].Name)
+	be.Equal(t, int32(1), requests.Load())
+
+	_, err = fetchCached(t.Context(), offline, offline.categoriesQuery())
+	be.True(t, errors.Is(err, cache.ErrNotCached))
+}
+
+func TestLoadCached(t *testing.T) {
+	newMsg := func(tags []*lm.Tag, info cacheInfo) tea.Msg {
+		return getTagsMsg{cacheInfo: info, tags: tags}
+	}
+
+	t.Run("fetches without cached data", func(t *testing.T) {
+		c, requests := newTestCachedClient(t, time.Hour)
+
+		msg, ok := loadCached(c, c.tagsQuery(), newMsg)().(getTagsMsg)
+		be.True(t, ok)
+		be.Equal(t, 1, len(msg.tags))
+		be.True(t, msg.cachedAt.IsZero())
+		be.True(t, msg.refresh == nil)
+		be.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("uses fresh cached data", func(t *testing.T) {
+		c, requests := newTestCachedClient(t, time.Hour)
+		be.NilErr(t, cache.Save(c.store, cache.Tags, cache.AllKey, []*lm.Tag{{ID: 2, Name: "cached"}}))
+
+		msg, ok := loadCached(c, c.tagsQuery(), newMsg)().(getTagsMsg)
+		be.True(t, ok)
+		be.Equal(t, "cached", msg.tags[0].Name)
+		be.False(t, msg.cachedAt.IsZero())
+		be.True(t, msg.refresh == nil)
+		be.Equal(t, int32(0), requests.Load())
+	})
+
+	t.Run("refreshes stale cached data in the background", func(t *testing.T) {
+		c, requests := newTestCachedClient(t, 0)
+		be.NilErr(t, cache.Save(c.store, cache.Tags, cache.AllKey, []*lm.Tag{{ID: 2, Name: "cached"}}))
+
+		msg, ok := loadCached(c, c.tagsQuery(), newMsg)().(getTagsMsg)
+		be.True(t, ok)
+		be.Equal(t, "cached", msg.tags[0].Name)
+		be.True(t, msg.refresh != nil)
+		be.Equal(t, int32(0), requests.Load())
+
+		refreshed, ok := msg.refresh().(getTagsMsg)
+		be.True(t, ok)
+		be.True(t, refreshed.background)
+		be.Equal(t, "coffee", refreshed.tags[0].Name)
+		be.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("fetches invalidated cached data", func(t *testing.T) {
+		c, requests := newTestCachedClient(t, time.Hour)
+		be.NilErr(t, cache.Save(c.store, cache.Tags, cache.AllKey, []*lm.Tag{{ID: 2, Name: "cached"}}))
+		c.invalidate(cache.Tags)
+
+		msg, ok := loadCached(c, c.tagsQuery(), newMsg)().(getTagsMsg)
+		be.True(t, ok)
+		be.Equal(t, "coffee", msg.tags[0].Name)
+		be.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestCacheInvalidatingTransport(t *testing.T) {
+	c, _ := newTestCachedClient(t, time.Hour)
+	for _, resource := range []string{cache.Transactions, cache.Budgets, cache.Tags} {
+		be.NilErr(t, cache.Save(c.store, resource, cache.AllKey, []int{1}))
+	}
+
+	_, err := updateTransaction(t.Context(), c.lmc, 1, &transactionUpdate{})
+	be.NilErr(t, err)
+
+	for resource, invalidated := range map[string]bool{
+		cache.Transactions: true,
+		cache.Budgets:      true,
+		cache.Tags:         false,
+	} {
+		entry, loadErr := cache.Load[[]int](c.store, resource, cache.AllKey)
+		be.NilErr(t, loadErr)
+		be.Equal(t, invalidated, entry.Invalidated())
+	}
+}
+
+func TestOfflineTransport(t *testing.T) {
+	lmc, err := lm.NewClient("token")
+	be.NilErr(t, err)
+	lmc.HTTP.Transport = offlineTransport{}
+
+	_, err = lmc.GetUser(t.Context())
+	be.True(t, errors.Is(err, errOffline))
+}
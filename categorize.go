@@ -46,10 +46,14 @@ func (m model) getCategoryDescription() string {
 		log.Debug("showing AI recommendation",
 			"category", m.aiRecommendation.CategoryName,
 			"confidence", m.aiRecommendation.Confidence)
-		return fmt.Sprintf("🤖 AI recommends: %s (%.0f%% confidence)\n%s",
+		description := fmt.Sprintf("🤖 AI recommends: %s (%.0f%% confidence)\n%s",
 			m.aiRecommendation.CategoryName,
 			m.aiRecommendation.Confidence,
 			m.aiRecommendation.Reasoning)
+		if examples := describeExamples(m.aiRecommendation.Examples); examples != "" {
+			description += "\n" + examples
+		}
+		return description
 	}
 	if m.aiRecommender != nil && m.aiRecommender.IsEnabled() {
 		log.Debug("showing AI available message")
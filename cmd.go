@@ -1,7 +1,9 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"time"
 
 	"github.com/Rshep3087/lunchtui/config"
 	"github.com/charmbracelet/fang"
@@ -25,12 +28,16 @@ import (
 const (
 	jsonOutputFormat  = "json"
 	tableOutputFormat = "table"
+	csvOutputFormat   = "csv"
+	icsOutputFormat   = "ics"
 )
 
 // Global variables for configuration.
 var (
 	cfgFile string
 	lmc     *lm.Client
+	// lmCache reads through the local cache of Lunch Money data
+	lmCache *cachedClient
 
 	// local variables for root command.
 	showUserInfo bool
@@ -53,8 +60,10 @@ func init() {
 		String("anthropic-api-key", "", "Anthropic API key for AI-powered category recommendations")
 	rootCmd.PersistentFlags().String("api-base-url", "",
 		"the base URL for the Lunch Money API (defaults to library default)")
+	rootCmd.PersistentFlags().Bool("offline", false, "run from cached data without calling the Lunch Money API")
 
 	// root comand flags
+	addPeriodFlags(rootCmd)
 	rootCmd.Flags().BoolVar(&showUserInfo, "show-user-info", false, "show user information in the overview")
 	_ = viper.BindPFlag("show_user_info", rootCmd.Flags().Lookup("show-user-info"))
 
@@ -65,16 +74,25 @@ func init() {
 	_ = viper.BindPFlag("hide_pending_transactions", rootCmd.PersistentFlags().Lookup("hide-pending-transactions"))
 	_ = viper.BindPFlag("ai.anthropic_api_key", rootCmd.PersistentFlags().Lookup("anthropic-api-key"))
 	_ = viper.BindPFlag("api_base_url", rootCmd.PersistentFlags().Lookup("api-base-url"))
+	_ = viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline"))
+
+	viper.SetDefault("ai.history_days", defaultAIHistoryDays)
+	viper.SetDefault("cache_max_age", defaultCacheMaxAge)
 
 	// Bind environment variables
 	_ = viper.BindEnv("token", "LUNCHMONEY_API_TOKEN")
 	_ = viper.BindEnv("ai.anthropic_api_key", "ANTHROPIC_API_KEY")
+	// OPENAI_API_KEY is only read for the default base URL, see newAIConfig
+	_ = viper.BindEnv("ai.api_key", "LUNCHTUI_AI_API_KEY")
 	_ = viper.BindEnv("api_base_url", "LUNCHMONEY_API_BASE_URL")
 
 	rootCmd.AddCommand(transactionCmd)
 	rootCmd.AddCommand(accountsCmd)
 	rootCmd.AddCommand(userCmd)
 	rootCmd.AddCommand(networthCmd)
+	rootCmd.AddCommand(budgetsCmd)
+	rootCmd.AddCommand(recurringCmd)
+	rootCmd.AddCommand(tagsCmd)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -121,6 +139,21 @@ func initConfig() {
 	}
 }
 
+// setupCache creates the cached client for the configured token. Writes through lmc
+// invalidate the cached data they change, and offline lmc cannot reach the API at all.
+func setupCache(lmc *lm.Client) *cachedClient {
+	store := newCacheStore(viper.GetString("cache_dir"), viper.GetString("token"))
+	offline := viper.GetBool("offline")
+	if offline {
+		lmc.HTTP.Transport = newLoggingTransport(offlineTransport{}, log.Default())
+	} else {
+		lmc.HTTP.Transport = newCacheInvalidatingTransport(lmc.HTTP.Transport, store)
+	}
+
+	log.Debug("using cache", "dir", store.Dir(), "offline", offline)
+	return newCachedClient(lmc, store, offline, viper.GetDuration("cache_max_age"))
+}
+
 // rootCmd represents the base command when called without any subcommands.
 var rootCmd = &cobra.Command{
 	Use:   "lunchtui",
@@ -161,12 +194,29 @@ var rootCmd = &cobra.Command{
 			log.SetLevel(log.DebugLevel)
 		}
 
-		categoryService := NewCategoryService(lmc)
+		lmCache = setupCache(lmc)
+
+		categoryService := NewCategoryService(lmCache)
 		cmd.Root().AddCommand(newCategoriesCmd(categoryService))
 
 		return nil
 	},
 	RunE: func(c *cobra.Command, _ []string) error {
+		configuredRules, err := loadRules()
+		if err != nil {
+			return err
+		}
+
+		liabilities, err := loadLiabilityClassifier()
+		if err != nil {
+			return err
+		}
+
+		periodType, period, err := periodFromFlags(c)
+		if err != nil {
+			return err
+		}
+
 		// Start TUI when no subcommands are provided
 		config := Config{
 			Debug:                   viper.GetBool("debug"),
@@ -187,15 +237,41 @@ var rootCmd = &cobra.Command{
 				Text:          viper.GetString("colors.text"),
 				SecondaryText: viper.GetString("colors.secondary_text"),
 			},
-			AI: AIConfig{
-				AnthropicAPIKey: viper.GetString("ai.anthropic_api_key"),
-			},
+			AI:          newAIConfig(),
+			Rules:       configuredRules,
+			Liabilities: liabilities,
+			PeriodType:  periodType,
+			Period:      period,
 		}
 
-		return rootAction(c.Context(), config, lmc)
+		if err = validateAIConfig(config.AI); err != nil {
+			return err
+		}
+
+		return rootAction(c.Context(), config, lmc, lmCache)
 	},
 }
 
+// newAIConfig reads the [ai] section of the config. The OPENAI_API_KEY environment variable
+// is only used with the OpenAI API itself, so the key is never sent to a custom base_url.
+func newAIConfig() AIConfig {
+	config := AIConfig{
+		Provider:             viper.GetString("ai.provider"),
+		BaseURL:              viper.GetString("ai.base_url"),
+		Model:                viper.GetString("ai.model"),
+		APIKey:               viper.GetString("ai.api_key"),
+		AnthropicAPIKey:      viper.GetString("ai.anthropic_api_key"),
+		HistoryDays:          viper.GetInt("ai.history_days"),
+		AutoAcceptConfidence: viper.GetFloat64("ai.auto_accept_confidence"),
+	}
+
+	if config.APIKey == "" && config.Provider == openAIProvider &&
+		cmp.Or(config.BaseURL, defaultOpenAIBaseURL) == defaultOpenAIBaseURL {
+		config.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	return config
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	if err := fang.Execute(context.Background(), rootCmd); err != nil {
@@ -214,6 +290,18 @@ func outputJSON(cmd *cobra.Command, data any) error {
 	return nil
 }
 
+// outputCSV writes the headers and rows as CSV to the command output.
+func outputCSV(cmd *cobra.Command, headers []string, rows [][]string) error {
+	w := csv.NewWriter(cmd.OutOrStdout())
+	if err := w.Write(headers); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV rows: %w", err)
+	}
+	return nil
+}
+
 func createStyledTable(headers ...string) *table.Table {
 	var (
 		purple    = lipgloss.Color("99")
@@ -242,54 +330,44 @@ func createStyledTable(headers ...string) *table.Table {
 		Headers(headers...)
 }
 
+// addPeriodFlags adds the --period, --start and --end flags read by periodFromFlags. They
+// are only added to the commands that show or report on a period.
+func addPeriodFlags(cmd *cobra.Command) {
+	cmd.Flags().String("period", monthlyPeriodType,
+		"period to show: week, month, quarter, year, ytd, last-30-days or last-90-days")
+	cmd.Flags().String("start", "", "start date of a custom period (YYYY-MM-DD)")
+	cmd.Flags().String("end", "", "end date of a custom period (YYYY-MM-DD, defaults to today)")
+}
+
+// periodFromFlags returns the period type and the period selected by the --period, --start
+// and --end flags added by addPeriodFlags, the current month by default.
+func periodFromFlags(cmd *cobra.Command) (string, Period, error) {
+	var periodType string
+	if cmd.Flags().Changed("period") {
+		periodType, _ = cmd.Flags().GetString("period")
+	}
+	start, _ := cmd.Flags().GetString("start")
+	end, _ := cmd.Flags().GetString("end")
+	return parsePeriod(periodType, start, end, time.Now())
+}
+
 // validateOutputFormat validates the output format flag value.
-func validateOutputFormat(cmd *cobra.Command) (string, error) {
+// Commands that support formats beyond table and json pass them as extraFormats.
+func validateOutputFormat(cmd *cobra.Command, extraFormats ...string) (string, error) {
 	outputFormat, _ := cmd.Flags().GetString("output")
-	validFormats := []string{tableOutputFormat, jsonOutputFormat}
+	validFormats := append([]string{tableOutputFormat, jsonOutputFormat}, extraFormats...)
 	if !slices.Contains(validFormats, outputFormat) {
 		return "", fmt.Errorf("invalid output format: %s (must be one of %v)", outputFormat, validFormats)
 	}
 	return outputFormat, nil
 }
 
-// fetchAssetsAndPlaidAccountsParallel fetches assets and plaid accounts in parallel.
+// fetchAssetsAndPlaidAccountsParallel fetches assets and plaid accounts in parallel through the cache.
 // Returns assets, plaid accounts, and any error encountered.
 func fetchAssetsAndPlaidAccountsParallel(ctx context.Context) ([]*lm.Asset, []*lm.PlaidAccount, error) {
-	assetsChan := make(chan []*lm.Asset, 1)
-	plaidAccountsChan := make(chan []*lm.PlaidAccount, 1)
-	errorChan := make(chan error, 2)
-
-	// Fetch assets
-	go func() {
-		assets, assetsError := lmc.GetAssets(ctx)
-		if assetsError != nil {
-			errorChan <- fmt.Errorf("failed to fetch assets: %w", assetsError)
-			return
-		}
-		assetsChan <- assets
-	}()
-
-	// Fetch plaid accounts
-	go func() {
-		plaidAccounts, plaidAccountsErr := lmc.GetPlaidAccounts(ctx)
-		if plaidAccountsErr != nil {
-			errorChan <- fmt.Errorf("failed to fetch plaid accounts: %w", plaidAccountsErr)
-			return
-		}
-		plaidAccountsChan <- plaidAccounts
-	}()
-
-	// Collect results
-	var assets []*lm.Asset
-	var plaidAccounts []*lm.PlaidAccount
-	for range 2 {
-		select {
-		case assets = <-assetsChan:
-		case plaidAccounts = <-plaidAccountsChan:
-		case fetchError := <-errorChan:
-			return nil, nil, fetchError
-		}
+	accounts, err := fetchCached(ctx, lmCache, lmCache.accountsQuery())
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return assets, plaidAccounts, nil
+	return accounts.Assets, accounts.PlaidAccounts, nil
 }
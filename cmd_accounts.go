@@ -1,13 +1,19 @@
 package main
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	lm "github.com/icco/lunchmoney"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Rshep3087/lunchtui/liability"
 )
 
 // Account represents a unified account structure for both assets and plaid accounts.
@@ -21,8 +27,17 @@ type Account struct {
 	InstitutionName string `json:"institution_name"`
 	Status          string `json:"status"`
 	AccountType     string `json:"account_type"` // "asset" or "plaid"
+	// BalanceAsOf is when the balance was last updated
+	BalanceAsOf time.Time `json:"balance_as_of,omitzero"`
+	// LastImport is when transactions were last imported through Plaid
+	LastImport time.Time `json:"last_import,omitzero"`
 }
 
+const (
+	assetAccountType = "asset"
+	plaidAccountType = "plaid"
+)
+
 // convertAssetToAccount converts an Asset to the unified Account structure.
 func convertAssetToAccount(asset *lm.Asset) Account {
 	return Account{
@@ -34,7 +49,8 @@ func convertAssetToAccount(asset *lm.Asset) Account {
 		Currency:        asset.Currency,
 		InstitutionName: asset.InstitutionName,
 		Status:          asset.Status,
-		AccountType:     "asset",
+		AccountType:     assetAccountType,
+		BalanceAsOf:     asset.BalanceAsOf,
 	}
 }
 
@@ -49,7 +65,9 @@ func convertPlaidAccountToAccount(plaidAccount *lm.PlaidAccount) Account {
 		Currency:        plaidAccount.Currency,
 		InstitutionName: plaidAccount.InstitutionName,
 		Status:          plaidAccount.Status,
-		AccountType:     "plaid",
+		AccountType:     plaidAccountType,
+		BalanceAsOf:     plaidAccount.BalanceLastUpdate,
+		LastImport:      plaidAccount.LastImport,
 	}
 }
 
@@ -64,8 +82,12 @@ var accountsCmd = &cobra.Command{
 var accountsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all accounts",
-	Long:  `List all accounts (assets and plaid accounts) with their IDs and details.`,
-	RunE:  accountsListRun,
+	Long: `List all accounts (assets and plaid accounts) with their IDs and details.
+
+Use --id to show the details of one account instead, with its transactions in the period
+selected with --period, --start and --end and the balance after each transaction. An asset
+and a plaid account can share an ID, in which case --type selects one of them.`,
+	RunE: accountsListRun,
 }
 
 func init() {
@@ -73,7 +95,10 @@ func init() {
 	accountsCmd.AddCommand(accountsListCmd)
 
 	// Accounts list flags
+	addPeriodFlags(accountsListCmd)
 	accountsListCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+	accountsListCmd.Flags().Int64("id", 0, "Show the details and transactions of the account with this ID")
+	accountsListCmd.Flags().String("type", "", "Account type of --id: asset or plaid")
 }
 
 func accountsListRun(cmd *cobra.Command, _ []string) error {
@@ -85,25 +110,28 @@ func accountsListRun(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	accountType, _ := cmd.Flags().GetString("type")
+	if accountType != "" && accountType != assetAccountType && accountType != plaidAccountType {
+		return fmt.Errorf("invalid account type: %s (must be 'asset' or 'plaid')", accountType)
+	}
+
 	// Fetch assets and plaid accounts in parallel
 	assets, plaidAccounts, err := fetchAssetsAndPlaidAccountsParallel(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Convert to unified Account structure
-	var accounts []Account
-	for _, asset := range assets {
-		accounts = append(accounts, convertAssetToAccount(asset))
+	if cmd.Flags().Changed("id") {
+		ref := accountRef{}
+		ref.id, _ = cmd.Flags().GetInt64("id")
+		ref.accountType, _ = cmd.Flags().GetString("type")
+		return accountDetailRun(cmd, outputFormat, assets, plaidAccounts, ref)
 	}
-	for _, plaidAccount := range plaidAccounts {
-		accounts = append(accounts, convertPlaidAccountToAccount(plaidAccount))
+	if cmd.Flags().Changed("type") {
+		return errors.New("the --type flag can only be used with --id")
 	}
 
-	// Sort accounts by name for consistent output
-	sort.Slice(accounts, func(i, j int) bool {
-		return accounts[i].Name < accounts[j].Name
-	})
+	accounts := convertAccounts(assets, plaidAccounts)
 
 	// Output based on format
 	switch outputFormat {
@@ -116,6 +144,24 @@ func accountsListRun(cmd *cobra.Command, _ []string) error {
 	}
 }
 
+// convertAccounts converts the assets and plaid accounts to the unified Account structure,
+// sorted by name.
+func convertAccounts(assets []*lm.Asset, plaidAccounts []*lm.PlaidAccount) []Account {
+	accounts := make([]Account, 0, len(assets)+len(plaidAccounts))
+	for _, asset := range assets {
+		accounts = append(accounts, convertAssetToAccount(asset))
+	}
+	for _, plaidAccount := range plaidAccounts {
+		accounts = append(accounts, convertPlaidAccountToAccount(plaidAccount))
+	}
+
+	// Sort accounts by name for consistent output
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+	return accounts
+}
+
 func outputAccountsTable(cmd *cobra.Command, accounts []Account) error {
 	// Create table
 	t := createStyledTable(
@@ -158,3 +204,173 @@ func outputAccountsTable(cmd *cobra.Command, accounts []Account) error {
 
 	return nil
 }
+
+// AccountStatement is an account with its transactions in a period, the output of
+// accounts list --id.
+type AccountStatement struct {
+	Account
+	Liability    bool                 `json:"liability"`
+	StartDate    string               `json:"start_date"`
+	EndDate      string               `json:"end_date"`
+	Transactions []AccountTransaction `json:"transactions"`
+}
+
+// AccountTransaction is a transaction of an account and the balance of the account after it.
+type AccountTransaction struct {
+	ID       int64  `json:"id"`
+	Date     string `json:"date"`
+	Payee    string `json:"payee"`
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+	Category string `json:"category"`
+	Status   string `json:"status"`
+	// Balance is empty when it cannot be worked out from the current balance
+	Balance string `json:"balance,omitempty"`
+}
+
+// findCommandAccount returns the account of accounts list --id and whether it is a liability.
+// Unlike findAccount, an ID shared by an asset and a plaid account is an error unless the
+// account type is set.
+func findCommandAccount(
+	assets []*lm.Asset,
+	plaidAccounts []*lm.PlaidAccount,
+	ref accountRef,
+	classifier liability.Classifier,
+) (Account, bool, error) {
+	if ref.accountType == "" {
+		_, _, isAsset := findAccount(assets, nil, ref, classifier)
+		_, _, isPlaid := findAccount(nil, plaidAccounts, ref, classifier)
+		if isAsset && isPlaid {
+			return Account{}, false, fmt.Errorf(
+				"account %d is both an asset and a plaid account, choose one with --type asset or --type plaid", ref.id)
+		}
+	}
+
+	account, isLiability, ok := findAccount(assets, plaidAccounts, ref, classifier)
+	if !ok {
+		if ref.accountType != "" {
+			return Account{}, false, fmt.Errorf("%s account %d not found", ref.accountType, ref.id)
+		}
+		return Account{}, false, fmt.Errorf("account %d not found", ref.id)
+	}
+	return account, isLiability, nil
+}
+
+func accountDetailRun(
+	cmd *cobra.Command,
+	outputFormat string,
+	assets []*lm.Asset,
+	plaidAccounts []*lm.PlaidAccount,
+	ref accountRef,
+) error {
+	classifier, err := loadLiabilityClassifier()
+	if err != nil {
+		return err
+	}
+
+	account, isLiability, err := findCommandAccount(assets, plaidAccounts, ref, classifier)
+	if err != nil {
+		return err
+	}
+
+	_, period, err := periodFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	debitsAsNegative := viper.GetBool("debits_as_negative")
+	loadPeriod := accountTransactionsPeriod(period, time.Now())
+	ts, err := fetchCached(cmd.Context(), lmCache,
+		lmCache.transactionsQuery(loadPeriod.startDate(), loadPeriod.endDate(), debitsAsNegative),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	entries := accountEntries(ts, account, isLiability, debitsAsNegative, period)
+	detail := newAccountStatement(account, isLiability, period, entries)
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, detail)
+	case tableOutputFormat:
+		return outputAccountStatementTable(cmd, detail)
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+func newAccountStatement(account Account, isLiability bool, period Period, entries []accountEntry) AccountStatement {
+	detail := AccountStatement{
+		Account:      account,
+		Liability:    isLiability,
+		StartDate:    period.startDate(),
+		EndDate:      period.endDate(),
+		Transactions: make([]AccountTransaction, len(entries)),
+	}
+	for i, e := range entries {
+		detail.Transactions[i] = AccountTransaction{
+			ID:       e.t.ID,
+			Date:     e.t.Date,
+			Payee:    e.t.Payee,
+			Amount:   e.t.Amount,
+			Currency: e.t.Currency,
+			Category: transactionCategoryName(e.t),
+			Status:   e.t.Status,
+		}
+		if e.balance != nil {
+			detail.Transactions[i].Balance = formatAmount(e.balance)
+		}
+	}
+	return detail
+}
+
+func outputAccountStatementTable(cmd *cobra.Command, detail AccountStatement) error {
+	out := cmd.OutOrStdout()
+	kind := detail.Type
+	if detail.Subtype != "" {
+		kind += " / " + detail.Subtype
+	}
+	if detail.Liability {
+		kind += " (liability)"
+	}
+
+	fmt.Fprintf(out, "Name: %s\n", detail.Name)
+	fmt.Fprintf(out, "Institution: %s\n", cmp.Or(detail.InstitutionName, "-"))
+	fmt.Fprintf(out, "Type: %s\n", kind)
+	fmt.Fprintf(out, "Balance: %s %s\n", detail.Balance, strings.ToUpper(detail.Currency))
+	fmt.Fprintf(out, "Balance as of: %s\n", formatAccountTime(detail.BalanceAsOf))
+	if detail.AccountType == plaidAccountType {
+		fmt.Fprintf(out, "Last import: %s\n", formatAccountTime(detail.LastImport))
+	}
+	fmt.Fprintf(out, "Status: %s\n\n", detail.Status)
+
+	if len(detail.Transactions) == 0 {
+		fmt.Fprintf(out, "No transactions from %s to %s\n", detail.StartDate, detail.EndDate)
+		return nil
+	}
+
+	t := createStyledTable("ID", "DATE", "PAYEE", "AMOUNT", "BALANCE", "CATEGORY", "STATUS")
+	for _, tr := range detail.Transactions {
+		amount := tr.Amount
+		if parsed, err := parseAmount(tr.Amount, tr.Currency); err == nil {
+			amount = parsed.Display()
+		}
+		balance := "-"
+		if parsed, err := parseAmount(tr.Balance, detail.Currency); err == nil {
+			balance = parsed.Display()
+		}
+		t.Row(
+			strconv.FormatInt(tr.ID, 10),
+			tr.Date,
+			tr.Payee,
+			amount,
+			balance,
+			tr.Category,
+			tr.Status,
+		)
+	}
+	fmt.Fprintln(out, t)
+
+	return nil
+}
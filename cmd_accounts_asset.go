@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+)
+
+// assetTypes are the types Lunch Money accepts for manually managed assets.
+var assetTypes = []string{
+	"cash",
+	"credit",
+	"investment",
+	"real estate",
+	"loan",
+	"vehicle",
+	"cryptocurrency",
+	"employee compensation",
+	"other liability",
+	"other asset",
+}
+
+// accountsAssetCmd represents the accounts asset command.
+var accountsAssetCmd = &cobra.Command{
+	Use:   "asset",
+	Short: "Manually managed asset commands",
+	Long:  `Commands for creating and updating manually managed assets, whose balances are not synced through Plaid.`,
+}
+
+// accountsAssetCreateCmd represents the accounts asset create command.
+var accountsAssetCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a manually managed asset",
+	Long: `Create a manually managed asset, such as a brokerage account or a property. The balance is
+as of today unless --as-of is set, and in your primary currency unless --currency is set.`,
+	Args: cobra.NoArgs,
+	RunE: accountsAssetCreateRun,
+}
+
+// accountsAssetUpdateCmd represents the accounts asset update command.
+var accountsAssetUpdateCmd = &cobra.Command{
+	Use:   "update <id>",
+	Short: "Update a manually managed asset",
+	Long: `Update the balance or other fields of a manually managed asset. Only the fields whose flags
+are set are changed. A new balance is as of today unless --as-of is set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: accountsAssetUpdateRun,
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsAssetCmd)
+	accountsAssetCmd.AddCommand(accountsAssetCreateCmd)
+	accountsAssetCmd.AddCommand(accountsAssetUpdateCmd)
+
+	addAssetFlags(accountsAssetCreateCmd)
+	_ = accountsAssetCreateCmd.MarkFlagRequired("name")
+	_ = accountsAssetCreateCmd.MarkFlagRequired("type")
+	_ = accountsAssetCreateCmd.MarkFlagRequired("balance")
+
+	addAssetFlags(accountsAssetUpdateCmd)
+}
+
+// addAssetFlags adds the flags for the fields of an asset and the output format.
+func addAssetFlags(cmd *cobra.Command) {
+	cmd.Flags().String("name", "", "Name of the asset")
+	cmd.Flags().String("display-name", "", "Display name of the asset")
+	cmd.Flags().String("type", "", "Type of the asset: "+strings.Join(assetTypes, ", "))
+	cmd.Flags().String("subtype", "", "Subtype of the asset, such as retirement or brokerage")
+	cmd.Flags().String("institution", "", "Name of the institution holding the asset")
+	cmd.Flags().String("balance", "", "Current balance of the asset")
+	cmd.Flags().String("as-of", "", "Date the balance is as of (YYYY-MM-DD), today by default")
+	cmd.Flags().String("currency", "", "Currency code of the balance")
+	cmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+}
+
+func accountsAssetCreateRun(cmd *cobra.Command, _ []string) error {
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	update, err := assetUpdateFromFlags(cmd, time.Now())
+	if err != nil {
+		return err
+	}
+	create := &assetCreate{
+		TypeName:        *update.TypeName,
+		SubtypeName:     deref(update.SubtypeName),
+		Name:            *update.Name,
+		DisplayName:     deref(update.DisplayName),
+		Balance:         *update.Balance,
+		BalanceAsOf:     deref(update.BalanceAsOf),
+		Currency:        deref(update.Currency),
+		InstitutionName: deref(update.InstitutionName),
+	}
+
+	log.Debug("creating asset", "asset", create)
+
+	asset, err := createAsset(cmd.Context(), lmc, create)
+	if err != nil {
+		return fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	return outputAsset(cmd, outputFormat, asset)
+}
+
+func accountsAssetUpdateRun(cmd *cobra.Command, args []string) error {
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid asset ID: %s", args[0])
+	}
+
+	update, err := assetUpdateFromFlags(cmd, time.Now())
+	if err != nil {
+		return err
+	}
+	if *update == (lm.UpdateAsset{}) {
+		return errors.New("no fields to update (see --help for available flags)")
+	}
+
+	log.Debug("updating asset", "id", id, "update", update)
+
+	asset, err := lmc.UpdateAsset(cmd.Context(), id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update asset %d: %w", id, err)
+	}
+
+	return outputAsset(cmd, outputFormat, asset)
+}
+
+// assetUpdateFromFlags builds an asset update from the flags that were explicitly set.
+// A new balance is as of now unless --as-of is set.
+func assetUpdateFromFlags(cmd *cobra.Command, now time.Time) (*lm.UpdateAsset, error) {
+	update := &lm.UpdateAsset{
+		Name:            changedStringFlag(cmd, "name"),
+		DisplayName:     changedStringFlag(cmd, "display-name"),
+		TypeName:        changedStringFlag(cmd, "type"),
+		SubtypeName:     changedStringFlag(cmd, "subtype"),
+		InstitutionName: changedStringFlag(cmd, "institution"),
+		Balance:         changedStringFlag(cmd, "balance"),
+		BalanceAsOf:     changedStringFlag(cmd, "as-of"),
+		Currency:        changedStringFlag(cmd, "currency"),
+	}
+
+	if update.TypeName != nil {
+		if err := validateAssetType(*update.TypeName); err != nil {
+			return nil, err
+		}
+	}
+	if update.Balance != nil {
+		if err := validateAssetBalance(*update.Balance); err != nil {
+			return nil, err
+		}
+		if update.BalanceAsOf == nil {
+			update.BalanceAsOf = ptr(now.Format(dateLayout))
+		}
+	}
+	if update.BalanceAsOf != nil {
+		if err := validateAsOf(*update.BalanceAsOf); err != nil {
+			return nil, err
+		}
+	}
+	if update.Currency != nil {
+		update.Currency = ptr(strings.ToLower(*update.Currency))
+	}
+
+	return update, nil
+}
+
+// validateAssetType checks that Lunch Money accepts the asset type.
+func validateAssetType(typeName string) error {
+	if !slices.Contains(assetTypes, typeName) {
+		return fmt.Errorf("invalid asset type: %s (must be one of %s)", typeName, strings.Join(assetTypes, ", "))
+	}
+	return nil
+}
+
+// validateAssetBalance checks that the balance is a plain amount such as 1234.56.
+func validateAssetBalance(balance string) error {
+	if _, err := strconv.ParseFloat(balance, 64); err != nil {
+		return fmt.Errorf("invalid balance: %s", balance)
+	}
+	return nil
+}
+
+// validateAsOf checks the date a balance is as of.
+func validateAsOf(date string) error {
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", date)
+	}
+	return nil
+}
+
+// outputAsset prints the created or updated asset like accounts list does.
+func outputAsset(cmd *cobra.Command, outputFormat string, asset *lm.Asset) error {
+	account := convertAssetToAccount(asset)
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, account)
+	case tableOutputFormat:
+		return outputAccountsTable(cmd, []Account{account})
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+// deref returns the value of an optional string, or an empty string.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+)
+
+func TestAssetUpdateFromFlags(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		expected    lm.UpdateAsset
+		expectedErr bool
+	}{
+		{
+			name:     "no flags",
+			expected: lm.UpdateAsset{},
+		},
+		{
+			name:     "balance is as of today",
+			flags:    map[string]string{"balance": "1250.50"},
+			expected: lm.UpdateAsset{Balance: ptr("1250.50"), BalanceAsOf: ptr("2024-03-01")},
+		},
+		{
+			name:     "balance as of a date",
+			flags:    map[string]string{"balance": "1250.50", "as-of": "2024-02-29"},
+			expected: lm.UpdateAsset{Balance: ptr("1250.50"), BalanceAsOf: ptr("2024-02-29")},
+		},
+		{
+			name:  "other fields",
+			flags: map[string]string{"name": "House", "type": "real estate", "currency": "EUR"},
+			expected: lm.UpdateAsset{
+				Name:     ptr("House"),
+				TypeName: ptr("real estate"),
+				Currency: ptr("eur"),
+			},
+		},
+		{
+			name:        "invalid type",
+			flags:       map[string]string{"type": "house"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid balance",
+			flags:       map[string]string{"balance": "$100"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid date",
+			flags:       map[string]string{"balance": "100", "as-of": "03/01/2024"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addAssetFlags(cmd)
+			for name, value := range tt.flags {
+				be.NilErr(t, cmd.Flags().Set(name, value))
+			}
+
+			update, err := assetUpdateFromFlags(cmd, now)
+			if tt.expectedErr {
+				be.Nonzero(t, err)
+				return
+			}
+			be.NilErr(t, err)
+			be.DeepEqual(t, tt.expected, *update)
+		})
+	}
+}
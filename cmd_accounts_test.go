@@ -5,6 +5,8 @@ import (
 
 	"github.com/carlmjohnson/be"
 	lm "github.com/icco/lunchmoney"
+
+	"github.com/Rshep3087/lunchtui/liability"
 )
 
 func TestConvertAssetToAccount(t *testing.T) {
@@ -134,3 +136,28 @@ func TestConvertPlaidAccountToAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestFindCommandAccount(t *testing.T) {
+	assets := []*lm.Asset{{ID: 1, Name: "Car loan", TypeName: "loan"}}
+	plaidAccounts := []*lm.PlaidAccount{
+		{ID: 1, Name: "Checking", Type: "depository"},
+		{ID: 2, Name: "Visa", Type: "credit"},
+	}
+
+	_, _, err := findCommandAccount(assets, plaidAccounts, accountRef{id: 1}, liability.Classifier{})
+	be.In(t, "--type", err.Error())
+
+	account, _, err := findCommandAccount(assets, plaidAccounts,
+		accountRef{accountType: plaidAccountType, id: 1}, liability.Classifier{})
+	be.NilErr(t, err)
+	be.Equal(t, "Checking", account.Name)
+
+	account, isLiability, err := findCommandAccount(assets, plaidAccounts, accountRef{id: 2}, liability.Classifier{})
+	be.NilErr(t, err)
+	be.Equal(t, "Visa", account.Name)
+	be.True(t, isLiability)
+
+	_, _, err = findCommandAccount(assets, plaidAccounts,
+		accountRef{accountType: assetAccountType, id: 2}, liability.Classifier{})
+	be.Equal(t, "asset account 2 not found", err.Error())
+}
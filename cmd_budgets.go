@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+)
+
+// budgetsCmd represents the budgets command.
+var budgetsCmd = &cobra.Command{
+	Use:   "budgets",
+	Short: "Budget commands",
+	Long:  `Commands for reporting on budgets in Lunch Money.`,
+}
+
+// budgetsListCmd represents the budgets list command.
+var budgetsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List budgets and spending by category and month",
+	Long: `List the budget, spending and variance of every category for each month in the period
+selected with --period, --start and --end, the current month by default. Amounts are in your
+primary currency. The command exits with a non-zero status when any expense category is over
+budget, so it can be used to alert from scheduled jobs.`,
+	RunE: budgetsListRun,
+}
+
+func init() {
+	// Add budgets list subcommand
+	budgetsCmd.AddCommand(budgetsListCmd)
+
+	// Budgets list flags
+	addPeriodFlags(budgetsListCmd)
+	budgetsListCmd.Flags().Bool("over-only", false, "Only include categories that are over budget")
+	budgetsListCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table, json or csv")
+}
+
+// budgetRow is the budget of a category for one month.
+type budgetRow struct {
+	Month         string `json:"month"`
+	CategoryID    int    `json:"category_id"`
+	Category      string `json:"category"`
+	CategoryGroup string `json:"category_group,omitempty"`
+	IsIncome      bool   `json:"is_income"`
+	// Budgeted and Variance are empty when the category has no budget
+	Budgeted     string `json:"budgeted,omitempty"`
+	Spent        string `json:"spent"`
+	Variance     string `json:"variance,omitempty"`
+	Currency     string `json:"currency"`
+	Transactions int    `json:"transactions"`
+	OverBudget   bool   `json:"over_budget"`
+
+	summary budgetSummary
+}
+
+func budgetsListRun(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	outputFormat, err := validateOutputFormat(cmd, csvOutputFormat)
+	if err != nil {
+		return err
+	}
+
+	_, period, err := periodFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	startDate, endDate := period.startDate(), period.endDate()
+	overOnly, _ := cmd.Flags().GetBool("over-only")
+
+	user, err := fetchCached(ctx, lmCache, lmCache.userQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch user info: %w", err)
+	}
+
+	log.Debug("fetching budgets", "start", startDate, "end", endDate)
+
+	budgets, err := fetchCached(ctx, lmCache, lmCache.budgetsQuery(startDate, endDate))
+	if err != nil {
+		return fmt.Errorf("failed to fetch budgets: %w", err)
+	}
+
+	currency := user.PrimaryCurrency
+	if currency == "" {
+		currency = "USD"
+	}
+
+	rows := newBudgetRows(budgets, currency)
+	over := overBudgetRows(rows)
+	if overOnly {
+		rows = over
+	}
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		err = outputJSON(cmd, rows)
+	case tableOutputFormat:
+		err = outputBudgetsTable(cmd, rows)
+	case csvOutputFormat:
+		err = outputBudgetsCSV(cmd, rows)
+	default:
+		err = errors.New("unsupported output format")
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(over) > 0 {
+		return fmt.Errorf("categories over budget: %d", len(over))
+	}
+	return nil
+}
+
+// newBudgetRows lists the budget of every category for each month, ordered by month.
+// Category groups are left out as their categories are listed, and so are categories
+// excluded from the budget.
+func newBudgetRows(budgets []*lm.Budget, currency string) []budgetRow {
+	var rows []budgetRow
+	for _, b := range budgets {
+		if b.IsGroup || b.ExcludeFromBudget {
+			continue
+		}
+
+		for month, data := range b.Data {
+			if data == nil {
+				continue
+			}
+
+			summary := summarizeBudgetMonth(data, b.IsIncome, currency)
+			row := budgetRow{
+				Month:         month,
+				CategoryID:    b.CategoryID,
+				Category:      b.CategoryName,
+				CategoryGroup: b.CategoryGroupName,
+				IsIncome:      b.IsIncome,
+				Spent:         formatAmount(summary.spent),
+				Currency:      summary.spent.Currency().Code,
+				Transactions:  summary.transactions,
+				OverBudget:    summary.over() && !b.IsIncome,
+				summary:       summary,
+			}
+			if summary.budgeted != nil {
+				row.Budgeted = formatAmount(summary.budgeted)
+				row.Variance = formatAmount(summary.remaining())
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	// ordered by month, keeping the API's category order within a month
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Month < rows[j].Month
+	})
+	return rows
+}
+
+// overBudgetRows returns the rows of expense categories that are over budget.
+func overBudgetRows(rows []budgetRow) []budgetRow {
+	var over []budgetRow
+	for _, row := range rows {
+		if row.OverBudget {
+			over = append(over, row)
+		}
+	}
+	return over
+}
+
+func outputBudgetsTable(cmd *cobra.Command, rows []budgetRow) error {
+	t := createStyledTable("MONTH", "CATEGORY", "GROUP", "BUDGETED", "SPENT", "VARIANCE", "TRANSACTIONS")
+
+	for _, row := range rows {
+		budgeted, variance := "-", "-"
+		if row.summary.budgeted != nil {
+			budgeted = row.summary.budgeted.Display()
+			variance = row.summary.remaining().Display()
+		}
+		category := row.Category
+		if row.OverBudget {
+			category += " (over budget)"
+		}
+		group := row.CategoryGroup
+		if group == "" {
+			group = "-"
+		}
+
+		t.Row(
+			row.Month,
+			category,
+			group,
+			budgeted,
+			row.summary.spent.Display(),
+			variance,
+			strconv.Itoa(row.Transactions),
+		)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), t)
+	return nil
+}
+
+func outputBudgetsCSV(cmd *cobra.Command, rows []budgetRow) error {
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = []string{
+			row.Month,
+			strconv.Itoa(row.CategoryID),
+			row.Category,
+			row.CategoryGroup,
+			strconv.FormatBool(row.IsIncome),
+			row.Budgeted,
+			row.Spent,
+			row.Variance,
+			row.Currency,
+			strconv.Itoa(row.Transactions),
+			strconv.FormatBool(row.OverBudget),
+		}
+	}
+
+	return outputCSV(cmd, []string{
+		"month", "category_id", "category", "category_group", "is_income", "budgeted",
+		"spent", "variance", "currency", "transactions", "over_budget",
+	}, records)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+)
+
+func TestNewBudgetRows(t *testing.T) {
+	budgets := []*lm.Budget{
+		{CategoryID: 1, CategoryName: "Food", IsGroup: true, Data: map[string]*lm.BudgetData{
+			"2025-01-01": budgetMonth("400", 450, 5),
+		}},
+		{CategoryID: 2, CategoryName: "Groceries", CategoryGroupName: "Food", GroupID: 1,
+			Data: map[string]*lm.BudgetData{
+				"2025-02-01": budgetMonth("300", 250, 2),
+				"2025-01-01": budgetMonth("300", 350.5, 3),
+			}},
+		{CategoryID: 3, CategoryName: "Transfers", ExcludeFromBudget: true, Data: map[string]*lm.BudgetData{
+			"2025-01-01": budgetMonth("10", 20, 1),
+		}},
+		{CategoryID: 4, CategoryName: "Salary", IsIncome: true, Data: map[string]*lm.BudgetData{
+			"2025-01-01": budgetMonth("4000", -4500, 1),
+		}},
+		{CategoryID: 5, CategoryName: "Gifts", Data: map[string]*lm.BudgetData{
+			"2025-01-01": {SpendingToBase: 25, NumTransactions: 1},
+		}},
+	}
+
+	rows := newBudgetRows(budgets, "usd")
+
+	be.Equal(t, 4, len(rows))
+	expected := []budgetRow{
+		{
+			Month: "2025-01-01", CategoryID: 2, Category: "Groceries", CategoryGroup: "Food",
+			Budgeted: "300.00", Spent: "350.50", Variance: "-50.50", Currency: "USD",
+			Transactions: 3, OverBudget: true,
+		},
+		{
+			Month: "2025-01-01", CategoryID: 4, Category: "Salary", IsIncome: true,
+			Budgeted: "4000.00", Spent: "4500.00", Variance: "-500.00", Currency: "USD",
+			Transactions: 1,
+		},
+		{
+			Month: "2025-01-01", CategoryID: 5, Category: "Gifts",
+			Spent: "25.00", Currency: "USD", Transactions: 1,
+		},
+		{
+			Month: "2025-02-01", CategoryID: 2, Category: "Groceries", CategoryGroup: "Food",
+			Budgeted: "300.00", Spent: "250.00", Variance: "50.00", Currency: "USD",
+			Transactions: 2,
+		},
+	}
+	for i, row := range rows {
+		row.summary = budgetSummary{}
+		be.Equal(t, expected[i], row)
+	}
+
+	over := overBudgetRows(rows)
+	be.Equal(t, 1, len(over))
+	be.Equal(t, "Groceries", over[0].Category)
+}
+
+func TestOutputBudgetsCSV(t *testing.T) {
+	rows := newBudgetRows([]*lm.Budget{
+		{CategoryID: 2, CategoryName: "Groceries", Data: map[string]*lm.BudgetData{
+			"2025-01-01": budgetMonth("300", 350.5, 3),
+		}},
+		{CategoryID: 5, CategoryName: "Gifts, cards", Data: map[string]*lm.BudgetData{
+			"2025-01-01": {SpendingToBase: 25, NumTransactions: 1},
+		}},
+	}, "usd")
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	be.NilErr(t, outputBudgetsCSV(cmd, rows))
+
+	be.Equal(t, "month,category_id,category,category_group,is_income,budgeted,spent,variance,currency,"+
+		"transactions,over_budget\n"+
+		"2025-01-01,2,Groceries,,false,300.00,350.50,-50.50,USD,3,true\n"+
+		"2025-01-01,5,\"Gifts, cards\",,false,,25.00,,USD,1,false\n", out.String())
+}
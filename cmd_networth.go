@@ -4,10 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/Rhymond/go-money"
+	"github.com/Rshep3087/lunchtui/liability"
+	"github.com/Rshep3087/lunchtui/overview"
+	"github.com/Rshep3087/lunchtui/totals"
+	"github.com/charmbracelet/log"
 	lm "github.com/icco/lunchmoney"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // networthCmd represents the networth command.
@@ -25,13 +31,38 @@ var networthGetCmd = &cobra.Command{
 	RunE:  networthGetRun,
 }
 
+// networthSnapshotCmd represents the networth snapshot command.
+var networthSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Record the current net worth in the local history",
+	Long: `Calculate the current net worth with its breakdown and append it to the local net worth history.
+Run it on a schedule, for example daily from cron, to build up the history shown by "networth history".`,
+	RunE: networthSnapshotRun,
+}
+
+// networthHistoryCmd represents the networth history command.
+var networthHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the net worth history",
+	Long:  `Show the recorded net worth snapshots with the change of each account type since the previous one.`,
+	RunE:  networthHistoryRun,
+}
+
 func init() {
-	// Add networth get subcommand
+	// Add networth subcommands
 	networthCmd.AddCommand(networthGetCmd)
+	networthCmd.AddCommand(networthSnapshotCmd)
+	networthCmd.AddCommand(networthHistoryCmd)
 
 	// Net worth get flags
 	networthGetCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
 	networthGetCmd.Flags().Bool("breakdown", false, "Show detailed breakdown of assets and liabilities")
+
+	// Net worth snapshot flags
+	networthSnapshotCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+
+	// Net worth history flags
+	networthHistoryCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
 }
 
 func networthGetRun(cmd *cobra.Command, _ []string) error {
@@ -44,8 +75,13 @@ func networthGetRun(cmd *cobra.Command, _ []string) error {
 	}
 	showBreakdown, _ := cmd.Flags().GetBool("breakdown")
 
+	classifier, err := loadLiabilityClassifier()
+	if err != nil {
+		return err
+	}
+
 	// Fetch user info to get primary currency
-	user, userErr := lmc.GetUser(ctx)
+	user, userErr := fetchCached(ctx, lmCache, lmCache.userQuery())
 	if userErr != nil {
 		return fmt.Errorf("failed to fetch user info: %w", userErr)
 	}
@@ -62,7 +98,8 @@ func networthGetRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Calculate net worth using shared logic and types
-	netWorthData := calculateNetWorthData(assets, plaidAccounts, currency, showBreakdown)
+	netWorthData := calculateNetWorthData(assets, plaidAccounts, currency, classifier, showBreakdown)
+	warnUnconverted(netWorthData)
 
 	switch outputFormat {
 	case jsonOutputFormat:
@@ -74,17 +111,272 @@ func networthGetRun(cmd *cobra.Command, _ []string) error {
 	}
 }
 
+func networthSnapshotRun(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	path, err := netWorthHistoryPath()
+	if err != nil {
+		return err
+	}
+
+	classifier, err := loadLiabilityClassifier()
+	if err != nil {
+		return err
+	}
+
+	user, err := fetchCached(ctx, lmCache, lmCache.userQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch user info: %w", err)
+	}
+
+	currency := user.PrimaryCurrency
+	if currency == "" {
+		currency = "USD"
+	}
+
+	assets, plaidAccounts, err := fetchAssetsAndPlaidAccountsParallel(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := calculateNetWorthData(assets, plaidAccounts, currency, classifier, true)
+	warnUnconverted(data)
+	snapshot := newNetWorthSnapshot(data, time.Now())
+	if err = appendNetWorthSnapshot(path, snapshot); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, snapshot)
+	case tableOutputFormat:
+		fmt.Fprintf(cmd.OutOrStdout(), "Recorded net worth of %s in %s\n",
+			money.New(snapshot.NetWorth, snapshot.Currency).Display(), path)
+		return nil
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+func networthHistoryRun(cmd *cobra.Command, _ []string) error {
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	path, err := netWorthHistoryPath()
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := readNetWorthHistory(path)
+	if err != nil {
+		return fmt.Errorf("failed to read net worth history: %w", err)
+	}
+
+	entries := newNetWorthHistory(snapshots)
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, netWorthHistoryJSON(entries))
+	case tableOutputFormat:
+		return outputNetWorthHistoryTable(cmd, entries, netWorthValues(snapshots))
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+// netWorthHistoryEntry is a snapshot of the net worth history with its change since the
+// previous snapshot.
+type netWorthHistoryEntry struct {
+	TakenAt      time.Time
+	Currency     string
+	NetWorth     *money.Money
+	AccountTypes map[string]*money.Money
+	// Change is nil for the first snapshot and when the currency changed since the previous one
+	Change *money.Money
+	// AccountTypeChanges is the change of each account type, including types that were
+	// added or removed since the previous snapshot
+	AccountTypeChanges map[string]*money.Money
+	// CurrencyChanged is set when the previous snapshot is in another currency, so the
+	// changes cannot be worked out
+	CurrencyChanged bool
+}
+
+// netWorthHistoryEntryJSON is a history entry in the JSON output, with plain decimal amounts.
+type netWorthHistoryEntryJSON struct {
+	TakenAt            time.Time         `json:"taken_at"`
+	Currency           string            `json:"currency"`
+	NetWorth           string            `json:"net_worth"`
+	Change             string            `json:"change,omitempty"`
+	CurrencyChanged    bool              `json:"currency_changed,omitempty"`
+	AccountTypes       map[string]string `json:"account_types"`
+	AccountTypeChanges map[string]string `json:"account_type_changes"`
+}
+
+// newNetWorthHistory returns the snapshots with their changes. The first snapshot has
+// no previous one and a snapshot in another currency than the previous one cannot be
+// compared with it, so neither has changes.
+func newNetWorthHistory(snapshots []netWorthSnapshot) []netWorthHistoryEntry {
+	entries := make([]netWorthHistoryEntry, len(snapshots))
+	for i, s := range snapshots {
+		entries[i] = netWorthHistoryEntry{
+			TakenAt:            s.TakenAt,
+			Currency:           s.Currency,
+			NetWorth:           money.New(s.NetWorth, s.Currency),
+			AccountTypes:       make(map[string]*money.Money, len(s.AccountTypes)),
+			AccountTypeChanges: make(map[string]*money.Money),
+		}
+		entry := &entries[i]
+		for accountType, amount := range s.AccountTypes {
+			entry.AccountTypes[accountType] = money.New(amount, s.Currency)
+		}
+		if i == 0 {
+			continue
+		}
+
+		previous := snapshots[i-1]
+		change, err := entry.NetWorth.Subtract(money.New(previous.NetWorth, previous.Currency))
+		if err != nil {
+			entry.CurrencyChanged = true
+			continue
+		}
+		entry.Change = change
+		for accountType, amount := range entry.AccountTypes {
+			entry.AccountTypeChanges[accountType], _ = amount.Subtract(
+				money.New(previous.AccountTypes[accountType], previous.Currency))
+		}
+		for accountType, amount := range previous.AccountTypes {
+			if _, ok := s.AccountTypes[accountType]; !ok {
+				entry.AccountTypeChanges[accountType] = money.New(-amount, previous.Currency)
+			}
+		}
+	}
+	return entries
+}
+
+// netWorthHistoryJSON converts the history entries for the JSON output.
+func netWorthHistoryJSON(entries []netWorthHistoryEntry) []netWorthHistoryEntryJSON {
+	out := make([]netWorthHistoryEntryJSON, len(entries))
+	for i, entry := range entries {
+		out[i] = netWorthHistoryEntryJSON{
+			TakenAt:            entry.TakenAt,
+			Currency:           entry.Currency,
+			NetWorth:           formatAmount(entry.NetWorth),
+			CurrencyChanged:    entry.CurrencyChanged,
+			AccountTypes:       make(map[string]string, len(entry.AccountTypes)),
+			AccountTypeChanges: make(map[string]string, len(entry.AccountTypeChanges)),
+		}
+		if entry.Change != nil {
+			out[i].Change = formatAmount(entry.Change)
+		}
+		for accountType, amount := range entry.AccountTypes {
+			out[i].AccountTypes[accountType] = formatAmount(amount)
+		}
+		for accountType, change := range entry.AccountTypeChanges {
+			out[i].AccountTypeChanges[accountType] = formatAmount(change)
+		}
+	}
+	return out
+}
+
+func outputNetWorthHistoryTable(cmd *cobra.Command, entries []netWorthHistoryEntry, values []float64) error {
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), `No net worth snapshots recorded yet, run "lunchtui networth snapshot"`)
+		return nil
+	}
+
+	var accountTypes []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		for accountType := range entry.AccountTypes {
+			if !seen[accountType] {
+				seen[accountType] = true
+				accountTypes = append(accountTypes, accountType)
+			}
+		}
+	}
+	sort.Strings(accountTypes)
+
+	// the total change is since the currency last changed
+	start := len(entries) - 1
+	for start > 0 && !entries[start].CurrencyChanged {
+		start--
+	}
+	first, last := entries[start], entries[len(entries)-1]
+	total, _ := last.NetWorth.Subtract(first.NetWorth)
+	fmt.Fprintf(cmd.OutOrStdout(), "Net Worth: %s  %s  (%s since %s)\n\n",
+		last.NetWorth.Display(),
+		overview.Sparkline(values, len(values)),
+		formatChange(total),
+		first.TakenAt.Format("2006-01-02"),
+	)
+
+	headers := append([]string{"DATE", "NET WORTH", "CHANGE"}, accountTypes...)
+	t := createStyledTable(headers...)
+	for _, entry := range entries {
+		row := []string{
+			entry.TakenAt.Local().Format("2006-01-02 15:04"),
+			entry.NetWorth.Display(),
+		}
+		switch {
+		case entry.CurrencyChanged:
+			row = append(row, "currency changed")
+		case entry.Change == nil:
+			row = append(row, "-")
+		default:
+			row = append(row, formatChange(entry.Change))
+		}
+		for _, accountType := range accountTypes {
+			change, ok := entry.AccountTypeChanges[accountType]
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, formatChange(change))
+		}
+		t.Row(row...)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), t)
+	return nil
+}
+
+// formatChange formats a change in net worth with its sign, such as "+$12.00".
+func formatChange(change *money.Money) string {
+	if change.IsPositive() {
+		return "+" + change.Display()
+	}
+	return change.Display()
+}
+
+// loadLiabilityClassifier reads the [[networth_accounts]] section of the config file.
+func loadLiabilityClassifier() (liability.Classifier, error) {
+	var overrides []liability.Override
+	if err := viper.UnmarshalKey("networth_accounts", &overrides); err != nil {
+		return liability.Classifier{}, fmt.Errorf("failed to read networth accounts from config: %w", err)
+	}
+	return liability.New(overrides)
+}
+
 // calculateNetWorthData reuses the existing net worth calculation logic from overview
 // but returns the shared NetWorthData type for consistency between CLI and TUI.
 func calculateNetWorthData(
 	assets []*lm.Asset,
 	plaidAccounts []*lm.PlaidAccount,
 	currency string,
+	classifier liability.Classifier,
 	includeBreakdown bool,
 ) *NetWorthData {
-	netWorth := money.New(0, currency)
-	totalAssets := money.New(0, currency)
-	totalLiabilities := money.New(0, currency)
+	sums := netWorthSums{
+		netWorth:    totals.New(currency),
+		assets:      totals.New(currency),
+		liabilities: totals.New(currency),
+	}
 
 	var breakdown *NetWorthBreakdown
 	if includeBreakdown {
@@ -95,16 +387,8 @@ func calculateNetWorthData(
 	}
 
 	// Process assets and plaid accounts
-	processAssets(assets, currency, &netWorth, &totalAssets, &totalLiabilities, breakdown, includeBreakdown)
-	processPlaidAccounts(
-		plaidAccounts,
-		currency,
-		&netWorth,
-		&totalAssets,
-		&totalLiabilities,
-		breakdown,
-		includeBreakdown,
-	)
+	processAssets(assets, classifier, sums, breakdown)
+	processPlaidAccounts(plaidAccounts, classifier, sums, breakdown)
 
 	// Sort breakdown by amount (descending)
 	if includeBreakdown {
@@ -112,83 +396,103 @@ func calculateNetWorthData(
 	}
 
 	return &NetWorthData{
-		NetWorth:         netWorth,
-		TotalAssets:      totalAssets,
-		TotalLiabilities: totalLiabilities,
+		NetWorth:         sums.netWorth.Total(),
+		TotalAssets:      sums.assets.Total(),
+		TotalLiabilities: sums.liabilities.Total(),
 		Currency:         currency,
 		Breakdown:        breakdown,
+		Subtotals:        sums.netWorth.Subtotals(),
+		Unconverted:      sums.netWorth.Unconverted(),
 	}
 }
 
+// warnUnconverted warns that accounts are missing from the net worth.
+func warnUnconverted(data *NetWorthData) {
+	if data.Unconverted > 0 {
+		log.Warn("accounts left out of the net worth, their balance could not be converted",
+			"accounts", data.Unconverted, "currency", data.Currency)
+	}
+}
+
+// netWorthSums adds up the balances of the accounts. The net worth keeps the subtotal of
+// each currency, with liabilities subtracted.
+type netWorthSums struct {
+	netWorth    *totals.Sum
+	assets      *totals.Sum
+	liabilities *totals.Sum
+}
+
+// add adds the balance of an account and returns it in the primary currency. It reports
+// false when the balance could not be converted.
+func (s netWorthSums) add(balance *money.Money, toBase float64, isLiability bool) (*money.Money, bool) {
+	if isLiability {
+		s.netWorth.Add(balance.Negative(), toBase)
+		return s.liabilities.Add(balance, toBase)
+	}
+	s.netWorth.Add(balance, toBase)
+	return s.assets.Add(balance, toBase)
+}
+
 func processAssets(
 	assets []*lm.Asset,
-	currency string,
-	netWorth, totalAssets, totalLiabilities **money.Money,
+	classifier liability.Classifier,
+	sums netWorthSums,
 	breakdown *NetWorthBreakdown,
-	includeBreakdown bool,
 ) {
 	for _, asset := range assets {
-		amount := money.NewFromFloat(asset.ToBase, currency)
-		*netWorth = updateNetWorthAmount(*netWorth, amount, asset.TypeName, asset.SubtypeName)
+		balance, err := asset.ParsedAmount()
+		if err != nil {
+			log.Debug("parsing asset balance", "asset", asset.ID, "error", err)
+			continue
+		}
 
-		if asset.TypeName == creditType && asset.SubtypeName == creditCardSubtype {
-			*totalLiabilities, _ = (*totalLiabilities).Add(amount)
-			if includeBreakdown {
-				addAccountToBreakdown(breakdown.Liabilities, asset, amount)
-			}
+		isLiability := classifier.IsLiability(liability.FromAsset(asset))
+		amount, ok := sums.add(balance, asset.ToBase, isLiability)
+		if !ok || breakdown == nil {
+			continue
+		}
+
+		if isLiability {
+			addAccountToBreakdown(breakdown.Liabilities, asset, amount, true)
 		} else {
-			*totalAssets, _ = (*totalAssets).Add(amount)
-			if includeBreakdown {
-				addAccountToBreakdown(breakdown.Assets, asset, amount)
-			}
+			addAccountToBreakdown(breakdown.Assets, asset, amount, false)
 		}
 	}
 }
 
 func processPlaidAccounts(
 	accounts []*lm.PlaidAccount,
-	currency string,
-	netWorth, totalAssets, totalLiabilities **money.Money,
+	classifier liability.Classifier,
+	sums netWorthSums,
 	breakdown *NetWorthBreakdown,
-	includeBreakdown bool,
 ) {
 	for _, account := range accounts {
-		amount := money.NewFromFloat(account.ToBase, currency)
-		*netWorth = updateNetWorthAmount(*netWorth, amount, account.Type, account.Subtype)
-
-		if account.Type == creditType && account.Subtype == creditCardSubtype {
-			*totalLiabilities, _ = (*totalLiabilities).Add(amount)
-			if includeBreakdown {
-				addPlaidAccountToBreakdown(breakdown.Liabilities, account, amount)
-			}
-		} else {
-			*totalAssets, _ = (*totalAssets).Add(amount)
-			if includeBreakdown {
-				addPlaidAccountToBreakdown(breakdown.Assets, account, amount)
-			}
+		balance, err := account.ParsedAmount()
+		if err != nil {
+			log.Debug("parsing plaid account balance", "account", account.ID, "error", err)
+			continue
 		}
-	}
-}
-
-// updateNetWorthAmount reuses the exact logic from overview/overview.go.
-func updateNetWorthAmount(netWorth, amount *money.Money, assetType, subtype string) *money.Money {
-	var nwa *money.Money
-	var err error
 
-	if assetType == creditType && subtype == creditCardSubtype {
-		nwa, err = netWorth.Subtract(amount)
-	} else {
-		nwa, err = netWorth.Add(amount)
-	}
+		isLiability := classifier.IsLiability(liability.FromPlaidAccount(account))
+		amount, ok := sums.add(balance, account.ToBase, isLiability)
+		if !ok || breakdown == nil {
+			continue
+		}
 
-	if err != nil {
-		return netWorth
+		if isLiability {
+			addPlaidAccountToBreakdown(breakdown.Liabilities, account, amount, true)
+		} else {
+			addPlaidAccountToBreakdown(breakdown.Assets, account, amount, false)
+		}
 	}
-
-	return nwa
 }
 
-func addAccountToBreakdown(categoryMap map[string][]*AccountSummary, asset *lm.Asset, amount *money.Money) {
+func addAccountToBreakdown(
+	categoryMap map[string][]*AccountSummary,
+	asset *lm.Asset,
+	amount *money.Money,
+	isLiability bool,
+) {
 	category := formatAccountCategory(asset.TypeName, asset.SubtypeName)
 
 	categoryMap[category] = append(categoryMap[category], &AccountSummary{
@@ -200,6 +504,7 @@ func addAccountToBreakdown(categoryMap map[string][]*AccountSummary, asset *lm.A
 		Amount:          amount,
 		InstitutionName: asset.InstitutionName,
 		AccountType:     "asset",
+		liability:       isLiability,
 	})
 }
 
@@ -207,6 +512,7 @@ func addPlaidAccountToBreakdown(
 	categoryMap map[string][]*AccountSummary,
 	account *lm.PlaidAccount,
 	amount *money.Money,
+	isLiability bool,
 ) {
 	category := formatAccountCategory(account.Type, account.Subtype)
 
@@ -219,6 +525,7 @@ func addPlaidAccountToBreakdown(
 		Amount:          amount,
 		InstitutionName: account.InstitutionName,
 		AccountType:     "plaid",
+		liability:       isLiability,
 	})
 }
 
@@ -246,7 +553,11 @@ func sortNetWorthBreakdown(breakdown *NetWorthBreakdown) {
 }
 
 func outputNetWorthTable(cmd *cobra.Command, data *NetWorthData) error {
-	fmt.Fprintf(cmd.OutOrStdout(), "Net Worth: %s\n\n", data.NetWorth.Display())
+	fmt.Fprintf(cmd.OutOrStdout(), "Net Worth: %s\n", data.NetWorth.Display())
+	if data.MultiCurrency() {
+		fmt.Fprintf(cmd.OutOrStdout(), "By currency: %s\n", data.SubtotalsString())
+	}
+	fmt.Fprintln(cmd.OutOrStdout())
 
 	if data.Breakdown != nil {
 		if len(data.Breakdown.Assets) > 0 {
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Rshep3087/lunchtui/liability"
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestCalculateNetWorthData(t *testing.T) {
+	classifier, err := liability.New([]liability.Override{{Account: "Loan to Sam", Liability: false}})
+	be.NilErr(t, err)
+
+	assets := []*lm.Asset{
+		{ID: 1, Name: "House", TypeName: "real estate", Balance: "300000", Currency: "usd"},
+		{ID: 2, Name: "Loan to Sam", TypeName: "loan", Balance: "500", Currency: "usd"},
+		{ID: 3, Name: "Car loan", TypeName: "loan", SubtypeName: "auto", Balance: "15000", Currency: "usd"},
+	}
+	plaidAccounts := []*lm.PlaidAccount{
+		{ID: 4, Name: "Mortgage", Type: "loan", Subtype: "mortgage", Balance: "200000", Currency: "usd"},
+		{ID: 5, Name: "Visa", Type: "credit", Subtype: "credit card", Balance: "1000", Currency: "usd"},
+		{ID: 6, Name: "HELOC", Type: "other", Subtype: "home equity", Balance: "2500", Currency: "usd"},
+	}
+
+	data := calculateNetWorthData(assets, plaidAccounts, "USD", classifier, true)
+
+	be.Equal(t, "$300,500.00", data.TotalAssets.Display())
+	be.Equal(t, "$218,500.00", data.TotalLiabilities.Display())
+	be.Equal(t, "$82,000.00", data.NetWorth.Display())
+	be.Equal(t, 4, len(data.Breakdown.Liabilities))
+	be.Equal(t, "Loan to Sam", data.Breakdown.Assets["Loan"][0].Name)
+	be.False(t, data.Breakdown.Assets["Loan"][0].IsLiability())
+	be.True(t, data.Breakdown.Liabilities["Mortgage"][0].IsLiability())
+}
+
+func TestCalculateNetWorthData_MultiCurrency(t *testing.T) {
+	assets := []*lm.Asset{
+		{ID: 1, Name: "Checking", TypeName: "cash", Balance: "1000", Currency: "usd"},
+		{ID: 2, Name: "Euro savings", TypeName: "cash", Balance: "500", Currency: "eur", ToBase: 550},
+		{ID: 3, Name: "Pound savings", TypeName: "cash", Balance: "100", Currency: "gbp"},
+	}
+	plaidAccounts := []*lm.PlaidAccount{
+		{ID: 4, Name: "Visa", Type: "credit", Subtype: "credit card", Balance: "100", Currency: "eur", ToBase: 110},
+	}
+
+	data := calculateNetWorthData(assets, plaidAccounts, "usd", liability.Classifier{}, false)
+
+	be.Equal(t, "$1,440.00", data.NetWorth.Display())
+	be.Equal(t, "$1,550.00", data.TotalAssets.Display())
+	be.Equal(t, "$110.00", data.TotalLiabilities.Display())
+	be.Equal(t, 1, data.Unconverted)
+	be.True(t, data.MultiCurrency())
+	be.Equal(t, "$1,000.00 + €400.00 + £100.00", data.SubtotalsString())
+
+	summary := data.ToJSON()
+	be.Equal(t, 1, summary.UnconvertedAccounts)
+	be.Equal(t, "€400.00", summary.Subtotals["EUR"])
+}
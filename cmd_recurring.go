@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/recurring"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+)
+
+// nextDueHorizon is how far ahead the next due date of a recurring expense is looked for,
+// which covers every cadence up to yearly.
+const nextDueHorizon = 366
+
+// recurringCmd represents the recurring command.
+var recurringCmd = &cobra.Command{
+	Use:   "recurring",
+	Short: "Recurring expense commands",
+	Long:  `Commands for listing recurring expenses and the bills coming up in Lunch Money.`,
+}
+
+// recurringListCmd represents the recurring list command.
+var recurringListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recurring expenses",
+	Long:  `List all recurring expenses with their cadence, amount and next due date.`,
+	RunE:  recurringListRun,
+}
+
+// recurringUpcomingCmd represents the recurring upcoming command.
+var recurringUpcomingCmd = &cobra.Command{
+	Use:   "upcoming",
+	Short: "List the bills due in the next days",
+	Long: `List every recurring expense due in the next days, projected from its cadence and billing date.
+Use --output ics to export the due dates as an iCalendar file.`,
+	RunE: recurringUpcomingRun,
+}
+
+func init() {
+	// Add recurring subcommands
+	recurringCmd.AddCommand(recurringListCmd)
+	recurringCmd.AddCommand(recurringUpcomingCmd)
+
+	// Recurring list flags
+	recurringListCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+
+	// Recurring upcoming flags
+	recurringUpcomingCmd.Flags().Int("days", recurring.DefaultCalendarDays, "Number of days ahead to include")
+	recurringUpcomingCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table, json or ics")
+}
+
+// recurringExpense is the JSON output of a recurring expense.
+type recurringExpense struct {
+	ID          int64  `json:"id"`
+	Payee       string `json:"payee"`
+	Description string `json:"description,omitempty"`
+	Cadence     string `json:"cadence"`
+	BillingDate string `json:"billing_date"`
+	Amount      string `json:"amount"`
+	Currency    string `json:"currency"`
+	// NextDue is empty when the cadence is unknown or the expense has ended
+	NextDue string `json:"next_due,omitempty"`
+}
+
+// upcomingBill is the JSON output of a recurring expense due on a date.
+type upcomingBill struct {
+	Date        string `json:"date"`
+	RecurringID int64  `json:"recurring_id"`
+	Payee       string `json:"payee"`
+	Description string `json:"description,omitempty"`
+	Cadence     string `json:"cadence"`
+	Amount      string `json:"amount"`
+	Currency    string `json:"currency"`
+}
+
+func recurringListRun(cmd *cobra.Command, _ []string) error {
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	expenses, err := fetchCached(cmd.Context(), lmCache, lmCache.recurringExpensesQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch recurring expenses: %w", err)
+	}
+
+	items := newRecurringExpenses(expenses, today())
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, items)
+	case tableOutputFormat:
+		t := createStyledTable("ID", "PAYEE", "CADENCE", "BILLING DATE", "NEXT DUE", "AMOUNT")
+		for _, item := range items {
+			nextDue := item.NextDue
+			if nextDue == "" {
+				nextDue = "-"
+			}
+			t.Row(
+				fmt.Sprint(item.ID),
+				item.Payee,
+				item.Cadence,
+				item.BillingDate,
+				nextDue,
+				item.Amount+" "+item.Currency,
+			)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), t)
+		return nil
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+func recurringUpcomingRun(cmd *cobra.Command, _ []string) error {
+	outputFormat, err := validateOutputFormat(cmd, icsOutputFormat)
+	if err != nil {
+		return err
+	}
+
+	days, _ := cmd.Flags().GetInt("days")
+	if days < 1 {
+		return fmt.Errorf("invalid number of days: %d (must be at least 1)", days)
+	}
+
+	expenses, err := fetchCached(cmd.Context(), lmCache, lmCache.recurringExpensesQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch recurring expenses: %w", err)
+	}
+
+	from := today()
+	occurrences := recurring.Project(expenses, from, from.AddDate(0, 0, days))
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, newUpcomingBills(occurrences))
+	case tableOutputFormat:
+		t := createStyledTable("DUE", "PAYEE", "CADENCE", "AMOUNT")
+		for _, o := range occurrences {
+			t.Row(o.Date.Format("Mon 2006-01-02"), o.Expense.Payee, o.Expense.Cadence, o.Amount.Display())
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), t)
+		return nil
+	case icsOutputFormat:
+		return recurring.WriteICS(cmd.OutOrStdout(), occurrences, time.Now())
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+// newRecurringExpenses converts the recurring expenses for output, with the next date
+// each is due on or after from. Expenses with an amount that cannot be parsed are left out.
+func newRecurringExpenses(expenses []*lm.RecurringExpense, from time.Time) []recurringExpense {
+	items := make([]recurringExpense, 0, len(expenses))
+	for _, r := range expenses {
+		amount, err := r.ParsedAmount()
+		if err != nil {
+			continue
+		}
+
+		item := recurringExpense{
+			ID:          r.ID,
+			Payee:       r.Payee,
+			Description: r.Description,
+			Cadence:     r.Cadence,
+			BillingDate: r.BillingDate,
+			Amount:      formatAmount(amount),
+			Currency:    amount.Currency().Code,
+		}
+		if dates, dueErr := recurring.Occurrences(r, from, from.AddDate(0, 0, nextDueHorizon)); dueErr == nil &&
+			len(dates) > 0 {
+			item.NextDue = dates[0].Format("2006-01-02")
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// newUpcomingBills converts the occurrences of recurring expenses for output.
+func newUpcomingBills(occurrences []recurring.Occurrence) []upcomingBill {
+	bills := make([]upcomingBill, len(occurrences))
+	for i, o := range occurrences {
+		bills[i] = upcomingBill{
+			Date:        o.Date.Format("2006-01-02"),
+			RecurringID: o.Expense.ID,
+			Payee:       o.Expense.Payee,
+			Description: o.Expense.Description,
+			Cadence:     o.Expense.Cadence,
+			Amount:      formatAmount(o.Amount),
+			Currency:    o.Amount.Currency().Code,
+		}
+	}
+	return bills
+}
+
+// today returns the start of the current day in local time.
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
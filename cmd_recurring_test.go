@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/recurring"
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestNewRecurringExpenses(t *testing.T) {
+	from := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	expenses := []*lm.RecurringExpense{
+		{ID: 1, Payee: "Netflix", Cadence: "monthly", BillingDate: "2024-06-15", Amount: "15.99", Currency: "usd"},
+		{ID: 2, Payee: "Domain", Cadence: "yearly", BillingDate: "2024-01-10", Amount: "12", Currency: "eur"},
+		{ID: 3, Payee: "Gym", Cadence: "whenever", BillingDate: "2025-01-01", Amount: "40", Currency: "usd"},
+		{ID: 4, Payee: "Broken", Cadence: "monthly", BillingDate: "2025-01-01", Amount: "n/a", Currency: "usd"},
+		{
+			ID: 5, Payee: "Old", Cadence: "monthly", BillingDate: "2024-01-01", EndDate: "2024-12-01",
+			Amount: "5", Currency: "usd",
+		},
+	}
+
+	items := newRecurringExpenses(expenses, from)
+
+	be.Equal(t, 4, len(items))
+	be.Equal(t, recurringExpense{
+		ID: 1, Payee: "Netflix", Cadence: "monthly", BillingDate: "2024-06-15",
+		Amount: "15.99", Currency: "USD", NextDue: "2025-02-15",
+	}, items[0])
+	be.Equal(t, "2026-01-10", items[1].NextDue)
+	be.Equal(t, "12.00", items[1].Amount)
+	be.Equal(t, "EUR", items[1].Currency)
+	// unknown cadence and ended expenses have no next due date
+	be.Equal(t, "", items[2].NextDue)
+	be.Equal(t, "", items[3].NextDue)
+}
+
+func TestNewUpcomingBills(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	occurrences := recurring.Project([]*lm.RecurringExpense{
+		{ID: 1, Payee: "Gym", Cadence: "every 2 weeks", BillingDate: "2025-01-03", Amount: "20", Currency: "usd"},
+	}, from, from.AddDate(0, 0, 30))
+
+	bills := newUpcomingBills(occurrences)
+
+	be.Equal(t, 3, len(bills))
+	be.Equal(t, upcomingBill{
+		Date: "2025-01-17", RecurringID: 1, Payee: "Gym", Cadence: "every 2 weeks", Amount: "20.00", Currency: "USD",
+	}, bills[1])
+	be.Equal(t, "2025-01-31", bills[2].Date)
+}
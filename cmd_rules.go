@@ -0,0 +1,268 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Rshep3087/lunchtui/rules"
+)
+
+// rulesCmd represents the rules command.
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Local categorization rules",
+	Long:  `Commands for the local categorization rules defined in the [[rules]] section of the config file.`,
+}
+
+// rulesApplyCmd represents the rules apply command.
+var rulesApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply rules to the transactions of a period",
+	Long: `Match the transactions of the period selected with --period, --start and --end (the current
+month by default) against the configured rules, preview the changes and apply them after confirmation.`,
+	RunE: rulesApplyRun,
+}
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesApplyCmd)
+
+	addPeriodFlags(rulesApplyCmd)
+	rulesApplyCmd.Flags().Bool("dry-run", false, "Show the changes without applying them")
+	rulesApplyCmd.Flags().BoolP("yes", "y", false, "Apply the changes without asking for confirmation")
+	rulesApplyCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+}
+
+// loadRules reads the [[rules]] section of the config file.
+func loadRules() ([]rules.Rule, error) {
+	var rs []rules.Rule
+	if err := viper.UnmarshalKey("rules", &rs); err != nil {
+		return nil, fmt.Errorf("failed to read rules from config: %w", err)
+	}
+	return rs, nil
+}
+
+// ruleMatch is a transaction matched by a rule and the update the rule makes.
+type ruleMatch struct {
+	t      *lm.Transaction
+	rule   string
+	update *transactionUpdate
+}
+
+// matchRules returns the transactions the rules would change.
+// Matches that would not change anything are skipped.
+func matchRules(engine *rules.Engine, ts []*lm.Transaction) []ruleMatch {
+	var matches []ruleMatch
+	for _, t := range ts {
+		action, ok := engine.Match(t)
+		if !ok {
+			continue
+		}
+		if update := newRuleUpdate(t, action); update != nil {
+			matches = append(matches, ruleMatch{t: t, rule: action.Rule, update: update})
+		}
+	}
+	return matches
+}
+
+// newRuleUpdate builds the update for a rule action, or nil if the transaction already matches it.
+func newRuleUpdate(t *lm.Transaction, action rules.Action) *transactionUpdate {
+	update := &transactionUpdate{}
+	if action.CategoryID != 0 && action.CategoryID != t.CategoryID {
+		update.CategoryID = ptr(int(action.CategoryID))
+	}
+	if action.Status != "" && action.Status != t.Status {
+		update.Status = ptr(action.Status)
+	}
+	if tagIDs := mergeTagIDs(t.Tags, action.TagIDs, nil); len(tagIDs) != len(t.Tags) {
+		update.Tags = &tagIDs
+	}
+
+	if isEmptyTransactionUpdate(update) {
+		return nil
+	}
+	return update
+}
+
+// ruleChange is a single field change made by a rule, used for previews.
+type ruleChange struct {
+	TransactionID int64  `json:"transaction_id"`
+	Date          string `json:"date"`
+	Payee         string `json:"payee"`
+	Rule          string `json:"rule"`
+	fieldChange
+}
+
+func rulesApplyRun(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, period, err := periodFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	configured, err := loadRules()
+	if err != nil {
+		return err
+	}
+	if len(configured) == 0 {
+		return errors.New("no rules configured (add [[rules]] entries to the config file)")
+	}
+
+	categories, err := lmc.GetCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch categories: %w", err)
+	}
+	tags, err := lmc.GetTags(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tags: %w", err)
+	}
+
+	engine, err := rules.Compile(configured, categories, tags)
+	if err != nil {
+		return fmt.Errorf("invalid rule %w", err)
+	}
+
+	ts, err := lmc.GetTransactions(ctx,
+		newTransactionFilters(period.startDate(), period.endDate(), viper.GetBool("debits_as_negative")),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	matches := matchRules(engine, ts)
+	changes := ruleChanges(matches, newUpdateNames(categories, tags))
+
+	log.Debug("rules matched", "rules", engine.Len(), "transactions", len(ts), "matches", len(matches))
+
+	if outputFormat == tableOutputFormat {
+		outputRuleChangesTable(cmd, &period, changes)
+	}
+
+	var applied bool
+	var applyErr error
+	if !dryRun && len(matches) > 0 {
+		if !yes {
+			if yes, err = confirmRuleMatches(len(matches)); err != nil {
+				return err
+			}
+		}
+		if yes {
+			applied = true
+			applyErr = applyRuleMatches(cmd, matches)
+		}
+	}
+
+	if outputFormat == jsonOutputFormat {
+		if err = outputJSON(cmd, struct {
+			DryRun  bool         `json:"dry_run"`
+			Applied bool         `json:"applied"`
+			Changes []ruleChange `json:"changes"`
+		}{DryRun: dryRun, Applied: applied, Changes: changes}); err != nil {
+			return err
+		}
+	} else if len(matches) > 0 && !applied {
+		fmt.Fprintln(cmd.OutOrStdout(), "No changes were applied")
+	}
+
+	return applyErr
+}
+
+// confirmRuleMatches asks whether the rules should be applied to the matched transactions.
+func confirmRuleMatches(count int) (bool, error) {
+	confirmed := false
+	err := huh.NewConfirm().
+		Title(fmt.Sprintf("Apply rules to %d transactions?", count)).
+		Value(&confirmed).
+		Run()
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm (use --yes to skip confirmation): %w", err)
+	}
+	return confirmed, nil
+}
+
+// applyRuleMatches updates each matched transaction and reports the failures.
+func applyRuleMatches(cmd *cobra.Command, matches []ruleMatch) error {
+	var failed int
+	for _, match := range matches {
+		resp, err := updateTransaction(cmd.Context(), lmc, match.t.ID, match.update)
+		if err == nil && !resp.Updated {
+			err = errors.New("transaction not updated")
+		}
+		if err != nil {
+			failed++
+			log.Error("failed to apply rule", "transaction", match.t.ID, "rule", match.rule, "error", err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d transactions could not be updated", failed, len(matches))
+	}
+
+	log.Infof("Rules applied to %d transactions", len(matches))
+	return nil
+}
+
+// newUpdateNames builds the names used to display rule changes.
+func newUpdateNames(categories []*lm.Category, tags []*lm.Tag) updateNames {
+	names := updateNames{
+		categories: make(map[int64]string, len(categories)),
+		tags:       make(map[int]string, len(tags)),
+	}
+	for _, c := range categories {
+		names.categories[c.ID] = c.Name
+	}
+	for _, t := range tags {
+		names.tags[t.ID] = t.Name
+	}
+	return names
+}
+
+// ruleChanges lists the field changes of every match.
+func ruleChanges(matches []ruleMatch, names updateNames) []ruleChange {
+	changes := make([]ruleChange, 0, len(matches))
+	for _, match := range matches {
+		for _, change := range diffTransactionUpdate(match.t, match.update, names) {
+			changes = append(changes, ruleChange{
+				TransactionID: match.t.ID,
+				Date:          match.t.Date,
+				Payee:         match.t.Payee,
+				Rule:          match.rule,
+				fieldChange:   change,
+			})
+		}
+	}
+	return changes
+}
+
+func outputRuleChangesTable(cmd *cobra.Command, period *Period, changes []ruleChange) {
+	if len(changes) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No rules matched transactions in %s\n", period)
+		return
+	}
+
+	t := createStyledTable("ID", "DATE", "PAYEE", "RULE", "FIELD", "BEFORE", "AFTER")
+	for _, c := range changes {
+		before := c.Before
+		if before == "" {
+			before = "-"
+		}
+		t.Row(strconv.FormatInt(c.TransactionID, 10), c.Date, c.Payee, c.Rule, c.Field, before, c.After)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), t)
+}
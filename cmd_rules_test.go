@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/viper"
+
+	"github.com/Rshep3087/lunchtui/rules"
+)
+
+func TestLoadRules(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	viper.SetConfigType("toml")
+	be.NilErr(t, viper.ReadConfig(strings.NewReader(`
+[[rules]]
+name = "coffee"
+payee = "starbucks"
+max_amount = 10
+category = "Coffee Shops"
+tags = ["coffee"]
+
+[[rules]]
+account = "Chase Checking"
+min_amount = 1.5
+status = "cleared"
+`)))
+
+	rs, err := loadRules()
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(rs))
+	be.Equal(t, "coffee", rs[0].Name)
+	be.Equal(t, 10.0, *rs[0].MaxAmount)
+	be.AllEqual(t, []string{"coffee"}, rs[0].Tags)
+	be.Equal(t, 1.5, *rs[1].MinAmount)
+	be.Equal(t, "Chase Checking", rs[1].Account)
+}
+
+func TestNewRuleUpdate(t *testing.T) {
+	trans := &lm.Transaction{
+		ID:         1,
+		Status:     unclearedStatus,
+		CategoryID: 10,
+		Tags:       []lm.Tag{{ID: 1, Name: "coffee"}},
+	}
+
+	tests := []struct {
+		name     string
+		action   rules.Action
+		expected *transactionUpdate
+	}{
+		{
+			name:     "already matches",
+			action:   rules.Action{CategoryID: 10, TagIDs: []int{1}},
+			expected: nil,
+		},
+		{
+			name:   "new category and status",
+			action: rules.Action{CategoryID: 11, Status: clearedStatus},
+			expected: &transactionUpdate{UpdateTransaction: lm.UpdateTransaction{
+				CategoryID: ptr(11),
+				Status:     ptr(clearedStatus),
+			}},
+		},
+		{
+			name:     "tags are added",
+			action:   rules.Action{TagIDs: []int{1, 2}},
+			expected: &transactionUpdate{Tags: &[]int{1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.DeepEqual(t, tt.expected, newRuleUpdate(trans, tt.action))
+		})
+	}
+}
+
+func TestRuleChanges(t *testing.T) {
+	engine, err := rules.Compile(
+		[]rules.Rule{{Name: "coffee", Payee: "starbucks", Category: "Coffee Shops"}},
+		[]*lm.Category{{ID: 10, Name: "Coffee Shops"}},
+		nil,
+	)
+	be.NilErr(t, err)
+
+	ts := []*lm.Transaction{
+		{ID: 1, Payee: "Starbucks", Date: "2024-01-02", CategoryName: "Dining", CategoryID: 5},
+		{ID: 2, Payee: "Starbucks", Date: "2024-01-03", CategoryName: "Coffee Shops", CategoryID: 10},
+		{ID: 3, Payee: "Market", Date: "2024-01-04"},
+	}
+
+	matches := matchRules(engine, ts)
+	be.Equal(t, 1, len(matches))
+
+	changes := ruleChanges(matches, updateNames{categories: map[int64]string{10: "Coffee Shops"}})
+	be.AllEqual(t, []ruleChange{{
+		TransactionID: 1,
+		Date:          "2024-01-02",
+		Payee:         "Starbucks",
+		Rule:          "coffee",
+		fieldChange:   fieldChange{Field: "category", Before: "Dining", After: "Coffee Shops"},
+	}}, changes)
+}
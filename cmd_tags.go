@@ -0,0 +1,272 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Rshep3087/lunchtui/totals"
+
+	"github.com/Rhymond/go-money"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// tagsCmd represents the tags command.
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Tag commands",
+	Long: `Commands for listing tags and reporting on spending by tag in Lunch Money. Tags are created
+by adding them to a transaction by name, such as from the tag editor in the TUI.`,
+}
+
+// tagsListCmd represents the tags list command.
+var tagsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all tags",
+	Long:  `List all tags with their IDs and descriptions.`,
+	RunE:  tagsListRun,
+}
+
+// tagsReportCmd represents the tags report command.
+var tagsReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report spending by tag and month",
+	Long: `Report the spending with each tag for each month in the period selected with --period,
+--start and --end, the current month by default. Spending is counted like in the overview:
+income and categories excluded from totals are left out, and amounts are in your primary
+currency. A transaction with several tags counts towards each of them.`,
+	RunE: tagsReportRun,
+}
+
+func init() {
+	tagsCmd.AddCommand(tagsListCmd)
+	tagsCmd.AddCommand(tagsReportCmd)
+
+	tagsListCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+	addPeriodFlags(tagsReportCmd)
+	tagsReportCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table, json or csv")
+}
+
+func tagsListRun(cmd *cobra.Command, _ []string) error {
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	tags, err := fetchCached(cmd.Context(), lmCache, lmCache.tagsQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch tags: %w", err)
+	}
+
+	// Sort tags by name for consistent output
+	slices.SortFunc(tags, func(a, b *lm.Tag) int {
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, tags)
+	case tableOutputFormat:
+		t := createStyledTable("ID", "NAME", "DESCRIPTION")
+		for _, tag := range tags {
+			t.Row(strconv.Itoa(tag.ID), tag.Name, cmp.Or(tag.Description, "-"))
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), t)
+		return nil
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+const (
+	// monthLayout is the layout of the month at the start of a transaction date
+	monthLayout = "2006-01"
+	percent     = 100
+)
+
+// tagSpendingRow is the spending with a tag in one month.
+type tagSpendingRow struct {
+	Month string `json:"month"`
+	TagID int    `json:"tag_id"`
+	Tag   string `json:"tag"`
+	Spent string `json:"spent"`
+	// Share is the percentage of the spending of the month with the tag
+	Share        float64 `json:"share"`
+	Currency     string  `json:"currency"`
+	Transactions int     `json:"transactions"`
+
+	spent *money.Money
+}
+
+func tagsReportRun(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	outputFormat, err := validateOutputFormat(cmd, csvOutputFormat)
+	if err != nil {
+		return err
+	}
+
+	_, period, err := periodFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	user, err := fetchCached(ctx, lmCache, lmCache.userQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch user info: %w", err)
+	}
+
+	categories, err := fetchCached(ctx, lmCache, lmCache.categoriesQuery())
+	if err != nil {
+		return fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	ts, err := fetchCached(ctx, lmCache,
+		lmCache.transactionsQuery(period.startDate(), period.endDate(), viper.GetBool("debits_as_negative")),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	// uncategorized spending counts like in the overview
+	idToCategory := map[int64]*lm.Category{0: {ID: 0, Name: "Uncategorized"}}
+	for _, c := range categories {
+		idToCategory[c.ID] = c
+	}
+
+	// grouped transactions are counted through their group, like in the TUI
+	ts, _ = collapseGroups(ts)
+	rows, unconverted := newTagSpendingRows(ts, idToCategory, cmp.Or(user.PrimaryCurrency, money.USD))
+	if unconverted > 0 {
+		log.Warn("left out transactions without an amount in the primary currency", "count", unconverted)
+	}
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, rows)
+	case tableOutputFormat:
+		return outputTagSpendingTable(cmd, rows)
+	case csvOutputFormat:
+		return outputTagSpendingCSV(cmd, rows)
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+// newTagSpendingRows adds up the spending with each tag for each month, ordered by month
+// and then by spending. It also returns how many transactions were left out because they
+// could not be converted to the currency.
+func newTagSpendingRows(
+	ts []*lm.Transaction,
+	categories map[int64]*lm.Category,
+	currency string,
+) ([]tagSpendingRow, int) {
+	type key struct {
+		month string
+		tagID int
+	}
+	byTag := make(map[key]*tagSpendingRow)
+	monthTotals := make(map[string]*money.Money)
+	unconverted := 0
+
+	for _, t := range ts {
+		category := categories[t.CategoryID]
+		if category == nil || category.ExcludeFromTotals || category.IsIncome || category.IsGroup ||
+			len(t.Date) < len(monthLayout) {
+			continue
+		}
+
+		amount, err := t.ParsedAmount()
+		if err != nil {
+			continue
+		}
+		amount, ok := totals.Convert(amount.Absolute(), math.Abs(t.ToBase), currency)
+		if !ok {
+			unconverted++
+			continue
+		}
+
+		month := t.Date[:len(monthLayout)]
+		total := cmp.Or(monthTotals[month], money.New(0, currency))
+		monthTotals[month], _ = total.Add(amount)
+
+		for _, tag := range t.Tags {
+			k := key{month: month, tagID: tag.ID}
+			row, exists := byTag[k]
+			if !exists {
+				row = &tagSpendingRow{
+					Month:    month,
+					TagID:    tag.ID,
+					Tag:      tag.Name,
+					Currency: currency,
+					spent:    money.New(0, currency),
+				}
+				byTag[k] = row
+			}
+			row.spent, _ = row.spent.Add(amount)
+			row.Transactions++
+		}
+	}
+
+	rows := make([]tagSpendingRow, 0, len(byTag))
+	for _, row := range byTag {
+		row.Spent = formatAmount(row.spent)
+		if total := monthTotals[row.Month]; total.IsPositive() {
+			row.Share = float64(row.spent.Amount()) / float64(total.Amount()) * percent
+		}
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b tagSpendingRow) int {
+		return cmp.Or(
+			strings.Compare(a.Month, b.Month),
+			cmp.Compare(b.spent.Amount(), a.spent.Amount()),
+			strings.Compare(a.Tag, b.Tag),
+		)
+	})
+	return rows, unconverted
+}
+
+func outputTagSpendingTable(cmd *cobra.Command, rows []tagSpendingRow) error {
+	if len(rows) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No spending with tags in this period")
+		return nil
+	}
+
+	t := createStyledTable("MONTH", "TAG", "SPENT", "SHARE", "TRANSACTIONS")
+	for _, row := range rows {
+		t.Row(
+			row.Month,
+			row.Tag,
+			row.spent.Display(),
+			fmt.Sprintf("%.1f%%", row.Share),
+			strconv.Itoa(row.Transactions),
+		)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), t)
+	return nil
+}
+
+func outputTagSpendingCSV(cmd *cobra.Command, rows []tagSpendingRow) error {
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = []string{
+			row.Month,
+			strconv.Itoa(row.TagID),
+			row.Tag,
+			row.Spent,
+			strconv.FormatFloat(row.Share, 'f', 1, 64),
+			row.Currency,
+			strconv.Itoa(row.Transactions),
+		}
+	}
+
+	return outputCSV(cmd, []string{"month", "tag_id", "tag", "spent", "share", "currency", "transactions"}, records)
+}
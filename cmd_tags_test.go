@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestNewTagSpendingRows(t *testing.T) {
+	categories := map[int64]*lm.Category{
+		1: {ID: 1, Name: "Groceries"},
+		2: {ID: 2, Name: "Salary", IsIncome: true},
+		3: {ID: 3, Name: "Transfers", ExcludeFromTotals: true},
+	}
+	vacation := lm.Tag{ID: 7, Name: "vacation"}
+	shared := lm.Tag{ID: 8, Name: "shared"}
+	ts := []*lm.Transaction{
+		{ID: 1, Date: "2024-01-05", CategoryID: 1, Amount: "60.00", Currency: "usd", Tags: []lm.Tag{vacation}},
+		{ID: 2, Date: "2024-01-10", CategoryID: 1, Amount: "40.00", Currency: "usd", Tags: []lm.Tag{vacation, shared}},
+		{ID: 3, Date: "2024-01-12", CategoryID: 1, Amount: "100.00", Currency: "usd"},
+		{ID: 4, Date: "2024-01-15", CategoryID: 2, Amount: "-500.00", Currency: "usd", Tags: []lm.Tag{vacation}},
+		{ID: 5, Date: "2024-01-20", CategoryID: 3, Amount: "80.00", Currency: "usd", Tags: []lm.Tag{vacation}},
+		{ID: 6, Date: "2024-02-01", CategoryID: 1, Amount: "25.00", Currency: "usd", Tags: []lm.Tag{shared}},
+		{ID: 7, Date: "2024-02-02", CategoryID: 1, Amount: "10.00", Currency: "eur", Tags: []lm.Tag{shared}},
+	}
+
+	rows, unconverted := newTagSpendingRows(ts, categories, "usd")
+	be.Equal(t, 1, unconverted)
+
+	type summary struct {
+		month, tag, spent string
+		share             float64
+		transactions      int
+	}
+	var got []summary
+	for _, row := range rows {
+		got = append(got, summary{row.Month, row.Tag, row.Spent, row.Share, row.Transactions})
+	}
+	be.AllEqual(t, []summary{
+		{"2024-01", "vacation", "100.00", 50, 2},
+		{"2024-01", "shared", "40.00", 20, 1},
+		{"2024-02", "shared", "25.00", 100, 1},
+	}, got)
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -27,9 +29,18 @@ var transactionInsertCmd = &cobra.Command{
 	RunE:  transactionInsertRun,
 }
 
+// transactionListCmd represents the transaction list command.
+var transactionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List transactions",
+	Long:  `List transactions from Lunch Money for a date range, optionally filtered.`,
+	RunE:  transactionListRun,
+}
+
 func init() {
-	// Add transaction insert subcommand
+	// Add transaction subcommands
 	transactionCmd.AddCommand(transactionInsertCmd)
+	transactionCmd.AddCommand(transactionListCmd)
 
 	// Transaction insert flags
 	transactionInsertCmd.Flags().String("payee", "", "The payee or merchant name (required)")
@@ -51,6 +62,18 @@ func init() {
 	// Mark required flags
 	_ = transactionInsertCmd.MarkFlagRequired("payee")
 	_ = transactionInsertCmd.MarkFlagRequired("amount")
+
+	// Transaction list flags
+	addPeriodFlags(transactionListCmd)
+	transactionListCmd.Flags().Int64("category", 0,
+		"Only include transactions with this category ID (0 for uncategorized)")
+	transactionListCmd.Flags().Int64("account", 0,
+		"Only include transactions for this account ID (plaid account or asset)")
+	transactionListCmd.Flags().Int64("tag", 0, "Only include transactions with this tag ID")
+	transactionListCmd.Flags().String("status", "",
+		"Only include transactions with this status (cleared, uncleared, pending)")
+	transactionListCmd.Flags().String("payee", "", "Only include transactions whose payee contains this text")
+	transactionListCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table, json or csv")
 }
 
 func transactionInsertRun(cmd *cobra.Command, _ []string) error {
@@ -71,32 +94,14 @@ func transactionInsertRun(cmd *cobra.Command, _ []string) error {
 	checkForRecurring, _ := cmd.Flags().GetBool("check-for-recurring")
 	skipBalanceUpdate, _ := cmd.Flags().GetBool("skip-balance-update")
 
-	// Validate and parse the amount
-	if _, err := strconv.ParseFloat(amountStr, 64); err != nil {
-		return fmt.Errorf("invalid amount: %s", amountStr)
-	}
-
-	// Validate date format
-	if _, err := time.Parse("2006-01-02", dateStr); err != nil {
-		return fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", dateStr)
-	}
-
-	// Validate status
-	if status != "cleared" && status != unclearedStatus {
-		return fmt.Errorf("invalid status: %s (must be 'cleared' or 'uncleared')", status)
+	if err := validateTransactionInput(amountStr, dateStr, status); err != nil {
+		return err
 	}
 
 	// Parse tag IDs
-	var tagIDs []int
-	if len(tagStrings) > 0 {
-		tagIDs = make([]int, 0, len(tagStrings))
-		for _, tagStr := range tagStrings {
-			tagID, err := strconv.Atoi(tagStr)
-			if err != nil {
-				return fmt.Errorf("invalid tag ID: %s", tagStr)
-			}
-			tagIDs = append(tagIDs, tagID)
-		}
+	tagIDs, err := parseTagIDs(tagStrings)
+	if err != nil {
+		return err
 	}
 
 	// Create the transaction
@@ -150,3 +155,226 @@ func transactionInsertRun(cmd *cobra.Command, _ []string) error {
 	log.Infof("Transaction inserted successfully with ID: %d", resp.IDs[0])
 	return nil
 }
+
+// validateTransactionInput checks the amount, date and status of a transaction before it is inserted.
+func validateTransactionInput(amount, date, status string) error {
+	// Validate and parse the amount
+	if _, err := strconv.ParseFloat(amount, 64); err != nil {
+		return fmt.Errorf("invalid amount: %s", amount)
+	}
+
+	// Validate date format
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", date)
+	}
+
+	// Validate status
+	if status != clearedStatus && status != unclearedStatus {
+		return fmt.Errorf("invalid status: %s (must be 'cleared' or 'uncleared')", status)
+	}
+
+	return nil
+}
+
+// parseTagIDs converts tag ID flag values into integers.
+func parseTagIDs(tagStrings []string) ([]int, error) {
+	if len(tagStrings) == 0 {
+		return nil, nil
+	}
+
+	tagIDs := make([]int, 0, len(tagStrings))
+	for _, tagStr := range tagStrings {
+		tagID, err := strconv.Atoi(tagStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag ID: %s", tagStr)
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+	return tagIDs, nil
+}
+
+func transactionListRun(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	// Get and validate output format
+	outputFormat, err := validateOutputFormat(cmd, csvOutputFormat)
+	if err != nil {
+		return err
+	}
+
+	_, period, err := periodFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	startDate, endDate := period.startDate(), period.endDate()
+
+	categoryID, _ := cmd.Flags().GetInt64("category")
+	accountID, _ := cmd.Flags().GetInt64("account")
+	tagID, _ := cmd.Flags().GetInt64("tag")
+	status, _ := cmd.Flags().GetString("status")
+	payee, _ := cmd.Flags().GetString("payee")
+
+	// Validate status
+	if status != "" && status != clearedStatus && status != unclearedStatus && status != pendingStatus {
+		return fmt.Errorf("invalid status: %s (must be 'cleared', 'uncleared' or 'pending')", status)
+	}
+
+	log.Debug("fetching transactions", "start", startDate, "end", endDate)
+
+	// every filter is applied locally so the whole period can be cached
+	ts, err := fetchCached(ctx, lmCache,
+		lmCache.transactionsQuery(startDate, endDate, viper.GetBool("debits_as_negative")),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	filtered := filterTransactions(ts, transactionListFilter{
+		uncategorized: cmd.Flags().Changed("category") && categoryID == 0,
+		categoryID:    categoryID,
+		tagID:         tagID,
+		accountID:     accountID,
+		status:        status,
+		payee:         payee,
+	})
+
+	// Output based on format
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, filtered)
+	case tableOutputFormat:
+		return outputTransactionsTable(cmd, filtered)
+	case csvOutputFormat:
+		return outputTransactionsCSV(cmd, filtered)
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+// transactionListFilter holds the filters applied to the transactions of a period.
+type transactionListFilter struct {
+	uncategorized bool
+	// categoryID matches the category or the category group of a transaction
+	categoryID int64
+	tagID      int64
+	accountID  int64
+	status     string
+	payee      string
+}
+
+// filterTransactions applies the local filters to the transactions returned by the API.
+func filterTransactions(ts []*lm.Transaction, f transactionListFilter) []*lm.Transaction {
+	filtered := make([]*lm.Transaction, 0, len(ts))
+	payee := strings.ToLower(f.payee)
+	for _, t := range ts {
+		if f.uncategorized && t.CategoryID != 0 {
+			continue
+		}
+		if f.categoryID != 0 && t.CategoryID != f.categoryID && t.CategoryGroupID != f.categoryID {
+			continue
+		}
+		if f.tagID != 0 && !slices.ContainsFunc(t.Tags, func(tag lm.Tag) bool { return int64(tag.ID) == f.tagID }) {
+			continue
+		}
+		if f.accountID != 0 && t.PlaidAccountID != f.accountID && t.AssetID != f.accountID {
+			continue
+		}
+		if f.status != "" && t.Status != f.status {
+			continue
+		}
+		if payee != "" && !strings.Contains(strings.ToLower(t.Payee), payee) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered
+}
+
+// transactionAccountName returns the display name of the account a transaction belongs to.
+func transactionAccountName(t *lm.Transaction) string {
+	switch {
+	case t.PlaidAccountID != 0:
+		return unescapeDisplayName(t.PlaidAccountName, t.PlaidAccountDisplayName)
+	case t.AssetID != 0:
+		return unescapeDisplayName(t.AssetName, t.AssetDisplayName)
+	default:
+		return "Cash"
+	}
+}
+
+// transactionCategoryName returns the category name of a transaction or "Uncategorized".
+func transactionCategoryName(t *lm.Transaction) string {
+	if t.CategoryID == 0 || t.CategoryName == "" {
+		return "Uncategorized"
+	}
+	return t.CategoryName
+}
+
+// transactionTagNames returns the tag names of a transaction joined by commas.
+func transactionTagNames(t *lm.Transaction) string {
+	names := make([]string, len(t.Tags))
+	for i, tag := range t.Tags {
+		names[i] = tag.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func outputTransactionsTable(cmd *cobra.Command, ts []*lm.Transaction) error {
+	// Create table
+	t := createStyledTable("ID", "DATE", "PAYEE", "AMOUNT", "CATEGORY", "ACCOUNT", "STATUS", "TAGS", "NOTES")
+
+	// Add transactions to table
+	for _, tr := range ts {
+		amount := tr.Amount
+		if parsed, err := tr.ParsedAmount(); err == nil {
+			amount = parsed.Display()
+		}
+		tags := transactionTagNames(tr)
+		if tags == "" {
+			tags = "-"
+		}
+		notes := tr.Notes
+		if notes == "" {
+			notes = "-"
+		}
+		t.Row(
+			strconv.FormatInt(tr.ID, 10),
+			tr.Date,
+			tr.Payee,
+			amount,
+			transactionCategoryName(tr),
+			transactionAccountName(tr),
+			tr.Status,
+			tags,
+			notes,
+		)
+	}
+
+	// Print the table
+	fmt.Fprintln(cmd.OutOrStdout(), t)
+
+	return nil
+}
+
+func outputTransactionsCSV(cmd *cobra.Command, ts []*lm.Transaction) error {
+	rows := make([][]string, len(ts))
+	for i, t := range ts {
+		rows[i] = []string{
+			strconv.FormatInt(t.ID, 10),
+			t.Date,
+			t.Payee,
+			t.Amount,
+			t.Currency,
+			strconv.FormatInt(t.CategoryID, 10),
+			transactionCategoryName(t),
+			transactionAccountName(t),
+			t.Status,
+			transactionTagNames(t),
+			t.Notes,
+		}
+	}
+
+	return outputCSV(cmd, []string{
+		"id", "date", "payee", "amount", "currency", "category_id", "category", "account", "status", "tags", "notes",
+	}, rows)
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// transactionCategorizeCmd represents the transaction categorize command.
+var transactionCategorizeCmd = &cobra.Command{
+	Use:   "categorize",
+	Short: "Categorize uncategorized transactions",
+	Long: `Recommend categories for the uncategorized transactions in a date range using the
+configured AI provider. Recommendations at or above the --threshold confidence are accepted
+automatically, the others can be accepted from a list before the transactions are updated.`,
+	RunE: transactionCategorizeRun,
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionCategorizeCmd)
+
+	addPeriodFlags(transactionCategorizeCmd)
+	transactionCategorizeCmd.Flags().Bool("ai", false, "Use the configured AI provider to recommend categories")
+	transactionCategorizeCmd.Flags().Float64("threshold", 0,
+		"Accept recommendations at or above this confidence (0-100, defaults to ai.auto_accept_confidence)")
+	transactionCategorizeCmd.Flags().BoolP("yes", "y", false,
+		"Only apply the recommendations above the threshold without asking about the rest")
+	transactionCategorizeCmd.Flags().Bool("dry-run", false, "Show the recommendations without applying them")
+	transactionCategorizeCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+
+	// AI is the only way to categorize from the command line for now
+	_ = transactionCategorizeCmd.MarkFlagRequired("ai")
+}
+
+// aiSuggestion is an AI category recommendation shown by the categorize command.
+type aiSuggestion struct {
+	TransactionID int64                   `json:"transaction_id"`
+	Date          string                  `json:"date"`
+	Payee         string                  `json:"payee"`
+	Amount        string                  `json:"amount"`
+	CategoryID    int64                   `json:"category_id,omitempty"`
+	Category      string                  `json:"category,omitempty"`
+	Confidence    float64                 `json:"confidence"`
+	Reasoning     string                  `json:"reasoning,omitempty"`
+	Examples      []CategorizationExample `json:"examples,omitempty"`
+	Error         string                  `json:"error,omitempty"`
+	Accepted      bool                    `json:"accepted"`
+	AutoAccepted  bool                    `json:"auto_accepted"`
+}
+
+// categorizeOptions holds the flags of the categorize command.
+type categorizeOptions struct {
+	startDate string
+	endDate   string
+	threshold float64
+	dryRun    bool
+	yes       bool
+}
+
+func categorizeOptionsFromFlags(cmd *cobra.Command, aiConfig AIConfig) (categorizeOptions, error) {
+	var opts categorizeOptions
+	// --ai is required, but --ai=false would still pass that check
+	if ai, _ := cmd.Flags().GetBool("ai"); !ai {
+		return opts, errors.New("categorizing without --ai is not supported yet")
+	}
+
+	_, period, err := periodFromFlags(cmd)
+	if err != nil {
+		return opts, err
+	}
+	opts.startDate, opts.endDate = period.startDate(), period.endDate()
+	opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.yes, _ = cmd.Flags().GetBool("yes")
+
+	opts.threshold = aiConfig.AutoAcceptConfidence
+	if cmd.Flags().Changed("threshold") {
+		opts.threshold, _ = cmd.Flags().GetFloat64("threshold")
+	}
+	if opts.threshold < 0 || opts.threshold > maxConfidenceScore {
+		return opts, fmt.Errorf("invalid threshold: %.0f (must be between 0 and 100)", opts.threshold)
+	}
+
+	return opts, nil
+}
+
+func transactionCategorizeRun(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	aiConfig := newAIConfig()
+	opts, err := categorizeOptionsFromFlags(cmd, aiConfig)
+	if err != nil {
+		return err
+	}
+
+	if err = validateAIConfig(aiConfig); err != nil {
+		return err
+	}
+	provider := newAIProvider(aiConfig)
+	if provider == nil {
+		return errors.New("no AI provider configured (see the [ai] section of the config file)")
+	}
+
+	categories, ts, err := fetchUncategorizedTransactions(ctx, opts.startDate, opts.endDate)
+	if err != nil {
+		return err
+	}
+	if len(ts) == 0 && outputFormat == tableOutputFormat {
+		fmt.Fprintf(cmd.OutOrStdout(), "No uncategorized transactions between %s and %s\n",
+			opts.startDate, opts.endDate)
+		return nil
+	}
+
+	log.Debug("requesting AI recommendations", "transactions", len(ts), "threshold", opts.threshold)
+
+	batchCtx, cancel := context.WithTimeout(ctx, aiBatchTimeout)
+	defer cancel()
+	history := newAIHistory(aiConfig, lmc, viper.GetBool("debits_as_negative"))
+	results := NewAIRecommender(provider, history).RecommendCategories(batchCtx, ts, categories)
+	suggestions := newAISuggestions(results, opts.threshold)
+
+	if outputFormat == tableOutputFormat {
+		outputAISuggestionsTable(cmd, suggestions)
+	}
+
+	if !opts.dryRun && !opts.yes {
+		if err = selectAISuggestions(suggestions); err != nil {
+			return err
+		}
+	}
+
+	var applyErr error
+	if !opts.dryRun {
+		applyErr = applyAISuggestions(cmd, suggestions)
+	}
+
+	if outputFormat == jsonOutputFormat {
+		if err = outputJSON(cmd, suggestions); err != nil {
+			return err
+		}
+	}
+	return applyErr
+}
+
+// fetchUncategorizedTransactions returns the assignable categories and the uncategorized
+// transactions in the date range. Pending transactions are skipped because they cannot be updated.
+func fetchUncategorizedTransactions(
+	ctx context.Context,
+	startDate, endDate string,
+) ([]*lm.Category, []*lm.Transaction, error) {
+	categories, err := lmc.GetCategories(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	ts, err := lmc.GetTransactions(ctx,
+		newTransactionFilters(startDate, endDate, viper.GetBool("debits_as_negative")),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	uncategorized := make([]*lm.Transaction, 0, len(ts))
+	for _, t := range filterTransactions(ts, transactionListFilter{uncategorized: true}) {
+		if t.Status != pendingStatus {
+			uncategorized = append(uncategorized, t)
+		}
+	}
+	return assignableCategories(categories), uncategorized, nil
+}
+
+// newAISuggestions converts the batch results, accepting the recommendations at or above
+// the threshold. A zero threshold accepts nothing automatically.
+func newAISuggestions(results []aiBatchResult, threshold float64) []aiSuggestion {
+	suggestions := make([]aiSuggestion, 0, len(results))
+	for _, r := range results {
+		s := aiSuggestion{
+			TransactionID: r.Transaction.ID,
+			Date:          r.Transaction.Date,
+			Payee:         r.Transaction.Payee,
+			Amount:        r.Transaction.Amount,
+		}
+		switch {
+		case r.Err != nil:
+			s.Error = r.Err.Error()
+		case r.Recommendation != nil:
+			s.CategoryID = r.Recommendation.CategoryID
+			s.Category = r.Recommendation.CategoryName
+			s.Confidence = r.Recommendation.Confidence
+			s.Reasoning = r.Recommendation.Reasoning
+			s.Examples = r.Recommendation.Examples
+			s.AutoAccepted = threshold > 0 && s.Confidence >= threshold
+			s.Accepted = s.AutoAccepted
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions
+}
+
+// selectAISuggestions asks which of the recommendations below the threshold should be accepted.
+func selectAISuggestions(suggestions []aiSuggestion) error {
+	var opts []huh.Option[int]
+	for i, s := range suggestions {
+		if s.Error != "" || s.AutoAccepted {
+			continue
+		}
+		label := fmt.Sprintf("%s %s (%s) → %s (%.0f%%)", s.Date, s.Payee, s.Amount, s.Category, s.Confidence)
+		opts = append(opts, huh.NewOption(label, i))
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+
+	var selected []int
+	err := huh.NewMultiSelect[int]().
+		Title("Accept recommendations").
+		Description("Recommendations above the threshold are already accepted").
+		Options(opts...).
+		Value(&selected).
+		Run()
+	if err != nil {
+		return fmt.Errorf("failed to select recommendations (use --yes to skip selection): %w", err)
+	}
+
+	for _, i := range selected {
+		suggestions[i].Accepted = true
+	}
+	return nil
+}
+
+// applyAISuggestions categorizes and reviews the transactions of the accepted suggestions.
+func applyAISuggestions(cmd *cobra.Command, suggestions []aiSuggestion) error {
+	var applied, failed int
+	for _, s := range suggestions {
+		if !s.Accepted {
+			continue
+		}
+
+		// categorizing also reviews the transaction, like the TUI does
+		update := &transactionUpdate{UpdateTransaction: lm.UpdateTransaction{
+			CategoryID: ptr(int(s.CategoryID)),
+			Status:     ptr(clearedStatus),
+		}}
+		resp, err := updateTransaction(cmd.Context(), lmc, s.TransactionID, update)
+		if err == nil && !resp.Updated {
+			err = errors.New("transaction not updated")
+		}
+		if err != nil {
+			failed++
+			log.Error("failed to categorize transaction", "transaction", s.TransactionID, "error", err)
+			continue
+		}
+		applied++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d transactions could not be categorized", failed, applied+failed)
+	}
+
+	log.Infof("Categorized %d transactions", applied)
+	return nil
+}
+
+func outputAISuggestionsTable(cmd *cobra.Command, suggestions []aiSuggestion) {
+	t := createStyledTable("ID", "DATE", "PAYEE", "AMOUNT", "SUGGESTION", "CONFIDENCE", "STATUS")
+	for _, s := range suggestions {
+		suggestion, confidence, status := s.Category, fmt.Sprintf("%.0f%%", s.Confidence), "pending"
+		switch {
+		case s.Error != "":
+			suggestion, confidence, status = "-", "-", "failed: "+s.Error
+		case s.AutoAccepted:
+			status = "accepted"
+		}
+		t.Row(strconv.FormatInt(s.TransactionID, 10), s.Date, s.Payee, s.Amount, suggestion, confidence, status)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), t)
+}
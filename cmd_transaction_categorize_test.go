@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+)
+
+func TestCategorizeOptionsRequireAI(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("ai", false, "")
+	be.NilErr(t, cmd.Flags().Set("ai", "false"))
+
+	_, err := categorizeOptionsFromFlags(cmd, AIConfig{})
+	be.In(t, "--ai", err.Error())
+}
+
+func TestNewAISuggestions(t *testing.T) {
+	results := []aiBatchResult{
+		{
+			Transaction:    &lm.Transaction{ID: 1, Payee: "Starbucks"},
+			Recommendation: &CategoryRecommendation{CategoryID: 10, CategoryName: "Coffee Shops", Confidence: 90},
+		},
+		{
+			Transaction:    &lm.Transaction{ID: 2, Payee: "Market"},
+			Recommendation: &CategoryRecommendation{CategoryID: 11, CategoryName: "Groceries", Confidence: 50},
+		},
+		{
+			Transaction: &lm.Transaction{ID: 3, Payee: "Unknown"},
+			Err:         errors.New("boom"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		accepted  []bool
+	}{
+		{name: "threshold disabled", threshold: 0, accepted: []bool{false, false, false}},
+		{name: "high threshold", threshold: 90, accepted: []bool{true, false, false}},
+		{name: "low threshold", threshold: 1, accepted: []bool{true, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions := newAISuggestions(results, tt.threshold)
+			be.Equal(t, len(results), len(suggestions))
+			for i, s := range suggestions {
+				be.Equal(t, tt.accepted[i], s.Accepted)
+				be.Equal(t, tt.accepted[i], s.AutoAccepted)
+			}
+			be.Equal(t, "Coffee Shops", suggestions[0].Category)
+			be.Equal(t, "boom", suggestions[2].Error)
+		})
+	}
+}
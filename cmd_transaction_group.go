@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+)
+
+// transactionGroupCmd represents the transaction group command.
+var transactionGroupCmd = &cobra.Command{
+	Use:   "group <id> <id>...",
+	Short: "Group transactions into one transaction",
+	Long: `Group two or more transactions into a new transaction with its own payee, category and notes.
+The group is dated on the earliest of the transactions unless --date is set.`,
+	Args: cobra.MinimumNArgs(minGroupTransactions),
+	RunE: transactionGroupRun,
+}
+
+// transactionUngroupCmd represents the transaction ungroup command.
+var transactionUngroupCmd = &cobra.Command{
+	Use:   "ungroup <group-id>",
+	Short: "Ungroup a group transaction",
+	Long:  `Delete a group transaction, restoring the transactions that were grouped in it.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  transactionUngroupRun,
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionGroupCmd)
+	transactionCmd.AddCommand(transactionUngroupCmd)
+
+	// Transaction group flags
+	transactionGroupCmd.Flags().String("payee", "", "The payee for the group transaction (required)")
+	transactionGroupCmd.Flags().Int64("category", 0, "Category ID for the group transaction")
+	transactionGroupCmd.Flags().String("notes", "", "Notes for the group transaction")
+	transactionGroupCmd.Flags().String("date", "",
+		"Date of the group transaction (YYYY-MM-DD, defaults to the earliest grouped transaction)")
+	transactionGroupCmd.Flags().StringSlice("tags", []string{}, "Tag IDs (can be specified multiple times)")
+	transactionGroupCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+	_ = transactionGroupCmd.MarkFlagRequired("payee")
+
+	// Transaction ungroup flags
+	transactionUngroupCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+}
+
+// groupResult is the JSON output of the group and ungroup commands.
+type groupResult struct {
+	ID           int64   `json:"id"`
+	Transactions []int64 `json:"transactions"`
+}
+
+func transactionGroupRun(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	ids, err := parseTransactionIDs(args)
+	if err != nil {
+		return err
+	}
+
+	// the transactions are fetched to date the group and to check they are not grouped already
+	ts := make([]*lm.Transaction, 0, len(ids))
+	for _, id := range ids {
+		t, getErr := lmc.GetTransaction(ctx, id, nil)
+		if getErr != nil {
+			return fmt.Errorf("failed to fetch transaction %d: %w", id, getErr)
+		}
+		ts = append(ts, t)
+	}
+
+	payee, _ := cmd.Flags().GetString("payee")
+	categoryID, _ := cmd.Flags().GetInt64("category")
+	notes, _ := cmd.Flags().GetString("notes")
+	group, err := newTransactionGroup(ts, payee, categoryID, notes)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("date") {
+		group.Date, _ = cmd.Flags().GetString("date")
+		if _, err = time.Parse("2006-01-02", group.Date); err != nil {
+			return fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", group.Date)
+		}
+	}
+
+	tagStrings, _ := cmd.Flags().GetStringSlice("tags")
+	if group.Tags, err = parseTagIDs(tagStrings); err != nil {
+		return err
+	}
+
+	log.Debug("grouping transactions", "group", group)
+
+	id, err := createTransactionGroup(ctx, lmc, group)
+	if err != nil {
+		return fmt.Errorf("failed to group transactions: %w", err)
+	}
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, groupResult{ID: id, Transactions: group.Transactions})
+	case tableOutputFormat:
+		log.Infof("Grouped %d transactions into transaction %d", len(group.Transactions), id)
+		return nil
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+func transactionUngroupRun(cmd *cobra.Command, args []string) error {
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid transaction ID: %s", args[0])
+	}
+
+	log.Debug("ungrouping transaction", "id", id)
+
+	ids, err := deleteTransactionGroup(cmd.Context(), lmc, id)
+	if err != nil {
+		return fmt.Errorf("failed to ungroup transaction: %w", err)
+	}
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, groupResult{ID: id, Transactions: ids})
+	case tableOutputFormat:
+		log.Infof("Ungrouped transaction %d into %d transactions", id, len(ids))
+		return nil
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+// parseTransactionIDs converts transaction ID arguments into integers.
+func parseTransactionIDs(args []string) ([]int64, error) {
+	ids := make([]int64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction ID: %s", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+)
+
+func TestParseTransactionIDs(t *testing.T) {
+	ids, err := parseTransactionIDs([]string{"12", "34"})
+	be.NilErr(t, err)
+	be.AllEqual(t, []int64{12, 34}, ids)
+
+	_, err = parseTransactionIDs([]string{"12", "abc"})
+	be.Equal(t, "invalid transaction ID: abc", err.Error())
+}
+
+// useTestAPI points the CLI client at a fake API with a response for each method and path,
+// and returns the requests it received.
+func useTestAPI(t *testing.T, responses map[string]string) *[]recordedRequest {
+	t.Helper()
+
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{method: r.Method, path: r.URL.Path, body: body})
+		response, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := lm.NewClient("token")
+	be.NilErr(t, err)
+	client.Base, err = url.Parse(server.URL)
+	be.NilErr(t, err)
+
+	previous := lmc
+	lmc = client
+	t.Cleanup(func() { lmc = previous })
+	return &requests
+}
+
+// runGroupCommand runs a group command with the flags and returns its output.
+func runGroupCommand(t *testing.T, cmd *cobra.Command, flags map[string]string, args ...string) (string, error) {
+	t.Helper()
+
+	for name, value := range flags {
+		be.NilErr(t, cmd.Flags().Set(name, value))
+	}
+	// the commands are shared, so the flags are reset for the next test
+	t.Cleanup(func() {
+		for name := range flags {
+			f := cmd.Flags().Lookup(name)
+			if slice, ok := f.Value.(interface{ Replace([]string) error }); ok {
+				_ = slice.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		}
+	})
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetContext(t.Context())
+	err := cmd.RunE(cmd, args)
+	return out.String(), err
+}
+
+func TestTransactionGroupRun(t *testing.T) {
+	requests := useTestAPI(t, map[string]string{
+		"GET /v1/transactions/12":     `{"id": 12, "date": "2025-01-05", "payee": "Coffee", "amount": "4.50"}`,
+		"GET /v1/transactions/34":     `{"id": 34, "date": "2025-01-03", "payee": "Bagel", "amount": "3.00"}`,
+		"POST /v1/transactions/group": "84",
+	})
+
+	out, err := runGroupCommand(t, transactionGroupCmd, map[string]string{
+		"payee":    "Breakfast",
+		"category": "7",
+		"notes":    " with team ",
+		"tags":     "1,2",
+		"output":   jsonOutputFormat,
+	}, "12", "34")
+	be.NilErr(t, err)
+
+	var result groupResult
+	be.NilErr(t, json.Unmarshal([]byte(out), &result))
+	be.DeepEqual(t, groupResult{ID: 84, Transactions: []int64{12, 34}}, result)
+
+	post := (*requests)[len(*requests)-1]
+	be.Equal(t, http.MethodPost, post.method)
+	var body map[string]any
+	be.NilErr(t, json.Unmarshal(post.body, &body))
+	be.DeepEqual(t, map[string]any{
+		"date":         "2025-01-03",
+		"payee":        "Breakfast",
+		"category_id":  float64(7),
+		"notes":        "with team",
+		"tags":         []any{float64(1), float64(2)},
+		"transactions": []any{float64(12), float64(34)},
+	}, body)
+}
+
+func TestTransactionGroupRunUnexpectedResponse(t *testing.T) {
+	useTestAPI(t, map[string]string{
+		"GET /v1/transactions/12":     `{"id": 12, "date": "2025-01-05", "amount": "4.50"}`,
+		"GET /v1/transactions/34":     `{"id": 34, "date": "2025-01-03", "amount": "3.00"}`,
+		"POST /v1/transactions/group": `{"id": 84}`,
+	})
+
+	_, err := runGroupCommand(t, transactionGroupCmd, map[string]string{"payee": "Breakfast"}, "12", "34")
+	be.In(t, "decode response", err.Error())
+}
+
+func TestTransactionUngroupRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected groupResult
+		err      string
+	}{
+		{
+			name:     "ungroups",
+			response: `{"transactions": [12, 34]}`,
+			expected: groupResult{ID: 84, Transactions: []int64{12, 34}},
+		},
+		{
+			name:     "unexpected response",
+			response: `[12, 34]`,
+			err:      "decode response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := useTestAPI(t, map[string]string{"DELETE /v1/transactions/group/84": tt.response})
+
+			out, err := runGroupCommand(t, transactionUngroupCmd,
+				map[string]string{"output": jsonOutputFormat}, "84")
+			be.Equal(t, 1, len(*requests))
+			be.Equal(t, http.MethodDelete, (*requests)[0].method)
+			if tt.err != "" {
+				be.In(t, tt.err, err.Error())
+				return
+			}
+			be.NilErr(t, err)
+
+			var result groupResult
+			be.NilErr(t, json.NewDecoder(strings.NewReader(out)).Decode(&result))
+			be.DeepEqual(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Rshep3087/lunchtui/importer"
+)
+
+const (
+	// defaultImportBatchSize is the number of transactions sent per insert request.
+	defaultImportBatchSize = 100
+	// maxExternalIDLength is the longest external ID the API accepts.
+	maxExternalIDLength = 75
+	// importPayeeHistoryDays is how far back existing payees are loaded to resolve imported payees.
+	importPayeeHistoryDays = 365
+)
+
+// Import row results.
+const (
+	importResultInserted  = "inserted"
+	importResultSubmitted = "submitted"
+	importResultPending   = "would insert"
+	importResultInvalid   = "invalid"
+	importResultFailed    = "failed"
+)
+
+// transactionImportCmd represents the transaction import command.
+var transactionImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import transactions from a CSV, OFX or QIF file",
+	Long: `Import transactions from a CSV, OFX or QIF file into Lunch Money.
+
+Categories and accounts are resolved by name (or ID). Payees are matched against the payees
+of the last year of transactions so they keep their existing spelling. CSV columns are mapped with --map,
+for example --map date=Posted,amount=Debit,payee=Description.`,
+	Args: cobra.ExactArgs(1),
+	RunE: transactionImportRun,
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionImportCmd)
+
+	transactionImportCmd.Flags().String("format", "", "File format: csv, ofx or qif (detected from the extension)")
+	transactionImportCmd.Flags().StringToString("map", map[string]string{},
+		"CSV column mapping as field=column "+
+			"(fields: date, amount, payee, category, account, notes, currency, external_id)")
+	transactionImportCmd.Flags().String("date-format", "2006-01-02", "Go time layout of the CSV date column")
+	transactionImportCmd.Flags().String("account", "",
+		"Account name or ID for rows without an account (plaid account or asset)")
+	transactionImportCmd.Flags().String("currency", "usd", "Currency code for rows without a currency")
+	transactionImportCmd.Flags().String("status", unclearedStatus, "Transaction status (cleared, uncleared)")
+	transactionImportCmd.Flags().Bool("apply-rules", true, "Apply rules to the transactions")
+	transactionImportCmd.Flags().Bool("skip-duplicates", true, "Skip duplicate transactions")
+	transactionImportCmd.Flags().Bool("check-for-recurring", true, "Check for recurring transactions")
+	transactionImportCmd.Flags().Bool("skip-balance-update", false, "Skip balance update")
+	transactionImportCmd.Flags().Int("batch-size", defaultImportBatchSize, "Number of transactions per insert request")
+	transactionImportCmd.Flags().Bool("dry-run", false, "Validate the file and show what would be imported")
+	transactionImportCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+}
+
+// importResult is the outcome of importing a single row.
+type importResult struct {
+	Row    int    `json:"row"`
+	Date   string `json:"date"`
+	Payee  string `json:"payee"`
+	Amount string `json:"amount"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+	ID     int64  `json:"id,omitempty"`
+}
+
+// importOptions holds the defaults applied to every imported row.
+type importOptions struct {
+	account  string
+	currency string
+	status   string
+}
+
+func transactionImportRun(cmd *cobra.Command, args []string) error {
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts := importOptions{}
+	opts.account, _ = cmd.Flags().GetString("account")
+	opts.currency, _ = cmd.Flags().GetString("currency")
+	opts.status, _ = cmd.Flags().GetString("status")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if opts.status != clearedStatus && opts.status != unclearedStatus {
+		return fmt.Errorf("invalid status: %s (must be 'cleared' or 'uncleared')", opts.status)
+	}
+	if batchSize < 1 {
+		return fmt.Errorf("invalid batch size: %d", batchSize)
+	}
+
+	rows, format, err := parseImportFile(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return errors.New("no transactions found in file")
+	}
+
+	resolver, err := newImportResolver(cmd)
+	if err != nil {
+		return err
+	}
+
+	results, transactions := buildImportTransactions(rows, resolver, opts)
+
+	if !dryRun {
+		request := lm.InsertTransactionsRequest{
+			ApplyRules:        flagBool(cmd, "apply-rules"),
+			SkipDuplicates:    flagBool(cmd, "skip-duplicates"),
+			CheckForRecurring: flagBool(cmd, "check-for-recurring"),
+			// OFX and QIF files always write debits as negative amounts.
+			DebitAsNegative:   format != importer.FormatCSV || viper.GetBool("debits_as_negative"),
+			SkipBalanceUpdate: flagBool(cmd, "skip-balance-update"),
+		}
+		insertImportBatches(cmd, request, transactions, results, batchSize)
+	}
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		err = outputJSON(cmd, results)
+	case tableOutputFormat:
+		err = outputImportResultsTable(cmd, results)
+	default:
+		err = errors.New("unsupported output format")
+	}
+	if err != nil {
+		return err
+	}
+
+	if failed := countFailedImports(results); failed > 0 {
+		return fmt.Errorf("%d of %d rows could not be imported", failed, len(results))
+	}
+	return nil
+}
+
+// flagBool returns the value of a boolean flag, ignoring lookup errors.
+func flagBool(cmd *cobra.Command, name string) bool {
+	v, _ := cmd.Flags().GetBool(name)
+	return v
+}
+
+// parseImportFile opens the file and parses it with the parser for its format.
+func parseImportFile(cmd *cobra.Command, path string) ([]importer.Row, importer.Format, error) {
+	formatName, _ := cmd.Flags().GetString("format")
+	format, err := importer.ParseFormat(formatName, path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer f.Close()
+
+	var rows []importer.Row
+	switch format {
+	case importer.FormatCSV:
+		overrides, _ := cmd.Flags().GetStringToString("map")
+		mapping, mapErr := importer.DefaultCSVMapping().WithOverrides(overrides)
+		if mapErr != nil {
+			return nil, "", mapErr
+		}
+		mapping.DateLayout, _ = cmd.Flags().GetString("date-format")
+		rows, err = importer.ParseCSV(f, mapping)
+	case importer.FormatOFX:
+		rows, err = importer.ParseOFX(f)
+	case importer.FormatQIF:
+		rows, err = importer.ParseQIF(f)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	log.Debug("parsed import file", "path", path, "format", format, "rows", len(rows))
+	return rows, format, nil
+}
+
+// importResolver resolves category and account names to their IDs and payees to their
+// existing spelling. Names are matched case-insensitively.
+type importResolver struct {
+	payees        map[string]string
+	categories    map[string]int64
+	categoryIDs   map[int64]bool
+	assets        map[string]int64
+	assetIDs      map[int64]bool
+	plaidAccounts map[string]int64
+	plaidIDs      map[int64]bool
+}
+
+// newImportResolver fetches the payees, categories and accounts used to resolve import rows.
+func newImportResolver(cmd *cobra.Command) (*importResolver, error) {
+	ctx := cmd.Context()
+
+	now := time.Now()
+	existing, err := lmc.GetTransactions(ctx, newTransactionFilters(
+		now.AddDate(0, 0, -importPayeeHistoryDays).Format(time.DateOnly),
+		now.Format(time.DateOnly),
+		viper.GetBool("debits_as_negative"),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	categories, err := lmc.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	assets, plaidAccounts, err := fetchAssetsAndPlaidAccountsParallel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &importResolver{
+		payees:        make(map[string]string),
+		categories:    make(map[string]int64),
+		categoryIDs:   make(map[int64]bool),
+		assets:        make(map[string]int64),
+		assetIDs:      make(map[int64]bool),
+		plaidAccounts: make(map[string]int64),
+		plaidIDs:      make(map[int64]bool),
+	}
+	for _, t := range existing {
+		payee := cleanImportText(t.Payee)
+		if key := strings.ToLower(payee); key != "" && r.payees[key] == "" {
+			r.payees[key] = payee
+		}
+	}
+	for _, c := range categories {
+		// Transactions cannot be assigned to category groups
+		if c.IsGroup {
+			continue
+		}
+		r.categories[strings.ToLower(c.Name)] = c.ID
+		r.categoryIDs[c.ID] = true
+	}
+	for _, a := range assets {
+		r.assets[strings.ToLower(a.Name)] = a.ID
+		if a.DisplayName != "" {
+			r.assets[strings.ToLower(a.DisplayName)] = a.ID
+		}
+		r.assetIDs[a.ID] = true
+	}
+	for _, p := range plaidAccounts {
+		r.plaidAccounts[strings.ToLower(p.Name)] = p.ID
+		if p.DisplayName != "" {
+			r.plaidAccounts[strings.ToLower(p.DisplayName)] = p.ID
+		}
+		r.plaidIDs[p.ID] = true
+	}
+	return r, nil
+}
+
+// category returns the ID of the named category, or 0 when name is empty.
+func (r *importResolver) category(name string) (int64, error) {
+	if name == "" || strings.EqualFold(name, "uncategorized") {
+		return 0, nil
+	}
+	if id, ok := r.categories[strings.ToLower(name)]; ok {
+		return id, nil
+	}
+	if id, err := strconv.ParseInt(name, 10, 64); err == nil && r.categoryIDs[id] {
+		return id, nil
+	}
+	return 0, fmt.Errorf("unknown category: %s", name)
+}
+
+// account sets the asset or plaid account ID of the named account on the transaction.
+// Nothing is set when name is empty.
+func (r *importResolver) account(t *lm.InsertTransaction, name string) error {
+	if name == "" {
+		return nil
+	}
+	key := strings.ToLower(name)
+	id, err := strconv.ParseInt(name, 10, 64)
+	switch {
+	case r.assets[key] != 0:
+		id = r.assets[key]
+		t.AssetID = &id
+	case r.plaidAccounts[key] != 0:
+		id = r.plaidAccounts[key]
+		t.PlaidAccountID = &id
+	case err == nil && r.assetIDs[id]:
+		t.AssetID = &id
+	case err == nil && r.plaidIDs[id]:
+		t.PlaidAccountID = &id
+	default:
+		return fmt.Errorf("unknown account: %s", name)
+	}
+	return nil
+}
+
+// buildImportTransactions validates the parsed rows and converts them into transactions to insert.
+// It returns a result for every row and the transactions for the valid rows, keyed by result index.
+func buildImportTransactions(
+	rows []importer.Row,
+	resolver *importResolver,
+	opts importOptions,
+) ([]importResult, map[int]lm.InsertTransaction) {
+	results := make([]importResult, len(rows))
+	transactions := make(map[int]lm.InsertTransaction, len(rows))
+
+	for i, row := range rows {
+		payee := resolver.payee(row)
+		results[i] = importResult{Row: row.Line, Date: row.Date, Payee: payee, Amount: row.Amount}
+
+		t, err := buildImportTransaction(row, payee, resolver, opts)
+		if err != nil {
+			results[i].Result = importResultInvalid
+			results[i].Detail = err.Error()
+			continue
+		}
+
+		results[i].Result = importResultPending
+		transactions[i] = t
+	}
+
+	return results, transactions
+}
+
+// buildImportTransaction converts a single row, applying the import defaults.
+func buildImportTransaction(
+	row importer.Row,
+	payee string,
+	resolver *importResolver,
+	opts importOptions,
+) (lm.InsertTransaction, error) {
+	if row.Err != nil {
+		return lm.InsertTransaction{}, row.Err
+	}
+	if err := validateTransactionInput(row.Amount, row.Date, opts.status); err != nil {
+		return lm.InsertTransaction{}, err
+	}
+	if len(row.ExternalID) > maxExternalIDLength {
+		return lm.InsertTransaction{}, fmt.Errorf("external ID longer than %d characters", maxExternalIDLength)
+	}
+
+	currency := row.Currency
+	if currency == "" {
+		currency = opts.currency
+	}
+
+	t := lm.InsertTransaction{
+		Date:       row.Date,
+		Amount:     row.Amount,
+		Payee:      payee,
+		Currency:   strings.ToLower(currency),
+		Notes:      row.Notes,
+		Status:     opts.status,
+		ExternalID: row.ExternalID,
+	}
+
+	categoryID, err := resolver.category(row.Category)
+	if err != nil {
+		return lm.InsertTransaction{}, err
+	}
+	if categoryID != 0 {
+		t.CategoryID = &categoryID
+	}
+
+	accountName := row.Account
+	if accountName == "" {
+		accountName = opts.account
+	}
+	if err = resolver.account(&t, accountName); err != nil {
+		return lm.InsertTransaction{}, err
+	}
+
+	return t, nil
+}
+
+// payee cleans up the payee of a row, falling back to the notes when it is empty, and
+// returns the spelling of the matching existing payee if there is one.
+func (r *importResolver) payee(row importer.Row) string {
+	payee := cleanImportText(row.Payee)
+	if payee == "" {
+		payee = cleanImportText(row.Notes)
+	}
+	if existing, ok := r.payees[strings.ToLower(payee)]; ok {
+		return existing
+	}
+	return payee
+}
+
+// cleanImportText unescapes HTML entities and collapses whitespace.
+func cleanImportText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// insertImportBatches inserts the transactions in batches and records the outcome on each result.
+func insertImportBatches(
+	cmd *cobra.Command,
+	request lm.InsertTransactionsRequest,
+	transactions map[int]lm.InsertTransaction,
+	results []importResult,
+	batchSize int,
+) {
+	// Keep the file order when batching
+	var indexes []int
+	for i := range results {
+		if _, ok := transactions[i]; ok {
+			indexes = append(indexes, i)
+		}
+	}
+
+	for start := 0; start < len(indexes); start += batchSize {
+		batch := indexes[start:min(start+batchSize, len(indexes))]
+
+		request.Transactions = make([]lm.InsertTransaction, 0, len(batch))
+		for _, i := range batch {
+			request.Transactions = append(request.Transactions, transactions[i])
+		}
+
+		log.Debug("inserting transactions", "batch", start/batchSize+1, "count", len(batch))
+
+		resp, err := lmc.InsertTransactions(cmd.Context(), request)
+		for n, i := range batch {
+			switch {
+			case err != nil:
+				results[i].Result = importResultFailed
+				results[i].Detail = err.Error()
+			case len(resp.IDs) == len(batch):
+				results[i].Result = importResultInserted
+				results[i].ID = resp.IDs[n]
+			default:
+				// The API does not say which rows were skipped as duplicates
+				results[i].Result = importResultSubmitted
+				results[i].Detail = fmt.Sprintf("%d of %d in batch inserted; duplicates skipped",
+					len(resp.IDs), len(batch))
+			}
+		}
+	}
+}
+
+// countFailedImports counts the rows that were invalid or failed to insert.
+func countFailedImports(results []importResult) int {
+	var failed int
+	for _, r := range results {
+		if r.Result == importResultInvalid || r.Result == importResultFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+func outputImportResultsTable(cmd *cobra.Command, results []importResult) error {
+	t := createStyledTable("ROW", "DATE", "PAYEE", "AMOUNT", "RESULT", "DETAIL")
+
+	for _, r := range results {
+		detail := r.Detail
+		if r.ID != 0 {
+			detail = fmt.Sprintf("id %d", r.ID)
+		}
+		t.Row(strconv.Itoa(r.Row), r.Date, r.Payee, r.Amount, r.Result, detail)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), t)
+	return nil
+}
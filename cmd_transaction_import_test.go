@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+
+	"github.com/Rshep3087/lunchtui/importer"
+)
+
+func TestBuildImportTransactions(t *testing.T) {
+	resolver := &importResolver{
+		payees:        map[string]string{"shop": "The Shop", "coffee & co": "Coffee & Co."},
+		categories:    map[string]int64{"dining": 10},
+		categoryIDs:   map[int64]bool{10: true},
+		assets:        map[string]int64{"cash": 20},
+		assetIDs:      map[int64]bool{20: true},
+		plaidAccounts: map[string]int64{"checking": 30},
+		plaidIDs:      map[int64]bool{30: true},
+	}
+	opts := importOptions{account: "Checking", currency: "usd", status: unclearedStatus}
+
+	rows := []importer.Row{
+		{Line: 2, Date: "2024-01-15", Amount: "4.50", Payee: "Coffee  &amp; Co", Category: "Dining"},
+		{Line: 3, Date: "2024-01-16", Amount: "12.00", Notes: "ATM fee", Account: "20", Currency: "CAD"},
+		{Line: 4, Date: "2024-01-17", Amount: "1.00", Payee: "Shop", Category: "Unknown"},
+		{Line: 5, Err: errors.New("invalid date: nope")},
+	}
+
+	results, transactions := buildImportTransactions(rows, resolver, opts)
+
+	be.Equal(t, 4, len(results))
+	be.Equal(t, 2, len(transactions))
+
+	be.Equal(t, importResultPending, results[0].Result)
+	be.Equal(t, "Coffee & Co.", transactions[0].Payee)
+	be.Equal(t, "Coffee & Co.", results[0].Payee)
+	be.Equal(t, int64(10), *transactions[0].CategoryID)
+	be.Equal(t, int64(30), *transactions[0].PlaidAccountID)
+	be.Zero(t, transactions[0].AssetID)
+
+	be.Equal(t, "ATM fee", transactions[1].Payee)
+	be.Equal(t, int64(20), *transactions[1].AssetID)
+	be.Equal(t, "cad", transactions[1].Currency)
+	be.Zero(t, transactions[1].CategoryID)
+
+	be.Equal(t, "The Shop", results[2].Payee)
+	be.Equal(t, importResultInvalid, results[2].Result)
+	be.Equal(t, "unknown category: Unknown", results[2].Detail)
+	be.Equal(t, importResultInvalid, results[3].Result)
+	be.Equal(t, 5, results[3].Row)
+}
+
+func TestCountFailedImports(t *testing.T) {
+	results := []importResult{
+		{Result: importResultInserted},
+		{Result: importResultInvalid},
+		{Result: importResultSubmitted},
+		{Result: importResultFailed},
+	}
+	be.Equal(t, 2, countFailedImports(results))
+}
+
+func TestImportResolverAccount(t *testing.T) {
+	resolver := &importResolver{
+		assets:        map[string]int64{"cash": 20},
+		assetIDs:      map[int64]bool{20: true},
+		plaidAccounts: map[string]int64{"checking": 30},
+		plaidIDs:      map[int64]bool{30: true},
+	}
+
+	var tr lm.InsertTransaction
+	be.NilErr(t, resolver.account(&tr, "30"))
+	be.Equal(t, int64(30), *tr.PlaidAccountID)
+
+	be.Nonzero(t, resolver.account(&lm.InsertTransaction{}, "Savings"))
+	be.NilErr(t, resolver.account(&lm.InsertTransaction{}, ""))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestFilterTransactions(t *testing.T) {
+	ts := []*lm.Transaction{
+		{ID: 1, Payee: "Coffee Shop", CategoryID: 10, PlaidAccountID: 100, Status: clearedStatus},
+		{ID: 2, Payee: "Grocery Store", CategoryID: 0, AssetID: 200, Status: unclearedStatus},
+		{ID: 3, Payee: "coffee roasters", CategoryID: 0, PlaidAccountID: 100, Status: pendingStatus},
+	}
+
+	tests := []struct {
+		name     string
+		filter   transactionListFilter
+		expected []int64
+	}{
+		{
+			name:     "no filters",
+			filter:   transactionListFilter{},
+			expected: []int64{1, 2, 3},
+		},
+		{
+			name:     "uncategorized",
+			filter:   transactionListFilter{uncategorized: true},
+			expected: []int64{2, 3},
+		},
+		{
+			name:     "plaid account",
+			filter:   transactionListFilter{accountID: 100},
+			expected: []int64{1, 3},
+		},
+		{
+			name:     "asset account",
+			filter:   transactionListFilter{accountID: 200},
+			expected: []int64{2},
+		},
+		{
+			name:     "status",
+			filter:   transactionListFilter{status: unclearedStatus},
+			expected: []int64{2},
+		},
+		{
+			name:     "payee is case insensitive",
+			filter:   transactionListFilter{payee: "COFFEE"},
+			expected: []int64{1, 3},
+		},
+		{
+			name:     "combined filters",
+			filter:   transactionListFilter{payee: "coffee", uncategorized: true},
+			expected: []int64{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := filterTransactions(ts, tt.filter)
+			ids := make([]int64, len(result))
+			for i, tr := range result {
+				ids[i] = tr.ID
+			}
+			be.AllEqual(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestTransactionAccountName(t *testing.T) {
+	tests := []struct {
+		name     string
+		trans    *lm.Transaction
+		expected string
+	}{
+		{
+			name: "plaid account prefers display name",
+			trans: &lm.Transaction{
+				PlaidAccountID:          1,
+				PlaidAccountName:        "Checking",
+				PlaidAccountDisplayName: "My Checking",
+			},
+			expected: "My Checking",
+		},
+		{
+			name:     "asset account",
+			trans:    &lm.Transaction{AssetID: 2, AssetName: "Brokerage &amp; Co"},
+			expected: "Brokerage & Co",
+		},
+		{
+			name:     "cash",
+			trans:    &lm.Transaction{},
+			expected: "Cash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.Equal(t, tt.expected, transactionAccountName(tt.trans))
+		})
+	}
+}
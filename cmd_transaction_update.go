@@ -0,0 +1,353 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// transactionUpdateCmd represents the transaction update command.
+var transactionUpdateCmd = &cobra.Command{
+	Use:   "update <id>",
+	Short: "Update an existing transaction",
+	Long: `Update the category, status, notes, payee, tags or other fields of an existing transaction.
+Only the fields whose flags are set are changed. Use --dry-run to preview the changes.`,
+	Args: cobra.ExactArgs(1),
+	RunE: transactionUpdateRun,
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionUpdateCmd)
+
+	// Transaction update flags
+	transactionUpdateCmd.Flags().Int64("category", 0, "New category ID")
+	transactionUpdateCmd.Flags().String("status", "", "New status (cleared, uncleared)")
+	transactionUpdateCmd.Flags().String("notes", "", "New notes")
+	transactionUpdateCmd.Flags().String("payee", "", "New payee")
+	transactionUpdateCmd.Flags().String("date", "", "New date (YYYY-MM-DD)")
+	transactionUpdateCmd.Flags().String("currency", "", "New currency code")
+	transactionUpdateCmd.Flags().Int("asset", 0, "New asset ID")
+	transactionUpdateCmd.Flags().Int("recurring", 0, "New recurring expense ID")
+	transactionUpdateCmd.Flags().String("external-id", "", "New external ID")
+	transactionUpdateCmd.Flags().StringSlice("tags", []string{}, "Replace all tags with these tag IDs")
+	transactionUpdateCmd.Flags().StringSlice("add-tags", []string{}, "Tag IDs to add to the existing tags")
+	transactionUpdateCmd.Flags().StringSlice("remove-tags", []string{}, "Tag IDs to remove from the existing tags")
+	transactionUpdateCmd.Flags().Bool("dry-run", false, "Show the changes without applying them")
+	transactionUpdateCmd.Flags().StringP("output", "o", tableOutputFormat, "Output format: table or json")
+
+	transactionUpdateCmd.MarkFlagsMutuallyExclusive("tags", "add-tags")
+	transactionUpdateCmd.MarkFlagsMutuallyExclusive("tags", "remove-tags")
+}
+
+// fieldChange describes a single field change of a transaction update.
+type fieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+func transactionUpdateRun(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	// Get and validate output format
+	outputFormat, err := validateOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid transaction ID: %s", args[0])
+	}
+
+	update, err := transactionUpdateFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Fetch the current transaction to compute tag changes and the diff
+	debitsAsNegative := viper.GetBool("debits_as_negative")
+	current, err := lmc.GetTransaction(ctx, id, &lm.TransactionFilters{DebitAsNegative: &debitsAsNegative})
+	if err != nil {
+		return fmt.Errorf("failed to fetch transaction %d: %w", id, err)
+	}
+
+	if err = applyTagFlags(cmd, current, update); err != nil {
+		return err
+	}
+
+	if isEmptyTransactionUpdate(update) {
+		return errors.New("no fields to update (see --help for available flags)")
+	}
+
+	names, err := fetchUpdateNames(cmd, update)
+	if err != nil {
+		return err
+	}
+
+	changes := diffTransactionUpdate(current, update, names)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if !dryRun {
+		log.Debug("updating transaction", "id", id, "update", update)
+
+		resp, updateErr := updateTransaction(ctx, lmc, id, update)
+		if updateErr != nil {
+			return fmt.Errorf("failed to update transaction: %w", updateErr)
+		}
+		if !resp.Updated {
+			return fmt.Errorf("transaction %d was not updated", id)
+		}
+	}
+
+	switch outputFormat {
+	case jsonOutputFormat:
+		return outputJSON(cmd, struct {
+			ID      int64         `json:"id"`
+			DryRun  bool          `json:"dry_run"`
+			Changes []fieldChange `json:"changes"`
+		}{ID: id, DryRun: dryRun, Changes: changes})
+	case tableOutputFormat:
+		outputFieldChangesTable(cmd, changes)
+		if dryRun {
+			fmt.Fprintln(cmd.OutOrStdout(), "Dry run: no changes were applied")
+		} else {
+			log.Infof("Transaction %d updated successfully", id)
+		}
+		return nil
+	default:
+		return errors.New("unsupported output format")
+	}
+}
+
+// transactionUpdateFromFlags builds the update from the flags that were explicitly set.
+func transactionUpdateFromFlags(cmd *cobra.Command) (*transactionUpdate, error) {
+	flags := cmd.Flags()
+	update := &transactionUpdate{}
+
+	if flags.Changed("category") {
+		categoryID, _ := flags.GetInt64("category")
+		if categoryID <= 0 {
+			return nil, fmt.Errorf("invalid category ID: %d", categoryID)
+		}
+		update.CategoryID = ptr(int(categoryID))
+	}
+
+	if flags.Changed("status") {
+		status, _ := flags.GetString("status")
+		if status != clearedStatus && status != unclearedStatus {
+			return nil, fmt.Errorf("invalid status: %s (must be 'cleared' or 'uncleared')", status)
+		}
+		update.Status = &status
+	}
+
+	if flags.Changed("date") {
+		date, _ := flags.GetString("date")
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", date)
+		}
+		update.Date = &date
+	}
+
+	update.Notes = changedStringFlag(cmd, "notes")
+	update.Payee = changedStringFlag(cmd, "payee")
+	update.Currency = changedStringFlag(cmd, "currency")
+	update.ExternalID = changedStringFlag(cmd, "external-id")
+
+	if flags.Changed("asset") {
+		assetID, _ := flags.GetInt("asset")
+		update.AssetID = &assetID
+	}
+
+	if flags.Changed("recurring") {
+		recurringID, _ := flags.GetInt("recurring")
+		update.RecurringID = &recurringID
+	}
+
+	return update, nil
+}
+
+// changedStringFlag returns the value of a string flag, or nil if it was not set.
+func changedStringFlag(cmd *cobra.Command, name string) *string {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	value, _ := cmd.Flags().GetString(name)
+	return &value
+}
+
+// applyTagFlags sets the tags on the update from the --tags, --add-tags and --remove-tags flags.
+func applyTagFlags(cmd *cobra.Command, current *lm.Transaction, update *transactionUpdate) error {
+	flags := cmd.Flags()
+	if !flags.Changed("tags") && !flags.Changed("add-tags") && !flags.Changed("remove-tags") {
+		return nil
+	}
+
+	if flags.Changed("tags") {
+		tagStrings, _ := flags.GetStringSlice("tags")
+		tagIDs, err := parseTagIDs(tagStrings)
+		if err != nil {
+			return err
+		}
+		if tagIDs == nil {
+			tagIDs = []int{}
+		}
+		update.Tags = &tagIDs
+		return nil
+	}
+
+	addStrings, _ := flags.GetStringSlice("add-tags")
+	add, err := parseTagIDs(addStrings)
+	if err != nil {
+		return err
+	}
+	removeStrings, _ := flags.GetStringSlice("remove-tags")
+	remove, err := parseTagIDs(removeStrings)
+	if err != nil {
+		return err
+	}
+
+	tagIDs := mergeTagIDs(current.Tags, add, remove)
+	update.Tags = &tagIDs
+	return nil
+}
+
+// mergeTagIDs returns the IDs of the current tags with add appended and remove taken out.
+func mergeTagIDs(current []lm.Tag, add, remove []int) []int {
+	tagIDs := make([]int, 0, len(current)+len(add))
+	for _, tag := range current {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	for _, id := range add {
+		if !slices.Contains(tagIDs, id) {
+			tagIDs = append(tagIDs, id)
+		}
+	}
+	return slices.DeleteFunc(tagIDs, func(id int) bool {
+		return slices.Contains(remove, id)
+	})
+}
+
+// isEmptyTransactionUpdate reports whether the update would not change anything.
+func isEmptyTransactionUpdate(u *transactionUpdate) bool {
+	return u.UpdateTransaction == lm.UpdateTransaction{} && u.Tags == nil
+}
+
+// updateNames resolves category and tag IDs to names for display.
+type updateNames struct {
+	categories map[int64]string
+	tags       map[int]string
+}
+
+// fetchUpdateNames fetches the names needed to display the changes of an update.
+func fetchUpdateNames(cmd *cobra.Command, update *transactionUpdate) (updateNames, error) {
+	ctx := cmd.Context()
+	names := updateNames{
+		categories: make(map[int64]string),
+		tags:       make(map[int]string),
+	}
+
+	if update.CategoryID != nil {
+		category, err := lmc.GetCategory(ctx, int64(*update.CategoryID))
+		if err != nil {
+			return names, fmt.Errorf("failed to fetch category %d: %w", *update.CategoryID, err)
+		}
+		names.categories[category.ID] = category.Name
+	}
+
+	if update.Tags != nil {
+		tags, err := lmc.GetTags(ctx)
+		if err != nil {
+			return names, fmt.Errorf("failed to fetch tags: %w", err)
+		}
+		for _, tag := range tags {
+			names.tags[tag.ID] = tag.Name
+		}
+	}
+
+	return names, nil
+}
+
+// diffTransactionUpdate lists the fields the update changes on the transaction.
+func diffTransactionUpdate(t *lm.Transaction, u *transactionUpdate, names updateNames) []fieldChange {
+	var changes []fieldChange
+	addChange := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, fieldChange{Field: field, Before: before, After: after})
+		}
+	}
+
+	if u.CategoryID != nil {
+		after := strconv.Itoa(*u.CategoryID)
+		if name, ok := names.categories[int64(*u.CategoryID)]; ok {
+			after = name
+		}
+		addChange("category", transactionCategoryName(t), after)
+	}
+	if u.Status != nil {
+		addChange("status", t.Status, *u.Status)
+	}
+	if u.Notes != nil {
+		addChange("notes", t.Notes, *u.Notes)
+	}
+	if u.Payee != nil {
+		addChange("payee", t.Payee, *u.Payee)
+	}
+	if u.Date != nil {
+		addChange("date", t.Date, *u.Date)
+	}
+	if u.Currency != nil {
+		addChange("currency", t.Currency, *u.Currency)
+	}
+	if u.AssetID != nil {
+		addChange("asset", strconv.FormatInt(t.AssetID, 10), strconv.Itoa(*u.AssetID))
+	}
+	if u.RecurringID != nil {
+		addChange("recurring", strconv.FormatInt(t.RecurringID, 10), strconv.Itoa(*u.RecurringID))
+	}
+	if u.ExternalID != nil {
+		addChange("external id", t.ExternalID, *u.ExternalID)
+	}
+	if u.Tags != nil {
+		after := make([]string, len(*u.Tags))
+		for i, id := range *u.Tags {
+			after[i] = strconv.Itoa(id)
+			if name, ok := names.tags[id]; ok {
+				after[i] = name
+			}
+		}
+		addChange("tags", transactionTagNames(t), strings.Join(after, ","))
+	}
+
+	return changes
+}
+
+func outputFieldChangesTable(cmd *cobra.Command, changes []fieldChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No changes")
+		return
+	}
+
+	t := createStyledTable("FIELD", "BEFORE", "AFTER")
+	for _, change := range changes {
+		before := change.Before
+		if before == "" {
+			before = "-"
+		}
+		after := change.After
+		if after == "" {
+			after = "-"
+		}
+		t.Row(change.Field, before, after)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), t)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestMergeTagIDs(t *testing.T) {
+	current := []lm.Tag{{ID: 1, Name: "food"}, {ID: 2, Name: "travel"}}
+
+	tests := []struct {
+		name     string
+		add      []int
+		remove   []int
+		expected []int
+	}{
+		{
+			name:     "no changes",
+			expected: []int{1, 2},
+		},
+		{
+			name:     "add new tag",
+			add:      []int{3},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "add existing tag is ignored",
+			add:      []int{2},
+			expected: []int{1, 2},
+		},
+		{
+			name:     "remove tag",
+			remove:   []int{1},
+			expected: []int{2},
+		},
+		{
+			name:     "add and remove",
+			add:      []int{3},
+			remove:   []int{1, 2},
+			expected: []int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.AllEqual(t, tt.expected, mergeTagIDs(current, tt.add, tt.remove))
+		})
+	}
+}
+
+func TestDiffTransactionUpdate(t *testing.T) {
+	trans := &lm.Transaction{
+		ID:           1,
+		Payee:        "Coffee Shop",
+		Status:       unclearedStatus,
+		CategoryID:   10,
+		CategoryName: "Dining",
+		Tags:         []lm.Tag{{ID: 1, Name: "food"}},
+	}
+	names := updateNames{
+		categories: map[int64]string{20: "Groceries"},
+		tags:       map[int]string{1: "food", 2: "weekly"},
+	}
+
+	update := &transactionUpdate{
+		UpdateTransaction: lm.UpdateTransaction{
+			CategoryID: ptr(20),
+			Status:     ptr(clearedStatus),
+			Payee:      ptr("Coffee Shop"), // unchanged values are not reported
+		},
+		Tags: &[]int{1, 2},
+	}
+
+	changes := diffTransactionUpdate(trans, update, names)
+
+	be.AllEqual(t, []fieldChange{
+		{Field: "category", Before: "Dining", After: "Groceries"},
+		{Field: "status", Before: unclearedStatus, After: clearedStatus},
+		{Field: "tags", Before: "food", After: "food,weekly"},
+	}, changes)
+}
+
+func TestIsEmptyTransactionUpdate(t *testing.T) {
+	be.True(t, isEmptyTransactionUpdate(&transactionUpdate{}))
+	be.False(t, isEmptyTransactionUpdate(&transactionUpdate{Tags: &[]int{}}))
+	be.False(t, isEmptyTransactionUpdate(&transactionUpdate{
+		UpdateTransaction: lm.UpdateTransaction{Notes: ptr("")},
+	}))
+}
@@ -42,7 +42,7 @@ func userGetRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Fetch user information
-	user, err := lmc.GetUser(ctx)
+	user, err := fetchCached(ctx, lmCache, lmCache.userQuery())
 	if err != nil {
 		return fmt.Errorf("failed to fetch user information: %w", err)
 	}
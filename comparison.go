@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/Rshep3087/lunchtui/overview"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lm "github.com/icco/lunchmoney"
+)
+
+// getComparisonTransactionsMsg holds the transactions of the period the overview compares
+// the current period with.
+type getComparisonTransactionsMsg struct {
+	cacheInfo
+	ts     []*lm.Transaction
+	period Period
+}
+
+// comparisonPeriod returns the period the overview compares the selected period with.
+func (m model) comparisonPeriod(c overview.Comparison) Period {
+	if c == overview.SamePeriodLastYear {
+		if m.periodType == customPeriodType {
+			return newCustomPeriod(
+				addMonths(m.customPeriod.start, -monthsPerYr),
+				addMonths(m.customPeriod.end, -monthsPerYr),
+			)
+		}
+		var p Period
+		p.setPeriod(addMonths(m.currentPeriod, -monthsPerYr), m.periodType)
+		return p
+	}
+
+	m.stepPeriod(-1)
+	return m.selectedPeriod()
+}
+
+func (m model) getComparisonTransactions() tea.Msg {
+	period := m.comparisonPeriod(m.overview.Comparison())
+
+	query := m.lmCache.transactionsQuery(period.startDate(), period.endDate(), m.debitsAsNegative)
+	return loadCached(m.lmCache, query, func(ts []*lm.Transaction, info cacheInfo) tea.Msg {
+		return getComparisonTransactionsMsg{cacheInfo: info, ts: ts, period: period}
+	})()
+}
+
+func (m model) handleComparisonChanged(msg overview.ComparisonChangedMsg) (tea.Model, tea.Cmd) {
+	if msg.Comparison == overview.NoComparison {
+		return m, nil
+	}
+	return m, m.getComparisonTransactions
+}
+
+func (m model) handleGetComparisonTransactions(msg getComparisonTransactionsMsg) (tea.Model, tea.Cmd) {
+	// the user moved to another period or comparison since the transactions were requested
+	if m.overview.Comparison() == overview.NoComparison {
+		return m, nil
+	}
+	if expected := m.comparisonPeriod(m.overview.Comparison()); expected.String() != msg.period.String() {
+		return m, nil
+	}
+
+	ts, _ := collapseGroups(m.withoutHiddenTransactions(msg.ts))
+	m.overview.SetComparisonTransactions(ts)
+	return m, msg.refresh
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/overview"
+	"github.com/carlmjohnson/be"
+	"github.com/charmbracelet/bubbles/list"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestComparisonPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		periodType string
+		current    time.Time
+		custom     Period
+		comparison overview.Comparison
+		expected   string
+	}{
+		{
+			name:       "previous month",
+			periodType: monthlyPeriodType,
+			current:    time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			comparison: overview.PreviousPeriod,
+			expected:   "2024-02-01 - 2024-02-29",
+		},
+		{
+			name:       "same month last year",
+			periodType: monthlyPeriodType,
+			current:    time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			comparison: overview.SamePeriodLastYear,
+			expected:   "2023-03-01 - 2023-03-31",
+		},
+		{
+			name:       "previous quarter",
+			periodType: quarterlyPeriodType,
+			current:    time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+			comparison: overview.PreviousPeriod,
+			expected:   "2024-01-01 - 2024-03-31",
+		},
+		{
+			name:       "year to date last year",
+			periodType: yearToDatePeriodType,
+			current:    time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+			comparison: overview.SamePeriodLastYear,
+			expected:   "2023-01-01 - 2023-05-15",
+		},
+		{
+			name:       "previous custom range",
+			periodType: customPeriodType,
+			custom: newCustomPeriod(
+				time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC),
+			),
+			comparison: overview.PreviousPeriod,
+			expected:   "2024-03-01 - 2024-03-10",
+		},
+		{
+			name:       "custom range last year",
+			periodType: customPeriodType,
+			custom: newCustomPeriod(
+				time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			),
+			comparison: overview.SamePeriodLastYear,
+			expected:   "2023-02-01 - 2023-02-28",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{periodType: tt.periodType, currentPeriod: tt.current, customPeriod: tt.custom}
+			p := m.comparisonPeriod(tt.comparison)
+			be.Equal(t, tt.expected, p.String())
+			// the selected period is left alone
+			be.Equal(t, tt.current, m.currentPeriod)
+		})
+	}
+}
+
+func TestHandleGetTransactionsClearsComparison(t *testing.T) {
+	categories := map[int64]*lm.Category{1: {ID: 1, Name: "Groceries"}}
+	m := model{
+		loadingState:  newLoadingState("transactions"),
+		idToCategory:  categories,
+		plaidAccounts: map[int64]*lm.PlaidAccount{},
+		assets:        map[int64]*lm.Asset{},
+		transactions:  list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		overview:      overview.New(overview.Config{}),
+	}
+	m.overview.SetSize(200, 40)
+	m.overview.SetCurrency("usd")
+	m.overview.SetCategories(categories)
+	m.overview.SetTransactions([]*lm.Transaction{{ID: 1, CategoryID: 1, Amount: "50.00", Currency: "usd"}})
+	m.overview.SetComparison(overview.PreviousPeriod)
+	m.overview.SetComparisonTransactions([]*lm.Transaction{{ID: 2, CategoryID: 1, Amount: "100.00", Currency: "usd"}})
+	be.In(t, "▼ 50%", m.overview.View())
+
+	// the changes of the previous period are not shown against the transactions of the new one
+	result, cmd := m.handleGetTransactions(getsTransactionsMsg{
+		ts: []*lm.Transaction{{ID: 3, Date: "2024-02-01", CategoryID: 1, Amount: "80.00", Currency: "usd"}},
+	})
+	m = result.(model)
+	be.Equal(t, overview.PreviousPeriod, m.overview.Comparison())
+	be.False(t, strings.Contains(m.overview.View(), "▼"))
+	be.False(t, strings.Contains(m.overview.View(), "▲"))
+	be.Nonzero(t, cmd)
+}
@@ -5,12 +5,21 @@ import "time"
 // Timeout and limit constants.
 const (
 	aiRecommendationTimeout      = 30 * time.Second
-	categoryServiceTimeout       = 10 * time.Second
 	transactionLoadTimeout       = 10 * time.Second
 	budgetStatusMessageLifetime  = 3 * time.Second
 	transactionStatusMsgLifetime = 3 * time.Second
-	anthropicMaxTokens           = 150
+	aiMaxTokens                  = 150
+	aiBatchGroupSize             = 10
+	aiBatchConcurrency           = 4
+	aiBatchTimeout               = 5 * time.Minute
+	defaultCacheMaxAge           = 15 * time.Minute
+	aiExampleLimit               = 5
+	aiPayeeWeight                = 3
+	defaultAIHistoryDays         = 90
+	aiHistoryLoadTimeout         = 30 * time.Second
+	aiHistoryRetryDelay          = 30 * time.Second
 	maxConfidenceScore           = 100
+	aiThresholdStep              = 5
 )
 
 // UI layout constants.
@@ -23,6 +32,18 @@ const (
 	transactionFormHeight      = 6
 	categoryFormHeight         = 8
 	transactionDateLength      = 10
+	bulkProgressWidth          = 40
+	budgetProgressWidth        = 20
+	aiReviewHeightOffset       = 8
+	aiReviewMinRows            = 5
+	aiReviewErrorWidth         = 40
+	accountDetailHeightOffset  = 20
+	accountDetailMinRows       = 5
+	minSplitParts              = 2
+	maxSplitParts              = 5
+	minGroupTransactions       = 2
+	budgetSummaryHeight        = 2
+	rulesPreviewMaxRows        = 15
 )
 
 // Table column width constants.
@@ -30,10 +51,28 @@ const (
 	transactionStatusWidth = 20
 )
 
+// AI provider constants.
+const (
+	anthropicAIProvider   = "anthropic"
+	openAIProvider        = "openai"
+	ollamaAIProvider      = "ollama"
+	defaultAnthropicModel = "claude-3-haiku-20240307"
+	defaultOpenAIModel    = "gpt-4o-mini"
+	defaultOpenAIBaseURL  = "https://api.openai.com/v1"
+	defaultOllamaBaseURL  = "http://localhost:11434/v1"
+)
+
 // Period types.
 const (
-	monthlyPeriodType = "month"
-	annualPeriodType  = "year"
+	weeklyPeriodType     = "week"
+	monthlyPeriodType    = "month"
+	quarterlyPeriodType  = "quarter"
+	annualPeriodType     = "year"
+	yearToDatePeriodType = "ytd"
+	last30DaysPeriodType = "last-30-days"
+	last90DaysPeriodType = "last-90-days"
+	// customPeriodType is a range with its own start and end date
+	customPeriodType = "custom"
 )
 
 // Transaction status constants.
@@ -43,12 +82,6 @@ const (
 	pendingStatus   = "pending"
 )
 
-// Credit account type constants.
-const (
-	creditType        = "credit"
-	creditCardSubtype = "credit card"
-)
-
 // Session states.
 type sessionState int
 
@@ -63,6 +96,19 @@ const (
 	budgets
 	configView
 	errorState
+	bulkActions
+	bulkUpdating
+	aiReviewState
+	splitTransactionState
+	editBudgetState
+	periodPickerState
+	trendsState
+	accountPickerState
+	accountDetailState
+	assetFormState
+	editTagsState
+	rulesPreviewState
+	ungroupTransactionState
 )
 
 func (ss sessionState) String() string {
@@ -87,6 +133,32 @@ func (ss sessionState) String() string {
 		return "configuration"
 	case errorState:
 		return "error"
+	case bulkActions:
+		return "bulk actions"
+	case bulkUpdating:
+		return "bulk update"
+	case aiReviewState:
+		return "ai review"
+	case splitTransactionState:
+		return "split transaction"
+	case editBudgetState:
+		return "edit budget"
+	case periodPickerState:
+		return "select period"
+	case trendsState:
+		return "trends"
+	case accountPickerState:
+		return "select account"
+	case accountDetailState:
+		return "account details"
+	case assetFormState:
+		return "edit asset"
+	case editTagsState:
+		return "edit tags"
+	case rulesPreviewState:
+		return "apply rules"
+	case ungroupTransactionState:
+		return "ungroup transaction"
 	}
 
 	return "unknown"
@@ -62,6 +62,66 @@ func TestSessionStateString(t *testing.T) {
 			state:    errorState,
 			expected: "error",
 		},
+		{
+			name:     "bulk actions state",
+			state:    bulkActions,
+			expected: "bulk actions",
+		},
+		{
+			name:     "bulk updating state",
+			state:    bulkUpdating,
+			expected: "bulk update",
+		},
+		{
+			name:     "ai review state",
+			state:    aiReviewState,
+			expected: "ai review",
+		},
+		{
+			name:     "split transaction state",
+			state:    splitTransactionState,
+			expected: "split transaction",
+		},
+		{
+			name:     "period picker state",
+			state:    periodPickerState,
+			expected: "select period",
+		},
+		{
+			name:     "trends state",
+			state:    trendsState,
+			expected: "trends",
+		},
+		{
+			name:     "account picker state",
+			state:    accountPickerState,
+			expected: "select account",
+		},
+		{
+			name:     "account detail state",
+			state:    accountDetailState,
+			expected: "account details",
+		},
+		{
+			name:     "asset form state",
+			state:    assetFormState,
+			expected: "edit asset",
+		},
+		{
+			name:     "edit tags state",
+			state:    editTagsState,
+			expected: "edit tags",
+		},
+		{
+			name:     "rules preview state",
+			state:    rulesPreviewState,
+			expected: "apply rules",
+		},
+		{
+			name:     "ungroup transaction state",
+			state:    ungroupTransactionState,
+			expected: "ungroup transaction",
+		},
 		{
 			name:     "unknown state",
 			state:    sessionState(999),
@@ -81,6 +141,9 @@ func TestPeriodConstants(t *testing.T) {
 	// Test that period constants have expected values
 	be.Equal(t, "month", monthlyPeriodType)
 	be.Equal(t, "year", annualPeriodType)
+	be.Equal(t, "week", weeklyPeriodType)
+	be.Equal(t, "quarter", quarterlyPeriodType)
+	be.Equal(t, "custom", customPeriodType)
 }
 
 func TestSessionStateConstants(t *testing.T) {
@@ -94,6 +157,18 @@ func TestSessionStateConstants(t *testing.T) {
 	be.True(t, recurringExpenses != budgets)
 	be.True(t, budgets != configView)
 	be.True(t, configView != errorState)
+	be.True(t, errorState != bulkActions)
+	be.True(t, bulkActions != bulkUpdating)
+	be.True(t, bulkUpdating != aiReviewState)
+	be.True(t, aiReviewState != splitTransactionState)
+	be.True(t, editBudgetState != periodPickerState)
+	be.True(t, periodPickerState != trendsState)
+	be.True(t, trendsState != accountPickerState)
+	be.True(t, accountPickerState != accountDetailState)
+	be.True(t, accountDetailState != assetFormState)
+	be.True(t, assetFormState != editTagsState)
+	be.True(t, editTagsState != rulesPreviewState)
+	be.True(t, rulesPreviewState != ungroupTransactionState)
 
 	// Test that overviewState is 0 (first iota value)
 	be.Equal(t, sessionState(0), overviewState)
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+)
+
+// groupTransactionsMsg is sent once marked transactions have been grouped.
+type groupTransactionsMsg struct {
+	payee string
+	count int
+	err   error
+}
+
+// ungroupTransactionMsg is sent once a group transaction has been ungrouped.
+type ungroupTransactionMsg struct {
+	payee string
+	count int
+	err   error
+}
+
+// newTransactionGroup builds the request that groups ts into one transaction, dated on the
+// earliest of them. Groups, and transactions that are already in a group, cannot be grouped.
+func newTransactionGroup(
+	ts []*lm.Transaction,
+	payee string,
+	categoryID int64,
+	notes string,
+) (*transactionGroup, error) {
+	if len(ts) < minGroupTransactions {
+		return nil, fmt.Errorf("at least %d transactions are needed for a group", minGroupTransactions)
+	}
+
+	payee = strings.TrimSpace(payee)
+	if payee == "" {
+		return nil, errors.New("payee cannot be empty")
+	}
+
+	group := &transactionGroup{
+		Date:         ts[0].Date,
+		Payee:        payee,
+		Notes:        strings.TrimSpace(notes),
+		Transactions: make([]int64, 0, len(ts)),
+	}
+	if categoryID != 0 {
+		group.CategoryID = ptr(categoryID)
+	}
+
+	for _, t := range ts {
+		switch {
+		case t.IsGroup:
+			return nil, fmt.Errorf("transaction %d is a group", t.ID)
+		case t.GroupID != 0:
+			return nil, fmt.Errorf("transaction %d is already in group %d", t.ID, t.GroupID)
+		case slices.Contains(group.Transactions, t.ID):
+			return nil, fmt.Errorf("transaction %d is listed more than once", t.ID)
+		}
+
+		// dates are YYYY-MM-DD, so they sort as strings
+		if t.Date < group.Date {
+			group.Date = t.Date
+		}
+		group.Transactions = append(group.Transactions, t.ID)
+	}
+
+	return group, nil
+}
+
+// collapseGroups nests grouped transactions under their group transaction. It returns the
+// transactions to list and the grouped transactions by group ID. Grouped transactions
+// whose group is not in ts are listed on their own.
+func collapseGroups(ts []*lm.Transaction) ([]*lm.Transaction, map[int64][]*lm.Transaction) {
+	groups := make(map[int64]bool)
+	for _, t := range ts {
+		if t.IsGroup {
+			groups[t.ID] = true
+		}
+	}
+
+	listed := make([]*lm.Transaction, 0, len(ts))
+	children := make(map[int64][]*lm.Transaction)
+	for _, t := range ts {
+		if t.GroupID != 0 && groups[t.GroupID] {
+			children[t.GroupID] = append(children[t.GroupID], t)
+			continue
+		}
+		listed = append(listed, t)
+	}
+
+	return listed, children
+}
+
+// groupMarkedTransactions groups the marked transactions into a new transaction.
+func (m model) groupMarkedTransactions(payee string, categoryID int64, notes string) tea.Cmd {
+	marked := markedTransactionItems(m.originalTransactions)
+	ts := make([]*lm.Transaction, len(marked))
+	for i, item := range marked {
+		ts[i] = item.t
+	}
+
+	return func() tea.Msg {
+		msg := groupTransactionsMsg{payee: payee, count: len(ts)}
+
+		group, err := newTransactionGroup(ts, payee, categoryID, notes)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), transactionLoadTimeout)
+		defer cancel()
+
+		id, err := createTransactionGroup(ctx, m.lmc, group)
+		if err != nil {
+			log.Debug("error grouping transactions", "error", err)
+			msg.err = err
+			return msg
+		}
+
+		log.Debug("transactions grouped", "group_id", id, "transactions", group.Transactions)
+		return msg
+	}
+}
+
+func (m model) handleGroupTransactionsMsg(msg groupTransactionsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, m.transactions.NewStatusMessage(
+			fmt.Sprintf("Error grouping transactions: %s", msg.err.Error()),
+		)
+	}
+	return m, tea.Batch(m.getTransactions,
+		m.transactions.NewStatusMessage(fmt.Sprintf("Grouped %d transactions into %s", msg.count, msg.payee)),
+	)
+}
+
+// showUngroupForm asks to confirm ungrouping the group transaction shown in the detail view.
+func showUngroupForm(m model) (tea.Model, tea.Cmd) {
+	if m.currentTransaction == nil || !m.currentTransaction.t.IsGroup {
+		return m, nil
+	}
+
+	item := m.currentTransaction
+	m.ungroupForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Ungroup %s?", item.t.Payee)).
+				Description(fmt.Sprintf("The group is deleted and its %d transactions restored", len(item.children))).
+				Key("confirm"),
+		),
+	).WithShowHelp(true)
+	m.previousSessionState = m.sessionState
+	m.sessionState = ungroupTransactionState
+	return m, m.ungroupForm.Init()
+}
+
+func (m model) handleUngroupFormState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.ungroupForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.ungroupForm = f
+	} else {
+		log.Debug("ungroupForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.ungroupForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	m.previousSessionState = m.sessionState
+	if !m.ungroupForm.GetBool("confirm") {
+		m.sessionState = detailedTransaction
+		return m, nil
+	}
+	return ungroupTransaction(m)
+}
+
+// ungroupTransaction ungroups the group transaction shown in the detail view.
+func ungroupTransaction(m model) (tea.Model, tea.Cmd) {
+	if m.currentTransaction == nil || !m.currentTransaction.t.IsGroup {
+		return m, nil
+	}
+
+	t := m.currentTransaction.t
+	log.Debug("ungrouping transaction", "transaction_id", t.ID)
+
+	m.sessionState = transactions
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), transactionLoadTimeout)
+		defer cancel()
+
+		ids, err := deleteTransactionGroup(ctx, m.lmc, t.ID)
+		if err != nil {
+			log.Debug("error ungrouping transaction", "error", err)
+		}
+		return ungroupTransactionMsg{payee: t.Payee, count: len(ids), err: err}
+	}
+}
+
+func (m model) handleUngroupTransactionMsg(msg ungroupTransactionMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, m.transactions.NewStatusMessage(
+			fmt.Sprintf("Error ungrouping transaction: %s", msg.err.Error()),
+		)
+	}
+	return m, tea.Batch(m.getTransactions,
+		m.transactions.NewStatusMessage(fmt.Sprintf("Ungrouped %s into %d transactions", msg.payee, msg.count)),
+	)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	tea "github.com/charmbracelet/bubbletea"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestNewTransactionGroup(t *testing.T) {
+	coffee := &lm.Transaction{ID: 1, Date: "2025-01-05"}
+	bagel := &lm.Transaction{ID: 2, Date: "2025-01-03"}
+
+	tests := []struct {
+		name    string
+		ts      []*lm.Transaction
+		payee   string
+		wantErr string
+	}{
+		{name: "groups transactions", ts: []*lm.Transaction{coffee, bagel}, payee: " Breakfast "},
+		{
+			name:    "needs two transactions",
+			ts:      []*lm.Transaction{coffee},
+			payee:   "Breakfast",
+			wantErr: "at least 2 transactions are needed for a group",
+		},
+		{
+			name:    "needs a payee",
+			ts:      []*lm.Transaction{coffee, bagel},
+			payee:   " ",
+			wantErr: "payee cannot be empty",
+		},
+		{
+			name:    "cannot group a group",
+			ts:      []*lm.Transaction{coffee, {ID: 3, IsGroup: true}},
+			payee:   "Breakfast",
+			wantErr: "transaction 3 is a group",
+		},
+		{
+			name:    "cannot group a transaction twice",
+			ts:      []*lm.Transaction{coffee, bagel, coffee},
+			payee:   "Breakfast",
+			wantErr: "transaction 1 is listed more than once",
+		},
+		{
+			name:    "cannot group a grouped transaction",
+			ts:      []*lm.Transaction{coffee, {ID: 4, GroupID: 3}},
+			payee:   "Breakfast",
+			wantErr: "transaction 4 is already in group 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, err := newTransactionGroup(tt.ts, tt.payee, 7, " morning ")
+			if tt.wantErr != "" {
+				be.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+
+			be.NilErr(t, err)
+			be.Equal(t, "Breakfast", group.Payee)
+			be.Equal(t, "morning", group.Notes)
+			be.Equal(t, "2025-01-03", group.Date)
+			be.Equal(t, int64(7), *group.CategoryID)
+			be.AllEqual(t, []int64{1, 2}, group.Transactions)
+		})
+	}
+}
+
+func TestCollapseGroups(t *testing.T) {
+	ts := []*lm.Transaction{
+		{ID: 1, IsGroup: true},
+		{ID: 2, GroupID: 1},
+		{ID: 3},
+		{ID: 4, GroupID: 1},
+		// the group of this transaction is in another period
+		{ID: 5, GroupID: 9},
+	}
+
+	listed, children := collapseGroups(ts)
+
+	ids := make([]int64, len(listed))
+	for i, t := range listed {
+		ids[i] = t.ID
+	}
+	be.AllEqual(t, []int64{1, 3, 5}, ids)
+	be.Equal(t, 2, len(children[1]))
+	be.Equal(t, int64(4), children[1][1].ID)
+}
+
+func TestGroupTransactionItemTitle(t *testing.T) {
+	group := transactionItem{
+		t:        &lm.Transaction{ID: 1, Payee: "Costco", IsGroup: true},
+		children: []*lm.Transaction{{ID: 2}, {ID: 3}},
+	}
+	be.Equal(t, "▸ Costco (1) [group of 2]", group.Title())
+
+	group.children = nil
+	group.marked = true
+	be.Equal(t, "✓ ▸ Costco (1) [group]", group.Title())
+}
+
+// recordedRequest is a request received by the fake API.
+type recordedRequest struct {
+	method string
+	path   string
+	query  string
+	auth   string
+	body   []byte
+}
+
+// newTestGroupClient returns a client for a fake API that answers every request with response.
+func newTestGroupClient(t *testing.T, status int, response string) (*lm.Client, *[]recordedRequest) {
+	t.Helper()
+
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			query:  r.URL.RawQuery,
+			auth:   r.Header.Get("Authorization"),
+			body:   body,
+		})
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	lmc, err := lm.NewClient("token")
+	be.NilErr(t, err)
+	lmc.Base, err = url.Parse(server.URL)
+	be.NilErr(t, err)
+	return lmc, &requests
+}
+
+func TestCreateTransactionGroup(t *testing.T) {
+	lmc, requests := newTestGroupClient(t, http.StatusOK, "84")
+
+	id, err := createTransactionGroup(t.Context(), lmc, &transactionGroup{
+		Date:         "2025-01-03",
+		Payee:        "Breakfast",
+		Transactions: []int64{1, 2},
+	})
+	be.NilErr(t, err)
+	be.Equal(t, int64(84), id)
+
+	req := (*requests)[0]
+	be.Equal(t, http.MethodPost, req.method)
+	be.Equal(t, "/v1/transactions/group", req.path)
+	var body map[string]any
+	be.NilErr(t, json.Unmarshal(req.body, &body))
+	be.Equal(t, "Breakfast", body["payee"])
+	_, hasCategory := body["category_id"]
+	be.False(t, hasCategory)
+}
+
+func TestDeleteTransactionGroup(t *testing.T) {
+	t.Run("ungroups", func(t *testing.T) {
+		lmc, requests := newTestGroupClient(t, http.StatusOK, `{"transactions": [1, 2]}`)
+
+		ids, err := deleteTransactionGroup(t.Context(), lmc, 84)
+		be.NilErr(t, err)
+		be.AllEqual(t, []int64{1, 2}, ids)
+
+		req := (*requests)[0]
+		be.Equal(t, http.MethodDelete, req.method)
+		be.Equal(t, "/v1/transactions/group/84", req.path)
+		be.Equal(t, "Bearer token", req.auth)
+	})
+
+	t.Run("reports API errors", func(t *testing.T) {
+		lmc, _ := newTestGroupClient(t, http.StatusNotFound, `{"error": "Transaction group not found"}`)
+
+		_, err := deleteTransactionGroup(t.Context(), lmc, 84)
+		be.In(t, "Transaction group not found", err.Error())
+	})
+}
+
+func TestShowUngroupForm(t *testing.T) {
+	group := &transactionItem{
+		t:        &lm.Transaction{ID: 84, Payee: "Breakfast", IsGroup: true},
+		children: []*lm.Transaction{{ID: 1}, {ID: 2}},
+	}
+	m := model{currentTransaction: group, sessionState: detailedTransaction}
+
+	// nothing is ungrouped until it is confirmed
+	result, _ := showUngroupForm(m)
+	m = result.(model)
+	be.Equal(t, ungroupTransactionState, m.sessionState)
+	be.In(t, "Ungroup Breakfast?", m.ungroupForm.View())
+
+	_, _ = handleEscape(tea.KeyMsg{Type: tea.KeyEsc}, &m)
+	be.Equal(t, detailedTransaction, m.sessionState)
+
+	// only group transactions can be ungrouped
+	m.currentTransaction = &transactionItem{t: &lm.Transaction{ID: 1}}
+	result, _ = showUngroupForm(m)
+	be.Equal(t, detailedTransaction, result.(model).sessionState)
+}
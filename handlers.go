@@ -2,42 +2,51 @@ package main
 
 import (
 	"context"
+	"slices"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/overview"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 	lm "github.com/icco/lunchmoney"
-	"golang.org/x/sync/errgroup"
 )
 
 // Message types for different API responses.
 type (
 	getRecurringExpensesMsg struct {
+		cacheInfo
 		recurringExpenses []*lm.RecurringExpense
 	}
 
 	getAccountsMsg struct {
+		cacheInfo
 		plaidAccounts []*lm.PlaidAccount
 		assets        []*lm.Asset
 	}
 
 	getCategoriesMsg struct {
+		cacheInfo
 		categories []*lm.Category
 	}
 
 	getsTransactionsMsg struct {
+		cacheInfo
 		ts     []*lm.Transaction
 		period Period
 	}
 
 	getUserMsg struct {
+		cacheInfo
 		user *lm.User
 	}
 
 	getTagsMsg struct {
+		cacheInfo
 		tags []*lm.Tag
 	}
 
@@ -47,6 +56,7 @@ type (
 	}
 
 	getBudgetsMsg struct {
+		cacheInfo
 		budgets []*lm.Budget
 		period  Period
 	}
@@ -67,9 +77,10 @@ func (m model) handleWindowSize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 	m.overview.Viewport.Height = msg.Height - takenHeight
 
 	m.transactions.SetSize(msg.Width-h, msg.Height-v-takenHeight)
-	m.budgets.SetSize(msg.Width-h, msg.Height-v-standardVerticalOffset)
+	m.budgets.SetSize(msg.Width-h, msg.Height-v-standardVerticalOffset-budgetSummaryHeight)
 	m.configView.SetSize(msg.Width-h, msg.Height-v-standardVerticalOffset)
 	m.recurringExpenses.SetSize(msg.Width-h, msg.Height-v-standardVerticalOffset)
+	m.trends.SetSize(msg.Width-h, msg.Height-v-standardVerticalOffset)
 
 	m.help.Width = msg.Width
 
@@ -82,6 +93,10 @@ func (m model) handleWindowSize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 			WithWidth(msg.Width)
 	}
 
+	if m.splitForm != nil {
+		m.splitForm = m.splitForm.WithHeight(msg.Height - insertFormHeightOffset).WithWidth(msg.Width)
+	}
+
 	return m, nil
 }
 
@@ -117,13 +132,17 @@ func (m model) handleGetCategories(msg getCategoriesMsg) (tea.Model, tea.Cmd) {
 	m.loadingState.set("categories")
 	m.sessionState = m.checkIfLoading()
 
-	return m, tea.Batch(m.getTransactions, tea.WindowSize())
+	// refreshed categories are picked up by the next transactions load
+	if msg.background {
+		return m, nil
+	}
+	return m, tea.Batch(m.getTransactions, tea.WindowSize(), msg.refresh)
 }
 
 func (m model) handleGetRecurringExpenses(msg getRecurringExpensesMsg) (tea.Model, tea.Cmd) {
 	m.recurringExpenses.SetRecurringExpenses(msg.recurringExpenses)
 	m.loadingState.set("recurring expenses")
-	return m, m.recurringExpenses.Init()
+	return m, tea.Batch(m.recurringExpenses.Init(), msg.refresh)
 }
 
 func (m model) handleGetAccounts(msg getAccountsMsg) (tea.Model, tea.Cmd) {
@@ -142,22 +161,18 @@ func (m model) handleGetAccounts(msg getAccountsMsg) (tea.Model, tea.Cmd) {
 	m.loadingState.set("accounts")
 	m.sessionState = m.checkIfLoading()
 
-	return m, nil
+	return m, msg.refresh
 }
 
 func (m model) handleGetTransactions(msg getsTransactionsMsg) (tea.Model, tea.Cmd) {
-	var filteredTransactions []*lm.Transaction
-	if m.hidePendingTransactions {
-		// Filter out pending transactions
-		for _, t := range msg.ts {
-			if t.Status != pendingStatus {
-				filteredTransactions = append(filteredTransactions, t)
-			}
-		}
-	} else {
-		filteredTransactions = msg.ts
+	// a refresh that finished after the user moved to another period is outdated
+	if msg.background && !m.isCurrentPeriod(msg.period) {
+		return m, nil
 	}
 
+	// grouped transactions are collapsed under their group so they are not counted twice
+	filteredTransactions, children := collapseGroups(m.withoutHiddenTransactions(msg.ts))
+
 	items := make([]list.Item, len(filteredTransactions))
 	for i, t := range filteredTransactions {
 		items[i] = transactionItem{
@@ -165,9 +180,17 @@ func (m model) handleGetTransactions(msg getsTransactionsMsg) (tea.Model, tea.Cm
 			category:     m.idToCategory[t.CategoryID],
 			plaidAccount: m.plaidAccounts[t.PlaidAccountID],
 			asset:        m.assets[t.AssetID],
+			tags:         transactionTags(t),
+			children:     children[t.ID],
 		}
 	}
 
+	// a background refresh keeps what the user was doing with the list
+	filterUncleared := msg.background && m.isFilteredUncleared
+	if msg.background {
+		keepMarks(m.originalTransactions, items)
+	}
+
 	cmd := m.transactions.SetItems(items)
 
 	// Store original transactions and reset filter state
@@ -175,13 +198,74 @@ func (m model) handleGetTransactions(msg getsTransactionsMsg) (tea.Model, tea.Cm
 	m.isFilteredUncleared = false
 
 	m.transactionsStats = newTransactionStats(items)
+	// the comparison is loaded again for the period, until then the one of the previous
+	// period would show the wrong changes
+	comparing := !msg.background && m.overview.Comparison() != overview.NoComparison
+	if comparing {
+		m.overview.SetComparison(m.overview.Comparison())
+	}
 	m.overview.SetTransactions(filteredTransactions)
 	m.period = msg.period
 
+	// show when the transactions were cached until they are up to date
+	m.cachedAt = time.Time{}
+	if msg.refresh != nil || m.lmCache.isOffline() {
+		m.cachedAt = msg.cachedAt
+	}
+
 	m.loadingState.set("transactions")
 	m.sessionState = m.checkIfLoading()
 
-	return m, cmd
+	if filterUncleared {
+		filtered, _ := filterUnclearedTransactions(m)
+		if fm, ok := filtered.(model); ok {
+			m = fm
+		}
+	}
+
+	// the comparison follows the period, a background refresh keeps the period
+	var comparisonCmd tea.Cmd
+	if comparing {
+		comparisonCmd = m.getComparisonTransactions
+	}
+
+	return m, tea.Batch(cmd, msg.refresh, comparisonCmd)
+}
+
+// withoutHiddenTransactions filters out the pending transactions when they are hidden.
+func (m model) withoutHiddenTransactions(ts []*lm.Transaction) []*lm.Transaction {
+	if !m.hidePendingTransactions {
+		return ts
+	}
+
+	var filtered []*lm.Transaction
+	for _, t := range ts {
+		if t.Status != pendingStatus {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// isCurrentPeriod reports whether p is the period the user is looking at.
+func (m model) isCurrentPeriod(p Period) bool {
+	current := m.selectedPeriod()
+	return current.startDate() == p.startDate() && current.endDate() == p.endDate()
+}
+
+// keepMarks marks the transactions in items that are marked in previous.
+func keepMarks(previous, items []list.Item) {
+	marked := make(map[int64]bool)
+	for _, t := range markedTransactionItems(previous) {
+		marked[t.t.ID] = true
+	}
+
+	for i, item := range items {
+		if t, ok := item.(transactionItem); ok && marked[t.t.ID] {
+			t.marked = true
+			items[i] = t
+		}
+	}
 }
 
 func (m model) handleGetUser(msg getUserMsg) (tea.Model, tea.Cmd) {
@@ -190,7 +274,7 @@ func (m model) handleGetUser(msg getUserMsg) (tea.Model, tea.Cmd) {
 	m.sessionState = m.checkIfLoading()
 	m.overview.SetCurrency(m.user.PrimaryCurrency)
 	m.overview.SetUser(m.user)
-	return m, nil
+	return m, msg.refresh
 }
 
 func (m model) handleGetTags(msg getTagsMsg) (tea.Model, tea.Cmd) {
@@ -201,22 +285,26 @@ func (m model) handleGetTags(msg getTagsMsg) (tea.Model, tea.Cmd) {
 	m.tags = tags
 	m.loadingState.set("tags")
 	m.sessionState = m.checkIfLoading()
-	return m, nil
+	return m, msg.refresh
 }
 
 func (m model) handleGetBudgets(msg getBudgetsMsg) (tea.Model, tea.Cmd) {
-	items := make([]list.Item, len(msg.budgets))
-	for i, b := range msg.budgets {
-		items[i] = budgetItem{
-			b:        b,
-			category: m.idToCategory[int64(b.CategoryID)],
-		}
+	if msg.background && !m.isCurrentPeriod(msg.period) {
+		return m, nil
+	}
+
+	var currency string
+	if m.user != nil {
+		currency = m.user.PrimaryCurrency
 	}
 
+	items := newBudgetItems(msg.budgets, m.idToCategory, currency)
+	m.budgetTotals = budgetTotals(items, currency)
+
 	cmd := m.budgets.SetItems(items)
 	m.period = msg.period
 
-	return m, cmd
+	return m, tea.Batch(cmd, msg.refresh)
 }
 
 // Helper function to check if an error is a 401 Unauthorized error.
@@ -235,131 +323,68 @@ func handleAuthError(err error) tea.Msg {
 	return authErrorMsg{err: err}
 }
 
-// API call functions.
-func (m model) getRecurringExpenses() tea.Msg {
-	ctx := context.Background()
-
-	recurringExpenses, err := m.lmc.GetRecurringExpenses(ctx, nil)
-	if err != nil {
-		if is401Error(err) {
-			return handleAuthError(err)
-		}
-		return nil
-	}
-	log.Debug("got recurring expenses")
+// API call functions. Data is read through the cache, see loadCached.
+func (m model) getCategories() tea.Msg {
+	return loadCached(m.lmCache, m.lmCache.categoriesQuery(), func(categories []*lm.Category, info cacheInfo) tea.Msg {
+		return getCategoriesMsg{cacheInfo: info, categories: categories}
+	})()
+}
 
-	return getRecurringExpensesMsg{recurringExpenses: recurringExpenses}
+func (m model) getRecurringExpenses() tea.Msg {
+	return loadCached(m.lmCache, m.lmCache.recurringExpensesQuery(),
+		func(recurringExpenses []*lm.RecurringExpense, info cacheInfo) tea.Msg {
+			log.Debug("got recurring expenses")
+			return getRecurringExpensesMsg{cacheInfo: info, recurringExpenses: recurringExpenses}
+		},
+	)()
 }
 
 func (m model) getAccounts() tea.Msg {
-	ctx := context.Background()
-
-	var errGroup errgroup.Group
-	var plaidAccounts []*lm.PlaidAccount
-	var assets []*lm.Asset
-
-	errGroup.Go(func() error {
-		pas, err := m.lmc.GetPlaidAccounts(ctx)
-		if err != nil {
-			return err
-		}
-		plaidAccounts = pas
-		return nil
-	})
-
-	errGroup.Go(func() error {
-		as, err := m.lmc.GetAssets(ctx)
-		if err != nil {
-			return err
-		}
-		assets = as
-		return nil
-	})
-
-	if err := errGroup.Wait(); err != nil {
-		if is401Error(err) {
-			return handleAuthError(err)
-		}
-		return err
-	}
-
-	return getAccountsMsg{plaidAccounts: plaidAccounts, assets: assets}
+	return loadCached(m.lmCache, m.lmCache.accountsQuery(), func(accounts cachedAccounts, info cacheInfo) tea.Msg {
+		return getAccountsMsg{cacheInfo: info, plaidAccounts: accounts.PlaidAccounts, assets: accounts.Assets}
+	})()
 }
 
 func (m model) getTransactions() tea.Msg {
-	ctx := context.Background()
-
-	m.period.setPeriod(m.currentPeriod, m.periodType)
-
-	sd := m.period.startDate()
-	ed := m.period.endDate()
-
-	ts, err := m.lmc.GetTransactions(ctx, &lm.TransactionFilters{
-		DebitAsNegative: &m.debitsAsNegative,
-		StartDate:       &sd,
-		EndDate:         &ed,
-	})
-	if err != nil {
-		if is401Error(err) {
-			return handleAuthError(err)
-		}
-		return nil
-	}
+	period := m.selectedPeriod()
+
+	query := m.lmCache.transactionsQuery(period.startDate(), period.endDate(), m.debitsAsNegative)
+	return loadCached(m.lmCache, query, func(ts []*lm.Transaction, info cacheInfo) tea.Msg {
+		// reverse the slice so the most recent transactions are at the top
+		slices.Reverse(ts)
+		return getsTransactionsMsg{cacheInfo: info, ts: ts, period: period}
+	})()
+}
 
-	// reverse the slice so the most recent transactions are at the top
-	for i, j := 0, len(ts)-1; i < j; i, j = i+1, j-1 {
-		ts[i], ts[j] = ts[j], ts[i]
+// newTransactionFilters builds the filters used to fetch transactions within a date range.
+// It is shared by the TUI and the CLI so both request transactions the same way.
+func newTransactionFilters(startDate, endDate string, debitsAsNegative bool) *lm.TransactionFilters {
+	return &lm.TransactionFilters{
+		DebitAsNegative: &debitsAsNegative,
+		StartDate:       &startDate,
+		EndDate:         &endDate,
 	}
-
-	return getsTransactionsMsg{ts: ts, period: m.period}
 }
 
 func (m model) getUser() tea.Msg {
-	u, err := m.lmc.GetUser(context.Background())
-	if err != nil {
-		if is401Error(err) {
-			return handleAuthError(err)
-		}
-		return nil
-	}
-
-	return getUserMsg{user: u}
+	return loadCached(m.lmCache, m.lmCache.userQuery(), func(u *lm.User, info cacheInfo) tea.Msg {
+		return getUserMsg{cacheInfo: info, user: u}
+	})()
 }
 
 func (m model) getTags() tea.Msg {
-	ctx := context.Background()
-
-	tags, err := m.lmc.GetTags(ctx)
-	if err != nil {
-		if is401Error(err) {
-			return handleAuthError(err)
-		}
-		return nil
-	}
-
-	return getTagsMsg{tags: tags}
+	return loadCached(m.lmCache, m.lmCache.tagsQuery(), func(tags []*lm.Tag, info cacheInfo) tea.Msg {
+		return getTagsMsg{cacheInfo: info, tags: tags}
+	})()
 }
 
 func (m model) getBudgets() tea.Msg {
-	ctx := context.Background()
-
-	m.period.setPeriod(m.currentPeriod, m.periodType)
-
-	sd := m.period.startDate()
-	ed := m.period.endDate()
-
-	budgets, err := m.lmc.GetBudgets(ctx, &lm.BudgetFilters{
-		StartDate: sd,
-		EndDate:   ed,
-	})
-	if err != nil {
-		if is401Error(err) {
-			return handleAuthError(err)
-		}
-		return nil
-	}
+	period := m.selectedPeriod()
 
-	return getBudgetsMsg{budgets: budgets, period: m.period}
+	query := m.lmCache.budgetsQuery(period.startDate(), period.endDate())
+	return loadCached(m.lmCache, query, func(budgets []*lm.Budget, info cacheInfo) tea.Msg {
+		return getBudgetsMsg{cacheInfo: info, budgets: budgets, period: period}
+	})()
 }
 
 func (m model) updateTransactionStatus(t *lm.Transaction) tea.Cmd {
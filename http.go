@@ -2,8 +2,11 @@ package main
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/Rshep3087/lunchtui/cache"
 	"github.com/charmbracelet/log"
 )
 
@@ -39,3 +42,51 @@ func (l *loggerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 func newLoggingTransport(transport http.RoundTripper, logger *log.Logger) http.RoundTripper {
 	return &loggerTransport{transport: transport, logger: logger}
 }
+
+// offlineTransport fails every request so nothing reaches the API in offline mode.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errOffline
+}
+
+// invalidatedResources maps the API endpoints to the cached resources that change
+// when data is written to them.
+var invalidatedResources = map[string][]string{
+	"transactions":       {cache.Transactions, cache.Budgets},
+	"categories":         {cache.Categories, cache.Transactions, cache.Budgets},
+	"tags":               {cache.Tags, cache.Transactions},
+	"assets":             {cache.Accounts},
+	"plaid_accounts":     {cache.Accounts},
+	"budgets":            {cache.Budgets},
+	"recurring_expenses": {cache.RecurringExpenses},
+}
+
+// cacheInvalidatingTransport invalidates the cached resources changed by successful
+// writes, so changes made from the CLI or the TUI are never hidden by the cache.
+type cacheInvalidatingTransport struct {
+	transport http.RoundTripper
+	store     *cache.Store
+}
+
+func (c *cacheInvalidatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil || req.Method == http.MethodGet || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp, err
+	}
+
+	// paths look like /v1/transactions/123
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if i := slices.Index(segments, "v1"); i >= 0 && i+1 < len(segments) {
+		for _, resource := range invalidatedResources[segments[i+1]] {
+			if invalidateErr := c.store.Invalidate(resource); invalidateErr != nil {
+				log.Warn("failed to invalidate cache", "resource", resource, "error", invalidateErr)
+			}
+		}
+	}
+	return resp, nil
+}
+
+func newCacheInvalidatingTransport(transport http.RoundTripper, store *cache.Store) http.RoundTripper {
+	return &cacheInvalidatingTransport{transport: transport, store: store}
+}
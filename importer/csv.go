@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSVMapping maps transaction fields to CSV column headers.
+// Empty column names are not imported.
+type CSVMapping struct {
+	Date       string
+	Amount     string
+	Payee      string
+	Category   string
+	Account    string
+	Notes      string
+	Currency   string
+	ExternalID string
+	// DateLayout is the Go time layout of the date column.
+	DateLayout string
+}
+
+// DefaultCSVMapping returns the mapping used when no columns are configured.
+// It matches the columns written by `lunchtui transaction list -o csv`.
+func DefaultCSVMapping() CSVMapping {
+	return CSVMapping{
+		Date:       "date",
+		Amount:     "amount",
+		Payee:      "payee",
+		Category:   "category",
+		Account:    "account",
+		Notes:      "notes",
+		Currency:   "currency",
+		ExternalID: "external_id",
+		DateLayout: dateLayout,
+	}
+}
+
+// WithOverrides returns a copy of the mapping with the given field=column overrides applied.
+func (m CSVMapping) WithOverrides(overrides map[string]string) (CSVMapping, error) {
+	for field, column := range overrides {
+		switch strings.ToLower(field) {
+		case "date":
+			m.Date = column
+		case "amount":
+			m.Amount = column
+		case "payee":
+			m.Payee = column
+		case "category":
+			m.Category = column
+		case "account":
+			m.Account = column
+		case "notes":
+			m.Notes = column
+		case "currency":
+			m.Currency = column
+		case "external_id":
+			m.ExternalID = column
+		default:
+			return m, fmt.Errorf("unknown CSV mapping field %q", field)
+		}
+	}
+	return m, nil
+}
+
+// ParseCSV parses transactions from a CSV file with a header row.
+// Headers are matched case-insensitively against the mapping.
+func ParseCSV(r io.Reader, mapping CSVMapping) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV file is empty")
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, required := range []string{mapping.Date, mapping.Amount} {
+		if _, ok := columns[strings.ToLower(required)]; !ok {
+			return nil, fmt.Errorf("CSV file has no %q column", required)
+		}
+	}
+
+	layout := mapping.DateLayout
+	if layout == "" {
+		layout = dateLayout
+	}
+
+	var rows []Row
+	for line := 2; ; line++ {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			rows = append(rows, Row{Line: line, Err: readErr})
+			continue
+		}
+
+		value := func(column string) string {
+			i, ok := columns[strings.ToLower(column)]
+			if column == "" || !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := Row{
+			Line:       line,
+			Payee:      value(mapping.Payee),
+			Category:   value(mapping.Category),
+			Account:    value(mapping.Account),
+			Notes:      value(mapping.Notes),
+			Currency:   strings.ToLower(value(mapping.Currency)),
+			ExternalID: value(mapping.ExternalID),
+		}
+		row.Date, row.Err = normalizeDate(value(mapping.Date), layout)
+		if row.Err == nil {
+			row.Amount, row.Err = normalizeAmount(value(mapping.Amount))
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
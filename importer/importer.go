@@ -0,0 +1,108 @@
+// Package importer parses transactions from CSV, OFX and QIF files.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a supported import file format.
+type Format string
+
+const (
+	// FormatCSV is a comma separated values file with a header row.
+	FormatCSV Format = "csv"
+	// FormatOFX is an Open Financial Exchange file (SGML or XML).
+	FormatOFX Format = "ofx"
+	// FormatQIF is a Quicken Interchange Format file.
+	FormatQIF Format = "qif"
+)
+
+// dateLayout is the date format used by the Lunch Money API.
+const dateLayout = "2006-01-02"
+
+// Row is a single transaction parsed from an import file.
+type Row struct {
+	// Line is the line (CSV) or record (OFX, QIF) number the row came from.
+	Line       int
+	Date       string
+	Amount     string
+	Payee      string
+	Category   string
+	Account    string
+	Notes      string
+	Currency   string
+	ExternalID string
+	// Err is set when the row could not be parsed.
+	Err error
+}
+
+// ParseFormat returns the Format for name, detecting it from the file extension of path when name is empty.
+func ParseFormat(name, path string) (Format, error) {
+	if name == "" {
+		name = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	switch Format(strings.ToLower(name)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatOFX, "qfx":
+		return FormatOFX, nil
+	case FormatQIF:
+		return FormatQIF, nil
+	}
+
+	return "", fmt.Errorf("unsupported import format %q (must be csv, ofx or qif)", name)
+}
+
+// amountPattern matches an amount with an optional sign, commas only as thousands separators
+// and a point as the decimal separator.
+var amountPattern = regexp.MustCompile(`^[-+]?(\d{1,3}(,\d{3})+|\d*)(\.\d*)?$`)
+
+// normalizeAmount strips currency symbols and thousands separators and validates the result.
+// Amounts wrapped in parentheses are treated as negative. Decimal commas, such as 12,50, are
+// rejected rather than read as thousands separators.
+func normalizeAmount(s string) (string, error) {
+	amount := strings.TrimSpace(s)
+	negative := strings.HasPrefix(amount, "(") && strings.HasSuffix(amount, ")")
+	amount = strings.Trim(amount, "()")
+	amount = strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == ',', r == '-', r == '+':
+			return r
+		default:
+			return -1
+		}
+	}, amount)
+
+	if strings.TrimSpace(s) == "" {
+		return "", errors.New("missing amount")
+	}
+	if !amountPattern.MatchString(amount) {
+		return "", fmt.Errorf("invalid amount: %s (the decimal separator must be a point)", s)
+	}
+	amount = strings.ReplaceAll(amount, ",", "")
+	if _, err := strconv.ParseFloat(amount, 64); err != nil {
+		return "", fmt.Errorf("invalid amount: %s", s)
+	}
+	if negative && !strings.HasPrefix(amount, "-") {
+		amount = "-" + amount
+	}
+	return amount, nil
+}
+
+// normalizeDate parses s with the first matching layout and formats it for the API.
+func normalizeDate(s string, layouts ...string) (string, error) {
+	value := strings.TrimSpace(s)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format(dateLayout), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date: %s", s)
+}
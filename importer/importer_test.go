@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		path     string
+		expected Format
+		wantErr  bool
+	}{
+		{name: "from extension", path: "export.CSV", expected: FormatCSV},
+		{name: "qfx is ofx", path: "bank.qfx", expected: FormatOFX},
+		{name: "explicit format wins", format: "qif", path: "export.txt", expected: FormatQIF},
+		{name: "unknown extension", path: "export.txt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := ParseFormat(tt.format, tt.path)
+			if tt.wantErr {
+				be.Nonzero(t, err)
+				return
+			}
+			be.NilErr(t, err)
+			be.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestNormalizeAmount(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "12.50", expected: "12.50"},
+		{input: "$1,234.56", expected: "1234.56"},
+		{input: "(45.00)", expected: "-45.00"},
+		{input: "-3", expected: "-3"},
+		{input: "", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "12,50", wantErr: true},
+		{input: "1.234,56", wantErr: true},
+		{input: "1,23.00", wantErr: true},
+		{input: "-1,234,567", expected: "-1234567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			amount, err := normalizeAmount(tt.input)
+			if tt.wantErr {
+				be.Nonzero(t, err)
+				return
+			}
+			be.NilErr(t, err)
+			be.Equal(t, tt.expected, amount)
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := `Posted,Description,Debit,Category
+01/15/2024,Coffee Shop,4.50,Dining
+01/16/2024,"Grocery, Inc","$1,020.00",
+not a date,Bad Row,1.00,
+`
+	mapping, err := DefaultCSVMapping().WithOverrides(map[string]string{
+		"date":   "posted",
+		"payee":  "Description",
+		"amount": "debit",
+	})
+	be.NilErr(t, err)
+	mapping.DateLayout = "01/02/2006"
+
+	rows, err := ParseCSV(strings.NewReader(input), mapping)
+	be.NilErr(t, err)
+	be.Equal(t, 3, len(rows))
+
+	be.Equal(t, Row{Line: 2, Date: "2024-01-15", Amount: "4.50", Payee: "Coffee Shop", Category: "Dining"}, rows[0])
+	be.Equal(t, Row{Line: 3, Date: "2024-01-16", Amount: "1020.00", Payee: "Grocery, Inc"}, rows[1])
+	be.Equal(t, 4, rows[2].Line)
+	be.Nonzero(t, rows[2].Err)
+}
+
+func TestParseCSVMissingColumn(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("payee,amount\nCoffee,1.00\n"), DefaultCSVMapping())
+	be.Nonzero(t, err)
+
+	_, err = DefaultCSVMapping().WithOverrides(map[string]string{"unknown": "column"})
+	be.Nonzero(t, err)
+}
+
+func TestParseOFX(t *testing.T) {
+	input := `OFXHEADER:100
+DATA:OFXSGML
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>USD
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240115120000[-5:EST]
+<TRNAMT>-4.50
+<FITID>abc123
+<NAME>Coffee &amp; Co
+<MEMO>Latte
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240116
+<TRNAMT>1000.00
+<FITID>def456
+<PAYEE>Employer
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+	rows, err := ParseOFX(strings.NewReader(input))
+	be.NilErr(t, err)
+	be.AllEqual(t, []Row{
+		{
+			Line:       1,
+			Date:       "2024-01-15",
+			Amount:     "-4.50",
+			Payee:      "Coffee & Co",
+			Notes:      "Latte",
+			Currency:   "usd",
+			ExternalID: "abc123",
+		},
+		{
+			Line:       2,
+			Date:       "2024-01-16",
+			Amount:     "1000.00",
+			Payee:      "Employer",
+			Currency:   "usd",
+			ExternalID: "def456",
+		},
+	}, rows)
+
+	_, err = ParseOFX(strings.NewReader("date,amount\n"))
+	be.Nonzero(t, err)
+}
+
+func TestParseQIF(t *testing.T) {
+	input := `!Type:Bank
+D1/15'24
+T-4.50
+PCoffee Shop
+LDining
+MLatte
+^
+D01/16/2024
+U1,000.00
+PEmployer
+L[Savings]
+N1001
+^
+D01/17/2024
+T-20.00
+PPlumber
+MSink
+N0
+^
+D01/18/2024
+T-80.00
+N1002
+MRent
+^
+DNot a date
+T1.00
+`
+
+	rows, err := ParseQIF(strings.NewReader(input))
+	be.NilErr(t, err)
+	be.Equal(t, 5, len(rows))
+
+	be.Equal(t, Row{
+		Line:     1,
+		Date:     "2024-01-15",
+		Amount:   "-4.50",
+		Payee:    "Coffee Shop",
+		Category: "Dining",
+		Notes:    "Latte",
+	}, rows[0])
+	be.Equal(t, Row{
+		Line:   2,
+		Date:   "2024-01-16",
+		Amount: "1000.00",
+		Payee:  "Employer",
+		Notes:  "Check 1001",
+	}, rows[1])
+	// check numbers are not unique, so they are never external IDs
+	be.Equal(t, "Sink", rows[2].Notes)
+	be.Equal(t, "", rows[2].ExternalID)
+	be.Equal(t, "Rent (check 1002)", rows[3].Notes)
+	be.Equal(t, 5, rows[4].Line)
+	be.Nonzero(t, rows[4].Err)
+}
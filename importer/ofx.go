@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ofxTransactionPattern matches a single <STMTTRN> block.
+	ofxTransactionPattern = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	// ofxElementPattern matches an element and its value. OFX 1.x (SGML) does not close
+	// elements, so the value runs until the next tag or line break.
+	ofxElementPattern = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+	// ofxCurrencyPattern matches the default currency of a statement.
+	ofxCurrencyPattern = regexp.MustCompile(`(?i)<CURDEF>([^<\r\n]*)`)
+)
+
+// ofxDateLength is the length of the date part of an OFX datetime (YYYYMMDD).
+const ofxDateLength = 8
+
+// ParseOFX parses the transactions of every statement in an OFX (or QFX) file.
+// Debits are negative amounts, as written by the bank.
+func ParseOFX(r io.Reader) ([]Row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OFX file: %w", err)
+	}
+
+	content := string(data)
+	if !strings.Contains(strings.ToUpper(content), "<OFX>") {
+		return nil, errors.New("not an OFX file")
+	}
+
+	var currency string
+	if match := ofxCurrencyPattern.FindStringSubmatch(content); match != nil {
+		currency = strings.ToLower(strings.TrimSpace(match[1]))
+	}
+
+	blocks := ofxTransactionPattern.FindAllStringSubmatch(content, -1)
+	rows := make([]Row, 0, len(blocks))
+	for i, block := range blocks {
+		elements := make(map[string]string)
+		for _, element := range ofxElementPattern.FindAllStringSubmatch(block[1], -1) {
+			elements[strings.ToUpper(element[1])] = html.UnescapeString(strings.TrimSpace(element[2]))
+		}
+
+		row := Row{
+			Line:       i + 1,
+			Payee:      elements["NAME"],
+			Notes:      elements["MEMO"],
+			Currency:   currency,
+			ExternalID: elements["FITID"],
+		}
+		if row.Payee == "" {
+			row.Payee = elements["PAYEE"]
+		}
+
+		posted := elements["DTPOSTED"]
+		if len(posted) >= ofxDateLength {
+			posted = posted[:ofxDateLength]
+		}
+		row.Date, row.Err = normalizeDate(posted, "20060102")
+		if row.Err == nil {
+			row.Amount, row.Err = normalizeAmount(elements["TRNAMT"])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
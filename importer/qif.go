@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// qifDateLayouts are the date formats seen in QIF exports. Quicken writes
+// two-digit years after a ' which is normalized to / before parsing.
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "01-02-2006", dateLayout}
+
+// ParseQIF parses the transactions of a QIF file. Debits are negative amounts.
+func ParseQIF(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+
+	var (
+		rows    []Row
+		current Row
+		date    string
+		amount  string
+		check   string
+		record  = 1
+		hasData bool
+	)
+
+	flush := func() {
+		if !hasData {
+			return
+		}
+		current.Line = record
+		current.Date, current.Err = normalizeDate(strings.ReplaceAll(date, "'", "/"), qifDateLayouts...)
+		if current.Err == nil {
+			current.Amount, current.Err = normalizeAmount(amount)
+		}
+		current.Notes = qifNotes(current.Notes, check)
+		rows = append(rows, current)
+		current, date, amount, check, hasData = Row{}, "", "", "", false
+		record++
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		code, value := line[0], strings.TrimSpace(line[1:])
+		switch code {
+		case '^':
+			flush()
+			continue
+		case 'D':
+			date = value
+		case 'T', 'U':
+			amount = value
+		case 'P':
+			current.Payee = value
+		case 'M':
+			current.Notes = value
+		case 'L':
+			// Transfers are written as [Account]; only categories are imported.
+			if !strings.HasPrefix(value, "[") {
+				current.Category = value
+			}
+		case 'N':
+			// the check or reference number is not unique, so it is kept in the notes
+			// rather than used to find duplicates
+			check = value
+		default:
+			continue
+		}
+		hasData = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read QIF file: %w", err)
+	}
+
+	// The final record may not be terminated with ^.
+	flush()
+
+	return rows, nil
+}
+
+// qifNotes adds the check number of a transaction to its memo. Numbers that are 0 mean
+// there is no check.
+func qifNotes(memo, check string) string {
+	if check == "" || strings.Trim(check, "0") == "" {
+		return memo
+	}
+	if memo == "" {
+		return "Check " + check
+	}
+	return memo + " (check " + check + ")"
+}
@@ -13,10 +13,12 @@ type keyMap struct {
 	overview       key.Binding
 	recurring      key.Binding
 	budgets        key.Binding
+	trends         key.Binding
 	config         key.Binding
 	nextPeriod     key.Binding
 	previousPeriod key.Binding
 	switchPeriod   key.Binding
+	pickPeriod     key.Binding
 	escape         key.Binding
 	fullHelp       key.Binding
 	quit           key.Binding
@@ -41,6 +43,7 @@ func (km keyMap) FullHelp() [][]key.Binding {
 			km.transactions,
 			km.budgets,
 			km.recurring,
+			km.trends,
 			km.config,
 			km.quit,
 			km.fullHelp,
@@ -49,6 +52,7 @@ func (km keyMap) FullHelp() [][]key.Binding {
 			km.nextPeriod,
 			km.previousPeriod,
 			km.switchPeriod,
+			km.pickPeriod,
 		},
 	}
 }
@@ -71,22 +75,30 @@ func initializeKeyMap() keyMap {
 			key.WithKeys("b"),
 			key.WithHelp("b", "budgets"),
 		),
+		trends: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "trends"),
+		),
 		config: key.NewBinding(
 			key.WithKeys("g"),
 			key.WithHelp("g", "configuration"),
 		),
 		nextPeriod: key.NewBinding(
 			key.WithKeys("]"),
-			key.WithHelp("]", "next month"),
+			key.WithHelp("]", "next period"),
 		),
 		previousPeriod: key.NewBinding(
 			key.WithKeys("["),
-			key.WithHelp("[", "previous month"),
+			key.WithHelp("[", "previous period"),
 		),
 		switchPeriod: key.NewBinding(
 			key.WithKeys("s"),
 			key.WithHelp("s", "switch range"),
 		),
+		pickPeriod: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "pick range"),
+		),
 		escape: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "escape"),
@@ -155,6 +167,52 @@ func isInputBlocked(m *model) bool {
 		return true
 	}
 
+	if m.bulkActionForm != nil && m.bulkActionForm.State == huh.StateNormal {
+		return true
+	}
+
+	if m.splitForm != nil && m.splitForm.State == huh.StateNormal {
+		return true
+	}
+
+	if m.budgetForm != nil && m.budgetForm.State == huh.StateNormal {
+		return true
+	}
+
+	if m.periodForm != nil && m.periodForm.State == huh.StateNormal {
+		return true
+	}
+
+	if m.accountForm != nil && m.accountForm.State == huh.StateNormal {
+		return true
+	}
+
+	if m.assetForm != nil && m.assetForm.State == huh.StateNormal {
+		return true
+	}
+
+	if m.tagForm != nil && m.tagForm.State == huh.StateNormal {
+		return true
+	}
+
+	if m.rulesPreviewForm != nil && m.rulesPreviewForm.State == huh.StateNormal {
+		return true
+	}
+
+	if m.ungroupForm != nil && m.ungroupForm.State == huh.StateNormal {
+		return true
+	}
+
+	// Block input while a bulk action is updating transactions
+	if m.bulkJob != nil && m.bulkJob.running() {
+		return true
+	}
+
+	// The AI review table uses its own keys
+	if m.sessionState == aiReviewState {
+		return true
+	}
+
 	if m.sessionState == loading {
 		return true
 	}
@@ -175,6 +233,8 @@ func handleNavigationKeys(msg tea.KeyMsg, m *model) (tea.Model, tea.Cmd) {
 		return retrievePreviousPeriod(m)
 	case key.Matches(msg, m.keys.switchPeriod):
 		return switchPeriodType(m)
+	case key.Matches(msg, m.keys.pickPeriod):
+		return showPeriodForm(m)
 	}
 
 	return m, nil
@@ -211,6 +271,14 @@ func handleSessionStateKeys(msg tea.KeyMsg, m *model) (tea.Model, tea.Cmd) {
 			return m, m.getBudgets
 		}
 
+	case key.Matches(msg, m.keys.trends):
+		if m.sessionState != trendsState {
+			m.previousSessionState = m.sessionState
+			m.sessionState = trendsState
+			m.trends.SetLoading()
+			return m, m.getTrends
+		}
+
 	case key.Matches(msg, m.keys.config):
 		if m.sessionState != configView {
 			m.previousSessionState = m.sessionState
@@ -250,6 +318,94 @@ func handleEscape(msg tea.KeyMsg, m *model) (tea.Model, tea.Cmd) {
 		return m, m.getTransactions
 	}
 
+	if m.sessionState == bulkActions {
+		log.Debug("handling escape in bulk actions state")
+		m.previousSessionState = m.sessionState
+		m.sessionState = transactions
+		m.bulkActionForm.State = huh.StateAborted
+		return m, m.transactions.NewStatusMessage("Bulk action cancelled")
+	}
+
+	if m.sessionState == bulkUpdating {
+		return finishBulkJob(m)
+	}
+
+	if m.sessionState == splitTransactionState {
+		log.Debug("handling escape in split transaction state")
+		m.previousSessionState = m.sessionState
+		m.sessionState = detailedTransaction
+		m.splitForm.State = huh.StateAborted
+		m.split = nil
+		return m, m.transactions.NewStatusMessage("Split cancelled")
+	}
+
+	if m.sessionState == editBudgetState {
+		log.Debug("handling escape in edit budget state")
+		m.previousSessionState = m.sessionState
+		m.sessionState = budgets
+		m.budgetForm.State = huh.StateAborted
+		m.budgetEdit = nil
+		return m, m.budgets.NewStatusMessage("Budget not changed")
+	}
+
+	if m.sessionState == periodPickerState {
+		log.Debug("handling escape in period picker state")
+		m.sessionState = m.previousSessionState
+		m.periodForm.State = huh.StateAborted
+		m.periodPick = nil
+		return m, nil
+	}
+
+	if m.sessionState == accountPickerState {
+		log.Debug("handling escape in account picker state")
+		m.sessionState = m.previousSessionState
+		m.accountForm.State = huh.StateAborted
+		m.accountPick = nil
+		return m, nil
+	}
+
+	if m.sessionState == assetFormState {
+		log.Debug("handling escape in asset form state")
+		m.sessionState = m.previousSessionState
+		m.assetForm.State = huh.StateAborted
+		m.assetEdit = nil
+		return m, nil
+	}
+
+	if m.sessionState == editTagsState {
+		log.Debug("handling escape in edit tags state")
+		m.previousSessionState = m.sessionState
+		m.sessionState = detailedTransaction
+		m.tagForm.State = huh.StateAborted
+		m.tagEdit = nil
+		return m, nil
+	}
+
+	if m.sessionState == rulesPreviewState {
+		log.Debug("handling escape in rules preview state")
+		m.previousSessionState = m.sessionState
+		m.sessionState = transactions
+		m.rulesPreviewForm.State = huh.StateAborted
+		m.rulesPreview = nil
+		return m, m.transactions.NewStatusMessage("Rules not applied")
+	}
+
+	if m.sessionState == ungroupTransactionState {
+		log.Debug("handling escape in ungroup transaction state")
+		m.previousSessionState = m.sessionState
+		m.sessionState = detailedTransaction
+		m.ungroupForm.State = huh.StateAborted
+		return m, nil
+	}
+
+	if m.sessionState == aiReviewState {
+		log.Debug("handling escape in AI review state")
+		m.aiReview = nil
+		m.previousSessionState = m.sessionState
+		m.sessionState = transactions
+		return m, m.transactions.NewStatusMessage("AI categorization cancelled")
+	}
+
 	// handle if user is filtering transactions and presses escape
 	if m.sessionState == transactions && m.transactions.FilterState() == list.Filtering {
 		log.Debug("handling escape in transactions filtering")
@@ -0,0 +1,124 @@
+// Package liability classifies accounts as assets or liabilities for net worth, using
+// built-in rules for the Lunch Money and Plaid account types and the overrides from the
+// `[[networth_accounts]]` section of the config file.
+package liability
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/icco/lunchmoney"
+)
+
+// liabilityTypes are the account types that are owed rather than owned, both the asset
+// types of Lunch Money and the account types of Plaid.
+var liabilityTypes = map[string]bool{
+	"credit":          true,
+	"loan":            true,
+	"other liability": true,
+}
+
+// liabilitySubtypes are the account subtypes that are liabilities whatever their type,
+// such as a mortgage with the generic type "other".
+var liabilitySubtypes = map[string]bool{
+	"credit card":    true,
+	"line of credit": true,
+	"mortgage":       true,
+	"home equity":    true,
+	"student":        true,
+}
+
+// Override sets the classification of one account, for accounts the built-in rules get
+// wrong, such as a loan given to someone else.
+type Override struct {
+	// Account is the name or ID of the plaid account or asset.
+	Account string `mapstructure:"account"`
+	// Liability classifies the account as a liability, or as an asset when false.
+	Liability bool `mapstructure:"liability"`
+}
+
+// Account is the part of a plaid account or asset that decides its classification.
+type Account struct {
+	ID          int64
+	Name        string
+	DisplayName string
+	Type        string
+	Subtype     string
+}
+
+// FromAsset returns the account of a manually managed asset.
+func FromAsset(a *lunchmoney.Asset) Account {
+	return Account{
+		ID:          a.ID,
+		Name:        a.Name,
+		DisplayName: a.DisplayName,
+		Type:        a.TypeName,
+		Subtype:     a.SubtypeName,
+	}
+}
+
+// FromPlaidAccount returns the account of an account synced through Plaid.
+func FromPlaidAccount(a *lunchmoney.PlaidAccount) Account {
+	return Account{
+		ID:          a.ID,
+		Name:        a.Name,
+		DisplayName: a.DisplayName,
+		Type:        a.Type,
+		Subtype:     a.Subtype,
+	}
+}
+
+// Classifier decides whether accounts are liabilities. The zero value only uses the
+// built-in rules.
+type Classifier struct {
+	overrides []Override
+}
+
+// New returns a classifier that applies the overrides before the built-in rules. The
+// first override that refers to an account wins.
+func New(overrides []Override) (Classifier, error) {
+	normalized := make([]Override, len(overrides))
+	for i, o := range overrides {
+		account := strings.ToLower(strings.TrimSpace(o.Account))
+		if account == "" {
+			return Classifier{}, fmt.Errorf("networth account %d: account is required", i+1)
+		}
+		normalized[i] = Override{Account: account, Liability: o.Liability}
+	}
+	return Classifier{overrides: normalized}, nil
+}
+
+// IsLiability reports whether the account is a liability.
+func (c Classifier) IsLiability(a Account) bool {
+	for _, o := range c.overrides {
+		if refersTo(o.Account, a) {
+			return o.Liability
+		}
+	}
+	return IsLiabilityType(a.Type, a.Subtype)
+}
+
+// IsLiabilityType reports whether the built-in rules classify an account type and
+// subtype as a liability.
+func IsLiabilityType(accountType, subtype string) bool {
+	return liabilityTypes[normalizeType(accountType)] || liabilitySubtypes[normalizeType(subtype)]
+}
+
+// normalizeType lowercases a type and spells it with spaces, as Plaid uses underscores.
+func normalizeType(s string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "_", " ")
+}
+
+// refersTo reports whether the lowercased account name or ID refers to the account.
+func refersTo(account string, a Account) bool {
+	for _, name := range []string{a.Name, a.DisplayName} {
+		if name != "" && strings.ToLower(html.UnescapeString(name)) == account {
+			return true
+		}
+	}
+
+	id, err := strconv.ParseInt(account, 10, 64)
+	return err == nil && id == a.ID
+}
@@ -0,0 +1,81 @@
+package liability
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/icco/lunchmoney"
+)
+
+func TestIsLiabilityType(t *testing.T) {
+	tests := []struct {
+		accountType string
+		subtype     string
+		expected    bool
+	}{
+		{accountType: "credit", subtype: "credit card", expected: true},
+		{accountType: "credit", subtype: "paypal", expected: true},
+		{accountType: "loan", subtype: "student", expected: true},
+		{accountType: "loan", subtype: "", expected: true},
+		{accountType: "other liability", subtype: "", expected: true},
+		{accountType: "other", subtype: "Mortgage", expected: true},
+		{accountType: "other", subtype: "line_of_credit", expected: true},
+		{accountType: "depository", subtype: "checking", expected: false},
+		{accountType: "real estate", subtype: "", expected: false},
+		{accountType: "investment", subtype: "401k", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accountType+"/"+tt.subtype, func(t *testing.T) {
+			be.Equal(t, tt.expected, IsLiabilityType(tt.accountType, tt.subtype))
+		})
+	}
+}
+
+func TestClassifier(t *testing.T) {
+	classifier, err := New([]Override{
+		{Account: "Loan to Sam", Liability: false},
+		{Account: "42", Liability: true},
+	})
+	be.NilErr(t, err)
+
+	tests := []struct {
+		name     string
+		account  Account
+		expected bool
+	}{
+		{
+			name:     "built-in rule",
+			account:  FromPlaidAccount(&lunchmoney.PlaidAccount{ID: 1, Type: "credit", Subtype: "credit card"}),
+			expected: true,
+		},
+		{
+			name:     "override by name",
+			account:  FromAsset(&lunchmoney.Asset{ID: 2, Name: "loan to sam", TypeName: "loan"}),
+			expected: false,
+		},
+		{
+			name: "override by display name",
+			account: FromPlaidAccount(&lunchmoney.PlaidAccount{
+				ID: 3, Name: "Personal Loan", DisplayName: "Loan to Sam", Type: "loan",
+			}),
+			expected: false,
+		},
+		{
+			name:     "override by ID",
+			account:  FromAsset(&lunchmoney.Asset{ID: 42, Name: "Tax owed", TypeName: "other asset"}),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.Equal(t, tt.expected, classifier.IsLiability(tt.account))
+		})
+	}
+}
+
+func TestNewRequiresAccount(t *testing.T) {
+	_, err := New([]Override{{Account: "Checking"}, {Account: " ", Liability: true}})
+	be.In(t, "networth account 2: account is required", err.Error())
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	lm "github.com/icco/lunchmoney"
+)
+
+// transactionUpdate extends lm.UpdateTransaction with fields the client library
+// does not support yet.
+type transactionUpdate struct {
+	lm.UpdateTransaction
+
+	// Tags replaces the tags on the transaction when set.
+	Tags *[]int `json:"tags,omitempty"`
+}
+
+// transactionSplitPart is one of the transactions a transaction is split into.
+type transactionSplitPart struct {
+	Amount     string `json:"amount"`
+	CategoryID *int64 `json:"category_id,omitempty"`
+	Notes      string `json:"notes,omitempty"`
+}
+
+// transactionUpdateRequest is the request body for updating a transaction.
+type transactionUpdateRequest struct {
+	Transaction *transactionUpdate     `json:"transaction,omitempty"`
+	Split       []transactionSplitPart `json:"split,omitempty"`
+	// DebitAsNegative is set when the split amounts use negative debits.
+	DebitAsNegative bool `json:"debit_as_negative,omitempty"`
+}
+
+// updateTransaction updates a transaction, including the fields that lm.Client.UpdateTransaction
+// cannot send.
+func updateTransaction(
+	ctx context.Context,
+	client *lm.Client,
+	id int64,
+	update *transactionUpdate,
+) (*lm.UpdateTransactionResp, error) {
+	resp, err := putTransaction(ctx, client, id, &transactionUpdateRequest{Transaction: update})
+	if err != nil {
+		return nil, fmt.Errorf("update transaction %d: %w", id, err)
+	}
+	return resp, nil
+}
+
+// splitTransaction splits a transaction into parts. The parts must add up to the
+// amount of the transaction, which becomes their hidden parent.
+func splitTransaction(
+	ctx context.Context,
+	client *lm.Client,
+	id int64,
+	parts []transactionSplitPart,
+	debitAsNegative bool,
+) (*lm.UpdateTransactionResp, error) {
+	resp, err := putTransaction(ctx, client, id, &transactionUpdateRequest{
+		Split:           parts,
+		DebitAsNegative: debitAsNegative,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("split transaction %d: %w", id, err)
+	}
+	return resp, nil
+}
+
+func putTransaction(
+	ctx context.Context,
+	client *lm.Client,
+	id int64,
+	req *transactionUpdateRequest,
+) (*lm.UpdateTransactionResp, error) {
+	body, err := client.Put(ctx, fmt.Sprintf("/v1/transactions/%d", id), req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lm.UpdateTransactionResp{}
+	if err = json.NewDecoder(body).Decode(resp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return resp, nil
+}
+
+// transactionTagsRequest is the request body for replacing the tags of a transaction.
+// Tags are given by ID, or by name to create a tag that does not exist yet.
+type transactionTagsRequest struct {
+	Transaction struct {
+		Tags []any `json:"tags"`
+	} `json:"transaction"`
+}
+
+// setTransactionTags replaces the tags of a transaction with the tags with tagIDs and new
+// tags named newTags, which Lunch Money creates.
+func setTransactionTags(ctx context.Context, client *lm.Client, id int64, tagIDs []int, newTags []string) error {
+	req := &transactionTagsRequest{}
+	req.Transaction.Tags = make([]any, 0, len(tagIDs)+len(newTags))
+	for _, tagID := range tagIDs {
+		req.Transaction.Tags = append(req.Transaction.Tags, tagID)
+	}
+	for _, name := range newTags {
+		req.Transaction.Tags = append(req.Transaction.Tags, name)
+	}
+
+	body, err := client.Put(ctx, fmt.Sprintf("/v1/transactions/%d", id), req)
+	if err != nil {
+		return fmt.Errorf("set tags of transaction %d: %w", id, err)
+	}
+
+	resp := &lm.UpdateTransactionResp{}
+	if err = json.NewDecoder(body).Decode(resp); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if !resp.Updated {
+		return fmt.Errorf("transaction %d was not updated", id)
+	}
+
+	return nil
+}
+
+// transactionGroup is the request body for grouping transactions into a new transaction.
+type transactionGroup struct {
+	Date         string  `json:"date"`
+	Payee        string  `json:"payee"`
+	CategoryID   *int64  `json:"category_id,omitempty"`
+	Notes        string  `json:"notes,omitempty"`
+	Tags         []int   `json:"tags,omitempty"`
+	Transactions []int64 `json:"transactions"`
+}
+
+// createTransactionGroup groups transactions and returns the ID of the new group transaction.
+func createTransactionGroup(ctx context.Context, client *lm.Client, group *transactionGroup) (int64, error) {
+	body, err := client.Post(ctx, "/v1/transactions/group", group)
+	if err != nil {
+		return 0, fmt.Errorf("group transactions: %w", err)
+	}
+
+	var id int64
+	if err = json.NewDecoder(body).Decode(&id); err != nil {
+		return 0, fmt.Errorf("decode response: %w", err)
+	}
+
+	return id, nil
+}
+
+// deleteTransactionGroup ungroups a group transaction and returns the IDs of the transactions
+// that were in it.
+func deleteTransactionGroup(ctx context.Context, client *lm.Client, id int64) ([]int64, error) {
+	data, err := deleteRequest(ctx, client, fmt.Sprintf("/v1/transactions/group/%d", id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("ungroup transaction %d: %w", id, err)
+	}
+
+	ungrouped := struct {
+		Transactions []int64 `json:"transactions"`
+	}{}
+	if err = json.Unmarshal(data, &ungrouped); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return ungrouped.Transactions, nil
+}
+
+// budgetUpdate is the request body for setting the budget of a category.
+type budgetUpdate struct {
+	StartDate  string `json:"start_date"`
+	CategoryID int64  `json:"category_id"`
+	Amount     string `json:"amount"`
+	Currency   string `json:"currency,omitempty"`
+}
+
+// upsertBudget sets the budget of a category for the budget period starting on StartDate.
+func upsertBudget(ctx context.Context, client *lm.Client, update *budgetUpdate) error {
+	if _, err := client.Put(ctx, "/v1/budgets", update); err != nil {
+		return fmt.Errorf("update budget for category %d: %w", update.CategoryID, err)
+	}
+	return nil
+}
+
+// removeBudget clears the budget of a category for the budget period starting on startDate.
+func removeBudget(ctx context.Context, client *lm.Client, startDate string, categoryID int64) error {
+	query := url.Values{}
+	query.Set("start_date", startDate)
+	query.Set("category_id", strconv.FormatInt(categoryID, 10))
+
+	if _, err := deleteRequest(ctx, client, "/v1/budgets", query); err != nil {
+		return fmt.Errorf("remove budget for category %d: %w", categoryID, err)
+	}
+	return nil
+}
+
+// deleteRequest sends a DELETE request and returns the response body. lm.Client cannot send
+// DELETE requests, so the request is made directly with its HTTP client.
+func deleteRequest(ctx context.Context, client *lm.Client, path string, query url.Values) ([]byte, error) {
+	// like lm.Client, the path replaces the path of the base URL
+	u := *client.Base
+	u.Path = path
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := client.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	// errors are reported in the body, sometimes with a 200 status
+	errResp := lm.ErrorResponse{}
+	if json.Unmarshal(data, &errResp) == nil && errResp.Error() != "" {
+		return nil, fmt.Errorf("%s: %s", resp.Status, errResp.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+
+	return data, nil
+}
+
+// assetCreate is the request body for creating a manually managed asset.
+type assetCreate struct {
+	TypeName        string `json:"type_name"`
+	SubtypeName     string `json:"subtype_name,omitempty"`
+	Name            string `json:"name"`
+	DisplayName     string `json:"display_name,omitempty"`
+	Balance         string `json:"balance"`
+	BalanceAsOf     string `json:"balance_as_of,omitempty"`
+	Currency        string `json:"currency,omitempty"`
+	InstitutionName string `json:"institution_name,omitempty"`
+}
+
+// createAsset creates a manually managed asset, which lm.Client cannot do yet.
+func createAsset(ctx context.Context, client *lm.Client, create *assetCreate) (*lm.Asset, error) {
+	body, err := client.Post(ctx, "/v1/assets", create)
+	if err != nil {
+		return nil, fmt.Errorf("create asset %q: %w", create.Name, err)
+	}
+
+	asset := &lm.Asset{}
+	if err = json.NewDecoder(body).Decode(asset); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return asset, nil
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	configview "github.com/Rshep3087/lunchtui/config"
+	"github.com/Rshep3087/lunchtui/liability"
 	"github.com/Rshep3087/lunchtui/overview"
 	"github.com/Rshep3087/lunchtui/recurring"
+	"github.com/Rshep3087/lunchtui/rules"
+	"github.com/Rshep3087/lunchtui/trends"
 	"github.com/spf13/viper"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -36,12 +42,34 @@ type Config struct {
 	Colors configview.Colors `toml:"colors"`
 	// AI contains AI provider configuration
 	AI AIConfig `toml:"ai"`
+	// Rules are the local categorization rules
+	Rules []rules.Rule `toml:"rules"`
+	// Liabilities classifies accounts for the net worth, with the overrides of the
+	// networth_accounts section
+	Liabilities liability.Classifier `toml:"-"`
+	// PeriodType is the period type the TUI starts in, from the --period, --start and
+	// --end flags
+	PeriodType string `toml:"-"`
+	// Period is the range the TUI starts in when PeriodType is custom
+	Period Period `toml:"-"`
 }
 
 // AIConfig holds configuration for AI providers.
 type AIConfig struct {
+	// Provider selects the AI provider: anthropic (default), openai or ollama
+	Provider string `toml:"provider"`
+	// BaseURL is the base URL of an OpenAI compatible API, such as a llama.cpp server
+	BaseURL string `toml:"base_url"`
+	// Model overrides the model used for recommendations
+	Model string `toml:"model"`
+	// APIKey is the API key for an OpenAI compatible API
+	APIKey string `toml:"api_key"`
 	// AnthropicAPIKey is the API key for Anthropic Claude
 	AnthropicAPIKey string `toml:"anthropic_api_key"`
+	// HistoryDays is how many days of categorized transactions are used as examples, zero disables them
+	HistoryDays int `toml:"history_days"`
+	// AutoAcceptConfidence accepts batch recommendations at or above this confidence, zero disables it
+	AutoAcceptConfidence float64 `toml:"auto_accept_confidence"`
 }
 
 type model struct {
@@ -75,8 +103,33 @@ type model struct {
 	period        Period
 	currentPeriod time.Time
 	// periodType is the type of range for the transactions
-	// ex. month, year
+	// ex. month, year, custom
 	periodType string
+	// customPeriod is the range selected with the range picker when periodType is custom
+	customPeriod Period
+	// periodForm is the range picker form
+	periodForm *huh.Form
+	// periodPick holds the values of the range picker form
+	periodPick *periodPick
+	// accountForm is the account picker that leads to the account details
+	accountForm *huh.Form
+	// accountPick holds the account chosen in the account picker
+	accountPick *accountRef
+	// accountDetail holds the account shown in the account details
+	accountDetail *accountDetail
+	// assetForm creates or changes a manually managed asset
+	assetForm *huh.Form
+	// assetEdit holds the values of the asset form
+	assetEdit *assetEdit
+	// tagForm adds and removes the tags of the current transaction
+	tagForm *huh.Form
+	// rulesPreviewForm confirms the changes in rulesPreview before they are applied
+	rulesPreviewForm *huh.Form
+	rulesPreview     *rulesPreview
+	// ungroupForm confirms ungrouping the current group transaction
+	ungroupForm *huh.Form
+	// tagEdit holds the values of the tag form
+	tagEdit *tagEdit
 
 	transactionsStats *transactionsStats
 	// debitsAsNegative is a flag to show debits as negative numbers
@@ -93,6 +146,16 @@ type model struct {
 	notesInput textinput.Model
 	// isEditingNotes indicates if the user is currently editing transaction notes
 	isEditingNotes bool
+	// bulkActionForm is the form for choosing an action for the marked transactions
+	bulkActionForm *huh.Form
+	// bulkJob tracks the bulk action being applied to the marked transactions
+	bulkJob *bulkJob
+	// aiReview holds the batch AI recommendations being reviewed
+	aiReview *aiBatchReview
+	// splitForm is the form for splitting the current transaction
+	splitForm *huh.Form
+	// split holds the parts entered in the split form
+	split *transactionSplit
 
 	categoryForm *huh.Form
 	// aiRecommendation holds the current AI category recommendation
@@ -116,14 +179,26 @@ type model struct {
 
 	// recurringExpenses is a model for the recurring expenses widget
 	recurringExpenses recurring.Model
+	// trends charts the spending of the last months
+	trends trends.Model
 	// budgets is a bubbletea list model of budgets
 	budgets list.Model
+	// budgetListKeys is the keybindings for the budgets list
+	budgetListKeys *budgetListKeyMap
+	// budgetTotals is the budget and spending of all expense categories in the period
+	budgetTotals budgetSummary
+	// budgetForm is the form for editing or clearing the budget of a category
+	budgetForm *huh.Form
+	// budgetEdit holds the budget change entered in the budget form
+	budgetEdit *budgetEdit
 	// configView is a model for the configuration view
 	configView configview.Model
 	// lmc is the Lunch Money client
 	lmc *lm.Client
-	// categoryService handles category data operations
-	categoryService *CategoryService
+	// lmCache reads Lunch Money data through the local cache
+	lmCache *cachedClient
+	// cachedAt is when the shown transactions were fetched, zero when they are up to date
+	cachedAt time.Time
 
 	loadingState loadingState
 	styles       styles
@@ -131,13 +206,14 @@ type model struct {
 
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
-		m.categoryService.GetCategoriesCmd,
+		m.getCategories,
 		m.getUser,
 		m.getAccounts,
 		m.loadingSpinner.Tick,
 		m.getRecurringExpenses,
 		m.getTags,
 		m.getBudgets,
+		m.getNetWorthHistory,
 	)
 }
 
@@ -157,23 +233,65 @@ func setupDebugLogging(config Config) (func(), error) {
 	}, nil
 }
 
-func initializeAIRecommender(config Config) *AIRecommender {
-	if config.AI.AnthropicAPIKey == "" {
-		log.Debug("no Anthropic API key provided, AI recommender disabled")
+func initializeAIRecommender(config Config, lmc *lm.Client) *AIRecommender {
+	provider := newAIProvider(config.AI)
+	if provider == nil {
+		log.Debug("no AI provider configured, AI recommender disabled")
 		return nil
 	}
-	log.Debug("initializing AI recommender with Anthropic provider", "api_key_length", len(config.AI.AnthropicAPIKey))
-	provider := NewAnthropicProvider(config.AI.AnthropicAPIKey)
-	aiRecommender := NewAIRecommender(provider)
+	aiRecommender := NewAIRecommender(provider, newAIHistory(config.AI, lmc, config.DebitsAsNegative))
 	log.Debug("AI recommender initialized successfully", "enabled", aiRecommender.IsEnabled())
 	return aiRecommender
 }
 
+// newAIProvider returns the configured AI provider, or nil when it is missing credentials.
+// The config is expected to have passed validateAIConfig.
+func newAIProvider(config AIConfig) AIProvider {
+	switch config.Provider {
+	case openAIProvider:
+		baseURL := cmp.Or(config.BaseURL, defaultOpenAIBaseURL)
+		// A custom base URL points to a server that might not need a key
+		if config.APIKey == "" && baseURL == defaultOpenAIBaseURL {
+			return nil
+		}
+		log.Debug("initializing AI recommender with OpenAI compatible provider", "base_url", baseURL)
+		return NewOpenAIProvider(baseURL, cmp.Or(config.Model, defaultOpenAIModel), config.APIKey)
+	case ollamaAIProvider:
+		baseURL := cmp.Or(config.BaseURL, defaultOllamaBaseURL)
+		log.Debug("initializing AI recommender with Ollama provider", "base_url", baseURL)
+		return NewOpenAIProvider(baseURL, config.Model, config.APIKey)
+	default:
+		if config.AnthropicAPIKey == "" {
+			return nil
+		}
+		log.Debug("initializing AI recommender with Anthropic provider", "api_key_length", len(config.AnthropicAPIKey))
+		return NewAnthropicProvider(config.AnthropicAPIKey, config.Model)
+	}
+}
+
+// validateAIConfig checks the provider name and the settings the provider requires.
+func validateAIConfig(config AIConfig) error {
+	if config.HistoryDays < 0 {
+		return fmt.Errorf("invalid ai.history_days: %d (must be 0 or more)", config.HistoryDays)
+	}
+
+	switch config.Provider {
+	case "", anthropicAIProvider, openAIProvider:
+	case ollamaAIProvider:
+		if config.Model == "" {
+			return errors.New("ai.model is required for the ollama provider")
+		}
+	default:
+		return fmt.Errorf("invalid ai.provider: %s (must be 'anthropic', 'openai' or 'ollama')", config.Provider)
+	}
+	return nil
+}
+
 func createModel(
 	config Config,
 	lmc *lm.Client,
+	lmCache *cachedClient,
 	aiRecommender *AIRecommender,
-	categoryService *CategoryService,
 ) model {
 	tlKeyMap := newTransactionListKeyMap()
 	theme := newTheme(config.Colors)
@@ -187,18 +305,19 @@ func createModel(
 		sessionState:            loading,
 		previousSessionState:    overviewState,
 		lmc:                     lmc,
-		categoryService:         categoryService,
+		lmCache:                 lmCache,
 		aiRecommender:           aiRecommender,
 		transactionsListKeys:    tlKeyMap,
 		debitsAsNegative:        config.DebitsAsNegative,
 		hidePendingTransactions: config.HidePendingTransactions,
 		currentPeriod:           time.Now(),
 		period:                  Period{},
-		periodType:              "month",
+		periodType:              monthlyPeriodType,
 		loadingSpinner:          spinner.New(spinner.WithSpinner(spinner.Dot)),
 		overview: overview.New(
 			overview.Config{
 				ShowUserInfo: config.ShowUserInfo,
+				Liabilities:  config.Liabilities,
 				Colors: &overview.Colors{
 					Income:        theme.Income,
 					Expense:       theme.Expense,
@@ -211,6 +330,15 @@ func createModel(
 		),
 		recurringExpenses: recurring.New(recurring.Colors{
 			Primary: string(theme.Primary),
+			Warning: string(theme.Warning),
+		}),
+		trends: trends.New(trends.Colors{
+			Primary: string(theme.Primary),
+			Muted:   string(theme.Muted),
+			Overlays: []string{
+				string(theme.Primary), string(theme.Success), string(theme.Warning),
+				string(theme.Border), string(theme.Income), string(theme.Expense),
+			},
 		}),
 		configView: configview.New(configview.Colors{
 			Primary: string(theme.Primary),
@@ -226,11 +354,17 @@ func createModel(
 
 	delegate := m.newItemDelegate(newDeleteKeyMap())
 	m.transactions = createTransactionList(delegate, tlKeyMap)
-	m.budgets = createBudgetList(delegate)
+	m.budgetListKeys = newBudgetListKeyMap()
+	m.budgets = createBudgetList(delegate, m.budgetListKeys)
 	m.notesInput = textinput.New()
 	m.notesInput.Placeholder = "Enter notes..."
 	m.notesInput.CharLimit = 500
 
+	if config.PeriodType != "" {
+		m.periodType = config.PeriodType
+		m.customPeriod = config.Period
+	}
+
 	configData := configview.Config{
 		Debug:                   config.Debug,
 		Token:                   config.Token,
@@ -243,7 +377,7 @@ func createModel(
 	return m
 }
 
-func rootAction(_ context.Context, config Config, lmc *lm.Client) error {
+func rootAction(_ context.Context, config Config, lmc *lm.Client, lmCache *cachedClient) error {
 	cleanup, err := setupDebugLogging(config)
 	if err != nil {
 		return err
@@ -254,13 +388,18 @@ func rootAction(_ context.Context, config Config, lmc *lm.Client) error {
 	log.Debug("config loaded",
 		"debug", config.Debug,
 		"token_length", len(config.Token),
+		"ai_provider", config.AI.Provider,
 		"anthropic_key_length", len(config.AI.AnthropicAPIKey),
 		"config_file", viper.ConfigFileUsed(),
 	)
 
-	aiRecommender := initializeAIRecommender(config)
-	dataService := NewCategoryService(lmc)
-	m := createModel(config, lmc, aiRecommender, dataService)
+	// offline there is nothing to show until lunchtui has run online once
+	if _, cached := peekCached(lmCache, lmCache.userQuery()); lmCache.offline && !cached {
+		return errors.New("no cached data for offline mode, run lunchtui without --offline first")
+	}
+
+	aiRecommender := initializeAIRecommender(config, lmc)
+	m := createModel(config, lmc, lmCache, aiRecommender)
 
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, runErr := p.Run()
@@ -285,6 +424,11 @@ func createTransactionList(delegate list.DefaultDelegate, tlKeyMap *transactionL
 			tlKeyMap.filterUncleared,
 			tlKeyMap.refreshTransactions,
 			tlKeyMap.insertTransaction,
+			tlKeyMap.toggleMark,
+			tlKeyMap.markAll,
+			tlKeyMap.bulkActions,
+			tlKeyMap.applyRules,
+			tlKeyMap.aiCategorize,
 		}
 	}
 	return transactionList
@@ -121,6 +121,24 @@ func TestAdvancePeriod(t *testing.T) {
 			expectedState:     loading,
 			expectedPrevState: transactions,
 		},
+		{
+			name:              "advance monthly period from the 31st",
+			periodType:        monthlyPeriodType,
+			initialDate:       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			expectedDate:      time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			initialState:      transactions,
+			expectedState:     loading,
+			expectedPrevState: transactions,
+		},
+		{
+			name:              "advance weekly period",
+			periodType:        weeklyPeriodType,
+			initialDate:       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			expectedDate:      time.Date(2024, 2, 7, 0, 0, 0, 0, time.UTC),
+			initialState:      transactions,
+			expectedState:     loading,
+			expectedPrevState: transactions,
+		},
 		{
 			name:              "advance annual period",
 			periodType:        annualPeriodType,
@@ -179,6 +197,15 @@ func TestRetrievePreviousPeriod(t *testing.T) {
 			expectedState:     loading,
 			expectedPrevState: transactions,
 		},
+		{
+			name:              "retrieve previous quarterly period",
+			periodType:        quarterlyPeriodType,
+			initialDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+			expectedDate:      time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC),
+			initialState:      transactions,
+			expectedState:     loading,
+			expectedPrevState: transactions,
+		},
 		{
 			name:              "retrieve previous annual period",
 			periodType:        annualPeriodType,
@@ -228,17 +255,25 @@ func TestSwitchPeriodType(t *testing.T) {
 		expectedPrevState sessionState
 	}{
 		{
-			name:              "switch from monthly to annual",
+			name:              "switch from monthly to quarterly",
 			initialPeriodType: monthlyPeriodType,
-			expectedPeriod:    annualPeriodType,
+			expectedPeriod:    quarterlyPeriodType,
+			initialState:      transactions,
+			expectedState:     loading,
+			expectedPrevState: transactions,
+		},
+		{
+			name:              "switch from the last period type back to weekly",
+			initialPeriodType: last90DaysPeriodType,
+			expectedPeriod:    weeklyPeriodType,
 			initialState:      transactions,
 			expectedState:     loading,
 			expectedPrevState: transactions,
 		},
 		{
-			name:              "switch from annual to monthly",
-			initialPeriodType: annualPeriodType,
-			expectedPeriod:    monthlyPeriodType,
+			name:              "switch from custom to weekly",
+			initialPeriodType: customPeriodType,
+			expectedPeriod:    weeklyPeriodType,
 			initialState:      transactions,
 			expectedState:     loading,
 			expectedPrevState: transactions,
@@ -640,3 +675,73 @@ func TestFilterUnclearedTransactionsToggle(t *testing.T) {
 		t.Error("Expected isFilteredUncleared to be false after toggle")
 	}
 }
+
+func TestHandleGetTransactionsBackgroundRefresh(t *testing.T) {
+	current := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	var january, february Period
+	january.setPeriod(current, monthlyPeriodType)
+	february.setPeriod(current.AddDate(0, 1, 0), monthlyPeriodType)
+
+	items := []list.Item{
+		transactionItem{t: &lm.Transaction{ID: 1, Status: unclearedStatus}, marked: true},
+		transactionItem{t: &lm.Transaction{ID: 2, Status: clearedStatus}},
+	}
+	m := model{
+		sessionState:         transactions,
+		loadingState:         newLoadingState("transactions"),
+		currentPeriod:        current,
+		periodType:           monthlyPeriodType,
+		period:               january,
+		originalTransactions: items,
+		transactions:         list.New(items, list.NewDefaultDelegate(), 0, 0),
+		overview:             overview.New(overview.Config{}),
+	}
+
+	refreshed := []*lm.Transaction{
+		{ID: 1, Status: unclearedStatus},
+		{ID: 2, Status: clearedStatus},
+		{ID: 3, Status: clearedStatus},
+	}
+
+	// a refresh for a period the user already left is dropped
+	result, _ := m.handleGetTransactions(getsTransactionsMsg{
+		cacheInfo: cacheInfo{background: true},
+		ts:        refreshed,
+		period:    february,
+	})
+	be.Equal(t, 2, len(result.(model).transactions.Items()))
+
+	result, _ = m.handleGetTransactions(getsTransactionsMsg{
+		cacheInfo: cacheInfo{background: true},
+		ts:        refreshed,
+		period:    january,
+	})
+	updated := result.(model)
+	be.Equal(t, 3, len(updated.transactions.Items()))
+	be.Equal(t, 1, len(markedTransactionItems(updated.transactions.Items())))
+	be.True(t, updated.cachedAt.IsZero())
+}
+
+func TestFilterCategoryTransactions(t *testing.T) {
+	items := []list.Item{
+		transactionItem{t: &lm.Transaction{ID: 1, CategoryID: 1, Payee: "Grocer"}},
+		transactionItem{t: &lm.Transaction{ID: 2, CategoryID: 2, Payee: "Bus"}},
+		transactionItem{t: &lm.Transaction{ID: 3, CategoryID: 1, Payee: "Market"}},
+	}
+
+	m := model{sessionState: overviewState, isFilteredUncleared: true}
+	m.transactions = list.New(items, list.NewDefaultDelegate(), 80, 20)
+	m.originalTransactions = items
+
+	result, cmd := filterCategoryTransactions(m, overview.CategorySelectedMsg{CategoryID: 1, Name: "Food"})
+	resultModel := result.(model)
+
+	be.Equal(t, 2, len(resultModel.transactions.Items()))
+	for _, item := range resultModel.transactions.Items() {
+		be.Equal(t, int64(1), item.(transactionItem).t.CategoryID)
+	}
+	be.Equal(t, 3, len(resultModel.originalTransactions))
+	be.False(t, resultModel.isFilteredUncleared)
+	be.Equal(t, transactions, resultModel.sessionState)
+	be.Nonzero(t, cmd)
+}
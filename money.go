@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Rhymond/go-money"
+)
+
+// newMoney converts an amount in major units, such as 12.34, to money, rounding it to the
+// minor units of the currency. lm.ParseCurrency and money.NewFromFloat truncate instead,
+// which can lose a cent.
+func newMoney(amount float64, currency string) *money.Money {
+	minorUnits := math.Pow10(money.New(0, currency).Currency().Fraction)
+	return money.New(int64(math.Round(amount*minorUnits)), currency)
+}
+
+// parseAmount parses an amount such as "12.34" into money.
+func parseAmount(amount, currency string) (*money.Money, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	return newMoney(f, currency), nil
+}
+
+// formatAmount formats money as a plain decimal amount, such as -1234.50, for machine
+// readable output.
+func formatAmount(m *money.Money) string {
+	return strconv.FormatFloat(m.AsMajorUnits(), 'f', m.Currency().Fraction, 64)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+)
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		expected int64
+	}{
+		{name: "rounds up", amount: 0.29 * 3, currency: "usd", expected: 87},
+		{name: "rounds down", amount: 12.344, currency: "usd", expected: 1234},
+		{name: "negative", amount: -5.255, currency: "usd", expected: -526},
+		{name: "no minor units", amount: 1500.4, currency: "jpy", expected: 1500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.Equal(t, tt.expected, newMoney(tt.amount, tt.currency).Amount())
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	amount, err := parseAmount(" 12.50 ", "usd")
+	be.NilErr(t, err)
+	be.Equal(t, int64(1250), amount.Amount())
+
+	_, err = parseAmount("twelve", "usd")
+	be.Equal(t, `invalid amount "twelve"`, err.Error())
+}
@@ -4,85 +4,50 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// advancePeriod advances the current period by one month or year depending on the period type.
+// advancePeriod advances the current period by one period of the period type.
 func advancePeriod(m *model) (tea.Model, tea.Cmd) {
-	if m.periodType == monthlyPeriodType {
-		m.currentPeriod = m.currentPeriod.AddDate(0, 1, 0)
-	}
-
-	if m.periodType == annualPeriodType {
-		m.currentPeriod = m.currentPeriod.AddDate(1, 0, 0)
-	}
-
-	m.previousSessionState = m.sessionState
-
-	// Reload data based on current session state
-	switch m.previousSessionState {
-	case budgets:
-		// Budget loading doesn't block UI, stay in budgets state
-		m.sessionState = budgets
-		return m, m.getBudgets
-	case overviewState,
-		transactions,
-		detailedTransaction,
-		categorizeTransaction,
-		insertTransaction,
-		loading,
-		recurringExpenses,
-		configView,
-		errorState:
-		// All other states need to wait for transactions to load
-		m.sessionState = loading
-		m.loadingState.unset("transactions")
-		return m, m.getTransactions
-	}
-	// Unreachable - all states are handled above
-	return m, nil
+	m.stepPeriod(1)
+	return reloadPeriod(m)
 }
 
-// retrievePreviousPeriod retrieves the previous period by one month or year depending on the period type.
+// retrievePreviousPeriod retrieves the previous period of the period type.
 func retrievePreviousPeriod(m *model) (tea.Model, tea.Cmd) {
-	if m.periodType == monthlyPeriodType {
-		m.currentPeriod = m.currentPeriod.AddDate(0, -1, 0)
-	}
+	m.stepPeriod(-1)
+	return reloadPeriod(m)
+}
 
-	if m.periodType == annualPeriodType {
-		m.currentPeriod = m.currentPeriod.AddDate(-1, 0, 0)
+// switchPeriodType switches to the next period type, from a custom range back to the
+// first one.
+func switchPeriodType(m *model) (tea.Model, tea.Cmd) {
+	if m.periodType == customPeriodType {
+		// continue from the custom range instead of jumping back to today
+		m.currentPeriod = m.customPeriod.start
 	}
+	m.periodType = nextPeriodType(m.periodType)
+	return reloadPeriod(m)
+}
 
-	m.previousSessionState = m.sessionState
-
-	// Reload data based on current session state
-	switch m.previousSessionState {
-	case budgets:
-		// Budget loading doesn't block UI, stay in budgets state
-		m.sessionState = budgets
-		return m, m.getBudgets
-	case overviewState,
-		transactions,
-		detailedTransaction,
-		categorizeTransaction,
-		insertTransaction,
-		loading,
-		recurringExpenses,
-		configView,
-		errorState:
-		// All other states need to wait for transactions to load
-		m.sessionState = loading
-		m.loadingState.unset("transactions")
-		return m, m.getTransactions
+// selectedPeriod returns the period the user is looking at.
+func (m model) selectedPeriod() Period {
+	if m.periodType == customPeriodType {
+		return m.customPeriod
 	}
-	// Unreachable - all states are handled above
-	return m, nil
+	var p Period
+	p.setPeriod(m.currentPeriod, m.periodType)
+	return p
 }
 
-func switchPeriodType(m *model) (tea.Model, tea.Cmd) {
-	if m.periodType == monthlyPeriodType {
-		m.periodType = annualPeriodType
-	} else {
-		m.periodType = monthlyPeriodType
+// stepPeriod moves the selected period by n periods, custom ranges by their length.
+func (m *model) stepPeriod(n int) {
+	if m.periodType == customPeriodType {
+		m.customPeriod = m.customPeriod.shift(n)
+		return
 	}
+	m.currentPeriod = shiftPeriod(m.currentPeriod, m.periodType, n)
+}
 
+// reloadPeriod reloads the data of the current view after the period changed.
+func reloadPeriod(m *model) (tea.Model, tea.Cmd) {
 	m.previousSessionState = m.sessionState
 
 	// Reload data based on current session state
@@ -91,6 +56,15 @@ func switchPeriodType(m *model) (tea.Model, tea.Cmd) {
 		// Budget loading doesn't block UI, stay in budgets state
 		m.sessionState = budgets
 		return m, m.getBudgets
+	case trendsState:
+		// the trends end with the month of the period
+		m.sessionState = trendsState
+		m.trends.SetLoading()
+		return m, m.getTrends
+	case accountDetailState:
+		m.sessionState = accountDetailState
+		m.accountDetail.loading = true
+		return m, m.getAccountTransactions
 	case overviewState,
 		transactions,
 		detailedTransaction,
@@ -99,7 +73,18 @@ func switchPeriodType(m *model) (tea.Model, tea.Cmd) {
 		loading,
 		recurringExpenses,
 		configView,
-		errorState:
+		errorState,
+		bulkActions,
+		bulkUpdating,
+		aiReviewState,
+		splitTransactionState,
+		editBudgetState,
+		periodPickerState,
+		accountPickerState,
+		assetFormState,
+		editTagsState,
+		rulesPreviewState,
+		ungroupTransactionState:
 		// All other states need to wait for transactions to load
 		m.sessionState = loading
 		m.loadingState.unset("transactions")
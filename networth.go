@@ -1,6 +1,8 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/Rhymond/go-money"
 )
 
@@ -11,6 +13,31 @@ type NetWorthData struct {
 	TotalLiabilities *money.Money
 	Currency         string
 	Breakdown        *NetWorthBreakdown
+	// Subtotals is the net worth in each currency the accounts are held in
+	Subtotals []*money.Money
+	// Unconverted is the number of accounts left out because their balance could not
+	// be converted to the primary currency
+	Unconverted int
+}
+
+// MultiCurrency reports whether any account is held in another currency than the
+// primary one.
+func (nw *NetWorthData) MultiCurrency() bool {
+	for _, subtotal := range nw.Subtotals {
+		if !strings.EqualFold(subtotal.Currency().Code, nw.Currency) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubtotalsString formats the net worth in each currency, such as "$12.00 + €5.00".
+func (nw *NetWorthData) SubtotalsString() string {
+	subtotals := make([]string, len(nw.Subtotals))
+	for i, subtotal := range nw.Subtotals {
+		subtotals[i] = subtotal.Display()
+	}
+	return strings.Join(subtotals, " + ")
 }
 
 // NetWorthBreakdown provides detailed account breakdown by type.
@@ -30,15 +57,21 @@ type AccountSummary struct {
 	Amount          *money.Money `json:"-"` // Not exported in JSON, used for calculations
 	InstitutionName string       `json:"institution_name,omitempty"`
 	AccountType     string       `json:"account_type"` // "asset" or "plaid"
+	// liability is how the account was classified when the net worth was calculated
+	liability bool
 }
 
 // NetWorthJSONSummary converts NetWorthData to a JSON-friendly format for CLI output.
 type NetWorthJSONSummary struct {
-	NetWorth         string                 `json:"net_worth"`
-	Currency         string                 `json:"currency"`
-	TotalAssets      string                 `json:"total_assets"`
-	TotalLiabilities string                 `json:"total_liabilities"`
-	Breakdown        *NetWorthJSONBreakdown `json:"breakdown,omitempty"`
+	NetWorth         string `json:"net_worth"`
+	Currency         string `json:"currency"`
+	TotalAssets      string `json:"total_assets"`
+	TotalLiabilities string `json:"total_liabilities"`
+	// Subtotals is the net worth in each currency, only set when there are several
+	Subtotals map[string]string `json:"subtotals,omitempty"`
+	// UnconvertedAccounts is the number of accounts left out of the totals
+	UnconvertedAccounts int                    `json:"unconverted_accounts,omitempty"`
+	Breakdown           *NetWorthJSONBreakdown `json:"breakdown,omitempty"`
 }
 
 // NetWorthJSONBreakdown is the JSON-friendly version of NetWorthBreakdown.
@@ -66,6 +99,15 @@ func (nw *NetWorthData) ToJSON() *NetWorthJSONSummary {
 		Currency:         nw.Currency,
 		TotalAssets:      nw.TotalAssets.Display(),
 		TotalLiabilities: nw.TotalLiabilities.Display(),
+
+		UnconvertedAccounts: nw.Unconverted,
+	}
+
+	if nw.MultiCurrency() {
+		summary.Subtotals = make(map[string]string, len(nw.Subtotals))
+		for _, subtotal := range nw.Subtotals {
+			summary.Subtotals[subtotal.Currency().Code] = subtotal.Display()
+		}
 	}
 
 	if nw.Breakdown != nil {
@@ -116,7 +158,7 @@ func (a *AccountSummary) GetDisplayName() string {
 	return unescapeDisplayName(a.Name, a.DisplayName)
 }
 
-// IsLiability determines if this account is a liability based on type and subtype.
+// IsLiability reports whether the account was classified as a liability.
 func (a *AccountSummary) IsLiability() bool {
-	return a.Type == creditType && a.Subtype == creditCardSubtype
+	return a.liability
 }
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/viper"
+)
+
+// netWorthHistoryFile is the name of the net worth history in the lunchtui config directory.
+const netWorthHistoryFile = "networth_history.jsonl"
+
+// netWorthSnapshot is the net worth at one point in time. Snapshots are stored one per
+// line in the history file. Amounts are in minor units of the currency, such as cents.
+type netWorthSnapshot struct {
+	TakenAt          time.Time `json:"taken_at"`
+	Currency         string    `json:"currency"`
+	NetWorth         int64     `json:"net_worth_minor"`
+	TotalAssets      int64     `json:"total_assets_minor"`
+	TotalLiabilities int64     `json:"total_liabilities_minor"`
+	// AccountTypes is the total of each account type, negative for liabilities
+	AccountTypes map[string]int64  `json:"account_types_minor"`
+	Accounts     []snapshotAccount `json:"accounts"`
+}
+
+// snapshotAccount is the balance of an account in a snapshot.
+type snapshotAccount struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	AccountType string `json:"account_type"`
+	Category    string `json:"category"`
+	Liability   bool   `json:"liability,omitempty"`
+	Amount      int64  `json:"amount_minor"`
+}
+
+// newNetWorthSnapshot records the net worth data, which must include its breakdown.
+func newNetWorthSnapshot(data *NetWorthData, takenAt time.Time) netWorthSnapshot {
+	snapshot := netWorthSnapshot{
+		TakenAt:          takenAt,
+		Currency:         data.Currency,
+		NetWorth:         data.NetWorth.Amount(),
+		TotalAssets:      data.TotalAssets.Amount(),
+		TotalLiabilities: data.TotalLiabilities.Amount(),
+		AccountTypes:     make(map[string]int64),
+	}
+	if data.Breakdown == nil {
+		return snapshot
+	}
+
+	add := func(categories map[string][]*AccountSummary, liability bool) {
+		for category, accounts := range categories {
+			total := money.New(0, data.Currency)
+			for _, account := range accounts {
+				total, _ = total.Add(account.Amount)
+				snapshot.Accounts = append(snapshot.Accounts, snapshotAccount{
+					ID:          account.ID,
+					Name:        account.GetDisplayName(),
+					AccountType: account.AccountType,
+					Category:    category,
+					Liability:   liability,
+					Amount:      account.Amount.Amount(),
+				})
+			}
+			if liability {
+				total = total.Negative()
+			}
+			snapshot.AccountTypes[category] += total.Amount()
+		}
+	}
+	add(data.Breakdown.Assets, false)
+	add(data.Breakdown.Liabilities, true)
+
+	// map iteration is random, so the accounts are sorted for a stable history file
+	sort.Slice(snapshot.Accounts, func(i, j int) bool {
+		a, b := snapshot.Accounts[i], snapshot.Accounts[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.ID < b.ID
+	})
+	return snapshot
+}
+
+// netWorthHistoryPath returns the configured history file, by default in the lunchtui
+// config directory.
+func netWorthHistoryPath() (string, error) {
+	if path := viper.GetString("networth_history"); path != "" {
+		return path, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("find user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "lunchtui", netWorthHistoryFile), nil
+}
+
+// appendNetWorthSnapshot appends the snapshot to the history file, creating it if needed.
+func appendNetWorthSnapshot(path string, snapshot netWorthSnapshot) error {
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open history: %w", err)
+	}
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write history: %w", err)
+	}
+	return f.Close()
+}
+
+// readNetWorthHistory reads the snapshots of the history file, oldest first. A missing
+// file is an empty history.
+func readNetWorthHistory(path string) ([]netWorthSnapshot, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	defer f.Close()
+
+	var snapshots []netWorthSnapshot
+	scanner := bufio.NewScanner(f)
+	// snapshots with many accounts are longer than the default limit of 64KB
+	scanner.Buffer(nil, bufio.MaxScanTokenSize*16)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var snapshot netWorthSnapshot
+		if err = json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, fmt.Errorf("read history line %d: %w", line, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].TakenAt.Before(snapshots[j].TakenAt)
+	})
+	return snapshots, nil
+}
+
+// netWorthValues returns the net worth of each snapshot since the currency last changed,
+// as amounts in different currencies cannot be compared.
+func netWorthValues(snapshots []netWorthSnapshot) []float64 {
+	start := len(snapshots)
+	for start > 0 && strings.EqualFold(snapshots[start-1].Currency, snapshots[len(snapshots)-1].Currency) {
+		start--
+	}
+
+	values := make([]float64, 0, len(snapshots)-start)
+	for _, s := range snapshots[start:] {
+		values = append(values, money.New(s.NetWorth, s.Currency).AsMajorUnits())
+	}
+	return values
+}
+
+// getNetWorthHistoryMsg carries the net worth history for the overview trend.
+type getNetWorthHistoryMsg struct {
+	values []float64
+}
+
+// getNetWorthHistory reads the local net worth history. The trend is optional, so a
+// history that cannot be read is only logged.
+func (m model) getNetWorthHistory() tea.Msg {
+	path, err := netWorthHistoryPath()
+	if err != nil {
+		log.Debug("no net worth history", "error", err)
+		return nil
+	}
+
+	snapshots, err := readNetWorthHistory(path)
+	if err != nil {
+		log.Debug("failed to read net worth history", "path", path, "error", err)
+		return nil
+	}
+	return getNetWorthHistoryMsg{values: netWorthValues(snapshots)}
+}
+
+func (m model) handleGetNetWorthHistory(msg getNetWorthHistoryMsg) (tea.Model, tea.Cmd) {
+	m.overview.SetNetWorthHistory(msg.values)
+	return m, nil
+}
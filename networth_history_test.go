@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/liability"
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestNewNetWorthSnapshot(t *testing.T) {
+	assets := []*lm.Asset{
+		{ID: 2, Name: "Savings", TypeName: "cash", SubtypeName: "savings", Balance: "1000", Currency: "usd"},
+		{ID: 1, Name: "Checking", TypeName: "cash", SubtypeName: "savings", Balance: "250.5", Currency: "usd"},
+		{ID: 3, Name: "Visa", TypeName: "credit", SubtypeName: "credit card", Balance: "300", Currency: "usd"},
+	}
+	plaidAccounts := []*lm.PlaidAccount{
+		{ID: 4, Name: "Brokerage", Type: "investment", Balance: "5000", Currency: "usd"},
+	}
+	takenAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	data := calculateNetWorthData(assets, plaidAccounts, "USD", liability.Classifier{}, true)
+	snapshot := newNetWorthSnapshot(data, takenAt)
+
+	be.Equal(t, takenAt, snapshot.TakenAt)
+	be.Equal(t, "USD", snapshot.Currency)
+	be.Equal(t, int64(595050), snapshot.NetWorth)
+	be.Equal(t, int64(625050), snapshot.TotalAssets)
+	be.Equal(t, int64(30000), snapshot.TotalLiabilities)
+	be.Equal(t, 3, len(snapshot.AccountTypes))
+	be.Equal(t, int64(125050), snapshot.AccountTypes["Savings"])
+	be.Equal(t, int64(500000), snapshot.AccountTypes["Investment"])
+	be.Equal(t, int64(-30000), snapshot.AccountTypes["Credit Card"])
+
+	ids := make([]int64, len(snapshot.Accounts))
+	for i, account := range snapshot.Accounts {
+		ids[i] = account.ID
+	}
+	be.AllEqual(t, []int64{3, 4, 1, 2}, ids)
+	be.True(t, snapshot.Accounts[0].Liability)
+}
+
+func TestNetWorthHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lunchtui", netWorthHistoryFile)
+
+	snapshots, err := readNetWorthHistory(path)
+	be.NilErr(t, err)
+	be.Zero(t, len(snapshots))
+
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	be.NilErr(t, appendNetWorthSnapshot(path, netWorthSnapshot{TakenAt: march, Currency: "USD", NetWorth: 20000}))
+	be.NilErr(t, appendNetWorthSnapshot(path, netWorthSnapshot{
+		TakenAt: march.AddDate(0, -1, 0), Currency: "USD", NetWorth: 10000,
+	}))
+
+	snapshots, err = readNetWorthHistory(path)
+	be.NilErr(t, err)
+	be.AllEqual(t, []float64{100, 200}, netWorthValues(snapshots))
+
+	be.NilErr(t, os.WriteFile(path, []byte("{not json\n"), 0o600))
+	_, err = readNetWorthHistory(path)
+	be.In(t, "read history line 1", err.Error())
+}
+
+func TestNewNetWorthHistory(t *testing.T) {
+	snapshots := []netWorthSnapshot{
+		{
+			Currency:     "USD",
+			NetWorth:     100010,
+			AccountTypes: map[string]int64{"Savings": 120010, "Credit Card": -20000},
+		},
+		{
+			Currency:     "USD",
+			NetWorth:     150030,
+			AccountTypes: map[string]int64{"Savings": 130030, "Investment": 20000},
+		},
+		{
+			Currency:     "EUR",
+			NetWorth:     140000,
+			AccountTypes: map[string]int64{"Savings": 140000},
+		},
+	}
+
+	entries := newNetWorthHistory(snapshots)
+
+	be.Equal(t, 3, len(entries))
+	be.True(t, entries[0].Change == nil)
+	be.Zero(t, len(entries[0].AccountTypeChanges))
+	be.Equal(t, "500.20", formatAmount(entries[1].Change))
+	be.Equal(t, "100.20", formatAmount(entries[1].AccountTypeChanges["Savings"]))
+	be.Equal(t, "200.00", formatAmount(entries[1].AccountTypeChanges["Investment"]))
+	be.Equal(t, "200.00", formatAmount(entries[1].AccountTypeChanges["Credit Card"]))
+
+	// amounts in different currencies are not subtracted
+	be.True(t, entries[2].CurrencyChanged)
+	be.True(t, entries[2].Change == nil)
+	be.Zero(t, len(entries[2].AccountTypeChanges))
+	be.AllEqual(t, []float64{1400}, netWorthValues(snapshots))
+
+	out := netWorthHistoryJSON(entries)
+	be.Equal(t, "1500.30", out[1].NetWorth)
+	be.Equal(t, "500.20", out[1].Change)
+	be.Equal(t, "", out[2].Change)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+)
+
+// OpenAIProvider implements AIProvider for OpenAI compatible chat completion APIs.
+// Besides OpenAI it works with self-hosted servers such as Ollama and llama.cpp.
+type OpenAIProvider struct {
+	client  *http.Client
+	baseURL string
+	model   string
+	apiKey  string
+}
+
+// NewOpenAIProvider creates a new OpenAI compatible AI provider.
+// The API key is optional since most self-hosted servers do not require one.
+func NewOpenAIProvider(baseURL, model, apiKey string) *OpenAIProvider {
+	return &OpenAIProvider{
+		client:  &http.Client{},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		apiKey:  apiKey,
+	}
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model     string          `json:"model"`
+	Messages  []openAIMessage `json:"messages"`
+	MaxTokens int             `json:"max_tokens"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// RecommendCategory implements AIProvider interface.
+func (p *OpenAIProvider) RecommendCategory(
+	ctx context.Context,
+	transaction *lm.Transaction,
+	categories []*lm.Category,
+	examples []CategorizationExample,
+) (*CategoryRecommendation, error) {
+	prompt := buildCategorizationPrompt(transaction, categories, examples)
+
+	log.Debug(
+		"sending categorization request to OpenAI compatible API",
+		"base_url", p.baseURL,
+		"model", p.model,
+		"transaction_id", transaction.ID,
+	)
+
+	responseText, err := p.complete(ctx, prompt, aiMaxTokens)
+	if err != nil {
+		log.Error("failed to call OpenAI compatible API", "error", err)
+		return nil, fmt.Errorf("failed to call OpenAI compatible API: %w", err)
+	}
+
+	recommendation, err := parseCategoryRecommendation(responseText, categories)
+	if err != nil {
+		log.Error("failed to parse OpenAI compatible response", "error", err, "response", responseText)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	log.Debug("received categorization recommendation",
+		"category_id", recommendation.CategoryID,
+		"confidence", recommendation.Confidence)
+	return recommendation, nil
+}
+
+// RecommendCategories implements BatchAIProvider interface.
+func (p *OpenAIProvider) RecommendCategories(
+	ctx context.Context,
+	transactions []*lm.Transaction,
+	categories []*lm.Category,
+	examples map[int64][]CategorizationExample,
+) (map[int64]*CategoryRecommendation, error) {
+	log.Debug("sending batch categorization request to OpenAI compatible API",
+		"base_url", p.baseURL,
+		"model", p.model,
+		"transactions", len(transactions),
+	)
+
+	prompt := buildBatchCategorizationPrompt(transactions, categories, examples)
+	responseText, err := p.complete(ctx, prompt, aiMaxTokens*len(transactions))
+	if err != nil {
+		log.Error("failed to call OpenAI compatible API", "error", err)
+		return nil, fmt.Errorf("failed to call OpenAI compatible API: %w", err)
+	}
+
+	recommendations, err := parseBatchCategoryRecommendations(responseText, categories)
+	if err != nil {
+		log.Error("failed to parse OpenAI compatible response", "error", err, "response", responseText)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return recommendations, nil
+}
+
+// complete sends the prompt to the chat completions endpoint and returns the reply text.
+func (p *OpenAIProvider) complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	body, err := json.Marshal(openAIChatRequest{
+		Model:     p.model,
+		Messages:  []openAIMessage{{Role: "user", Content: prompt}},
+		MaxTokens: maxTokens,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result openAIChatResponse
+	if err = json.Unmarshal(data, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(data)))
+		}
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if result.Error != nil {
+		return "", fmt.Errorf("%s: %s", resp.Status, result.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if len(result.Choices) == 0 || strings.TrimSpace(result.Choices[0].Message.Content) == "" {
+		return "", errors.New("empty response")
+	}
+
+	return result.Choices[0].Message.Content, nil
+}
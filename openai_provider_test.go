@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+	"github.com/spf13/viper"
+)
+
+func TestOpenAIProviderRecommendCategory(t *testing.T) {
+	categories := []*lm.Category{{ID: 1, Name: "Groceries"}, {ID: 2, Name: "Coffee Shops"}}
+
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		wantErr  string
+	}{
+		{
+			name:   "recommendation",
+			status: http.StatusOK,
+			response: `{"choices":[{"message":{"role":"assistant","content":` +
+				`"Sure!\\n{\"category_id\": 2, \"confidence\": 120, \"reasoning\": \"coffee\"}"}}]}`,
+		},
+		{
+			name:     "api error",
+			status:   http.StatusNotFound,
+			response: `{"error":{"message":"model \"llama\" not found"}}`,
+			wantErr:  `failed to call OpenAI compatible API: 404 Not Found: model "llama" not found`,
+		},
+		{
+			name:     "non json error",
+			status:   http.StatusBadGateway,
+			response: "bad gateway",
+			wantErr:  "failed to call OpenAI compatible API: unexpected status 502 Bad Gateway: bad gateway",
+		},
+		{
+			name:     "no choices",
+			status:   http.StatusOK,
+			response: `{"choices":[]}`,
+			wantErr:  "failed to call OpenAI compatible API: empty response",
+		},
+		{
+			name:     "unknown category",
+			status:   http.StatusOK,
+			response: `{"choices":[{"message":{"content":"{\"category_id\": 9, \"confidence\": 50}"}}]}`,
+			wantErr:  "failed to parse response: recommended category ID 9 not found in available categories",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				be.Equal(t, "/v1/chat/completions", r.URL.Path)
+				be.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+				var req openAIChatRequest
+				be.NilErr(t, json.NewDecoder(r.Body).Decode(&req))
+				be.Equal(t, "llama3.2", req.Model)
+				be.Equal(t, aiMaxTokens, req.MaxTokens)
+				be.Equal(t, 1, len(req.Messages))
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			provider := NewOpenAIProvider(server.URL+"/v1/", "llama3.2", "secret")
+			trans := &lm.Transaction{ID: 1, Payee: "Starbucks"}
+			rec, err := provider.RecommendCategory(t.Context(), trans, categories, nil)
+			if tt.wantErr != "" {
+				be.Nonzero(t, err)
+				be.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			be.NilErr(t, err)
+			be.Equal(t, int64(2), rec.CategoryID)
+			be.Equal(t, "Coffee Shops", rec.CategoryName)
+			be.Equal(t, 100.0, rec.Confidence)
+			be.Equal(t, "coffee", rec.Reasoning)
+		})
+	}
+}
+
+func TestNewAIProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   AIConfig
+		expected AIProvider
+	}{
+		{
+			name:     "nothing configured",
+			config:   AIConfig{},
+			expected: nil,
+		},
+		{
+			name:   "openai defaults",
+			config: AIConfig{Provider: openAIProvider, APIKey: "key"},
+			expected: &OpenAIProvider{
+				client:  &http.Client{},
+				baseURL: defaultOpenAIBaseURL,
+				model:   defaultOpenAIModel,
+				apiKey:  "key",
+			},
+		},
+		{
+			name:     "openai without key",
+			config:   AIConfig{Provider: openAIProvider},
+			expected: nil,
+		},
+		{
+			name:   "llama.cpp server without key",
+			config: AIConfig{Provider: openAIProvider, BaseURL: "http://localhost:8080/v1", Model: "qwen"},
+			expected: &OpenAIProvider{
+				client:  &http.Client{},
+				baseURL: "http://localhost:8080/v1",
+				model:   "qwen",
+			},
+		},
+		{
+			name:   "ollama",
+			config: AIConfig{Provider: ollamaAIProvider, Model: "llama3.2"},
+			expected: &OpenAIProvider{
+				client:  &http.Client{},
+				baseURL: defaultOllamaBaseURL,
+				model:   "llama3.2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.DeepEqual(t, tt.expected, newAIProvider(tt.config))
+		})
+	}
+
+	anthropic, ok := newAIProvider(AIConfig{AnthropicAPIKey: "key"}).(*AnthropicProvider)
+	be.True(t, ok)
+	be.Equal(t, defaultAnthropicModel, anthropic.model)
+}
+
+func TestNewAIConfigOpenAIKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "env-key")
+
+	tests := []struct {
+		name     string
+		settings map[string]string
+		expected string
+	}{
+		{
+			name:     "default base url",
+			settings: map[string]string{"ai.provider": openAIProvider},
+			expected: "env-key",
+		},
+		{
+			name:     "explicit default base url",
+			settings: map[string]string{"ai.provider": openAIProvider, "ai.base_url": defaultOpenAIBaseURL},
+			expected: "env-key",
+		},
+		{
+			name:     "custom base url",
+			settings: map[string]string{"ai.provider": openAIProvider, "ai.base_url": "https://llm.example.com/v1"},
+			expected: "",
+		},
+		{
+			name:     "ollama",
+			settings: map[string]string{"ai.provider": ollamaAIProvider},
+			expected: "",
+		},
+		{
+			name: "configured key",
+			settings: map[string]string{
+				"ai.provider": openAIProvider, "ai.base_url": "https://llm.example.com/v1", "ai.api_key": "own-key",
+			},
+			expected: "own-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(viper.Reset)
+			for k, v := range tt.settings {
+				viper.Set(k, v)
+			}
+			be.Equal(t, tt.expected, newAIConfig().APIKey)
+		})
+	}
+}
+
+func TestValidateAIConfig(t *testing.T) {
+	be.NilErr(t, validateAIConfig(AIConfig{}))
+	be.NilErr(t, validateAIConfig(AIConfig{Provider: anthropicAIProvider}))
+	be.NilErr(t, validateAIConfig(AIConfig{Provider: ollamaAIProvider, Model: "llama3.2"}))
+	be.Equal(t, "ai.model is required for the ollama provider",
+		validateAIConfig(AIConfig{Provider: ollamaAIProvider}).Error())
+	be.Equal(t, "invalid ai.provider: gemini (must be 'anthropic', 'openai' or 'ollama')",
+		validateAIConfig(AIConfig{Provider: "gemini"}).Error())
+}
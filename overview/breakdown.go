@@ -0,0 +1,138 @@
+package overview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// cursorMarker starts the row under the cursor in the spending breakdown.
+const cursorMarker = "›"
+
+// breakdownRow is a group or category shown in the spending breakdown, in the order they
+// are drawn.
+type breakdownRow struct {
+	categoryID int64
+	name       string
+	group      bool
+}
+
+// CategorySelectedMsg is sent when the user picks a category in the spending breakdown, to
+// show its transactions.
+type CategorySelectedMsg struct {
+	CategoryID int64
+	Name       string
+}
+
+// handleBreakdownKey moves the cursor of the spending breakdown, expands and collapses
+// groups and selects categories. It reports whether the key was one of its keys.
+func (m *Model) handleBreakdownKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	// the breakdown may have fewer rows since the cursor last moved
+	m.breakdownCursor = min(m.breakdownCursor, max(len(m.breakdownRows)-1, 0))
+
+	switch {
+	case key.Matches(msg, m.keys.up):
+		m.breakdownCursor = max(m.breakdownCursor-1, 0)
+	case key.Matches(msg, m.keys.down):
+		m.breakdownCursor = min(m.breakdownCursor+1, max(len(m.breakdownRows)-1, 0))
+	case key.Matches(msg, m.keys.collapse):
+		m.setCollapsed(true)
+	case key.Matches(msg, m.keys.expand):
+		m.setCollapsed(false)
+	case key.Matches(msg, m.keys.selectCategory):
+		row, ok := m.cursorRow()
+		if !ok {
+			return nil, true
+		}
+		if row.group {
+			m.setCollapsed(!m.collapsedGroups[row.categoryID])
+			break
+		}
+		return func() tea.Msg {
+			return CategorySelectedMsg{CategoryID: row.categoryID, Name: row.name}
+		}, true
+	default:
+		return nil, false
+	}
+
+	m.UpdateViewport()
+	m.scrollToCursor()
+	return nil, true
+}
+
+// cursorRow returns the row under the cursor.
+func (m *Model) cursorRow() (breakdownRow, bool) {
+	if m.breakdownCursor < 0 || m.breakdownCursor >= len(m.breakdownRows) {
+		return breakdownRow{}, false
+	}
+	return m.breakdownRows[m.breakdownCursor], true
+}
+
+// setCollapsed collapses or expands the group under the cursor, or the group of the
+// category under it, moving the cursor to the group.
+func (m *Model) setCollapsed(collapsed bool) {
+	row, ok := m.cursorRow()
+	if !ok {
+		return
+	}
+
+	groupID := row.categoryID
+	if !row.group {
+		category := m.categories[row.categoryID]
+		if category == nil || category.GroupID == 0 {
+			return
+		}
+		groupID = category.GroupID
+	}
+
+	m.collapsedGroups[groupID] = collapsed
+	for i, r := range m.breakdownRows {
+		if r.group && r.categoryID == groupID {
+			m.breakdownCursor = i
+		}
+	}
+}
+
+// breakdownRowText records a row of the spending breakdown and highlights it when it is
+// under the cursor. Every row is indented to leave room for the cursor.
+func (m *Model) breakdownRowText(row breakdownRow, text string) string {
+	selected := len(m.breakdownRows) == m.breakdownCursor
+	m.breakdownRows = append(m.breakdownRows, row)
+	if selected {
+		return m.Styles.SelectedStyle.Render(cursorMarker + " " + text)
+	}
+	return "  " + text
+}
+
+// scrollToCursor scrolls the viewport so that the row under the cursor is visible.
+func (m *Model) scrollToCursor() {
+	line := -1
+	for i, l := range strings.Split(m.content, "\n") {
+		if strings.Contains(l, cursorMarker) {
+			line = i
+			break
+		}
+	}
+
+	switch {
+	case line < 0:
+		return
+	case line < m.Viewport.YOffset:
+		m.Viewport.SetYOffset(line)
+	case line >= m.Viewport.YOffset+m.Viewport.Height:
+		m.Viewport.SetYOffset(line - m.Viewport.Height + 1)
+	}
+}
+
+// breakdownHint shows the keys to move through the spending breakdown.
+func (m *Model) breakdownHint() string {
+	var hints []string
+	for _, b := range []key.Binding{m.keys.up, m.keys.down, m.keys.collapse, m.keys.expand, m.keys.selectCategory} {
+		hints = append(hints, b.Help().Key+" "+b.Help().Desc)
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#888888")).
+		Render(strings.Join(hints, " • "))
+}
@@ -0,0 +1,171 @@
+package overview
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Rhymond/go-money"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	lm "github.com/icco/lunchmoney"
+)
+
+// Comparison is the period the overview compares the current period with.
+type Comparison int
+
+const (
+	// NoComparison shows the current period only.
+	NoComparison Comparison = iota
+	// PreviousPeriod compares with the period before the current one.
+	PreviousPeriod
+	// SamePeriodLastYear compares with the same period a year earlier.
+	SamePeriodLastYear
+)
+
+func (c Comparison) String() string {
+	switch c {
+	case NoComparison:
+		return "off"
+	case PreviousPeriod:
+		return "vs previous period"
+	case SamePeriodLastYear:
+		return "vs same period last year"
+	}
+	return "unknown"
+}
+
+// next returns the comparison after c, back to none after the last one.
+func (c Comparison) next() Comparison {
+	return (c + 1) % (SamePeriodLastYear + 1)
+}
+
+// ComparisonChangedMsg is sent when the user switches the comparison, so that the
+// transactions of the comparison period can be loaded.
+type ComparisonChangedMsg struct {
+	Comparison Comparison
+}
+
+// Comparison returns the period the overview compares the current period with.
+func (m *Model) Comparison() Comparison {
+	return m.comparison
+}
+
+// SetComparison sets the period to compare with, dropping the transactions of the
+// previous comparison.
+func (m *Model) SetComparison(c Comparison) {
+	m.comparison = c
+	m.comparisonSummary = nil
+	m.comparisonTransactions = nil
+	m.UpdateViewport()
+}
+
+// SetComparisonTransactions sets the transactions of the period the current period is
+// compared with.
+func (m *Model) SetComparisonTransactions(transactions []*lm.Transaction) {
+	m.comparisonTransactions = transactions
+	m.updateComparisonSummary()
+	m.UpdateViewport()
+}
+
+// switchComparison moves to the next comparison and asks for its transactions.
+func (m *Model) switchComparison() tea.Cmd {
+	m.SetComparison(m.comparison.next())
+	c := m.comparison
+	return func() tea.Msg {
+		return ComparisonChangedMsg{Comparison: c}
+	}
+}
+
+// comparing reports whether the transactions of the comparison period are loaded.
+func (m *Model) comparing() bool {
+	return m.comparison != NoComparison && m.comparisonSummary != nil
+}
+
+func (m *Model) updateComparisonSummary() {
+	if m.comparison == NoComparison {
+		return
+	}
+	summary := m.summarize(m.comparisonTransactions)
+	m.comparisonSummary = &summary
+}
+
+// comparisonHint shows the key to switch the comparison and the current one.
+func (m *Model) comparisonHint() string {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#888888")).
+		Render(fmt.Sprintf("%s compare: %s", m.keys.compare.Help().Key, m.comparison))
+}
+
+// metricChange shows how a hero metric changed from the comparison period, in the income
+// style when it improved and the spent style when it got worse.
+func (m *Model) metricChange(current, previous *money.Money, improved bool) string {
+	if !m.comparing() {
+		return ""
+	}
+	delta, err := current.Subtract(previous)
+	if err != nil {
+		return ""
+	}
+
+	text := fmt.Sprintf("%s %s", changeArrow(delta.Amount()), signedDisplay(delta))
+	if !previous.IsZero() {
+		percent := float64(delta.Amount()) / math.Abs(float64(previous.Amount())) * percentageMultiplier
+		text += fmt.Sprintf(" (%+.1f%%)", percent)
+	}
+	return m.changeStyle(delta.IsZero() || improved).Render(text)
+}
+
+// savingsRateChange shows how the savings rate changed from the comparison period, in
+// percentage points.
+func (m *Model) savingsRateChange() string {
+	if !m.comparing() {
+		return ""
+	}
+	delta := m.summary.savingsRate - m.comparisonSummary.savingsRate
+	text := fmt.Sprintf("%s %+.1f pts", changeArrow(int64(math.Round(delta*percentageMultiplier))), delta)
+	return m.changeStyle(delta >= 0).Render(text)
+}
+
+// spendingChange shows how the spending of a category or group changed from the
+// comparison period, or nothing when not comparing.
+func (m *Model) spendingChange(data *spendingData, current, previous *money.Money) string {
+	const width = 8
+	if data.previous == nil {
+		return ""
+	}
+
+	if previous == nil || previous.IsZero() {
+		return " " + m.Styles.SpentStyle.Render(fmt.Sprintf("%*s", width, "new"))
+	}
+	delta := current.Amount() - previous.Amount()
+	percent := math.Abs(float64(delta)) / float64(previous.Amount()) * percentageMultiplier
+	text := fmt.Sprintf("%s %.0f%%", changeArrow(delta), percent)
+	// spending less is an improvement
+	return " " + m.changeStyle(delta <= 0).Render(fmt.Sprintf("%*s", width, text))
+}
+
+func (m *Model) changeStyle(improved bool) lipgloss.Style {
+	if improved {
+		return m.Styles.IncomeStyle
+	}
+	return m.Styles.SpentStyle
+}
+
+// changeArrow points up for an increase and down for a decrease.
+func changeArrow(delta int64) string {
+	switch {
+	case delta > 0:
+		return "▲"
+	case delta < 0:
+		return "▼"
+	}
+	return "="
+}
+
+// signedDisplay formats an amount with its sign, also for positive amounts.
+func signedDisplay(amount *money.Money) string {
+	if amount.IsPositive() {
+		return "+" + amount.Display()
+	}
+	return amount.Display()
+}
@@ -2,12 +2,16 @@ package overview
 
 import (
 	"fmt"
+	"math"
 	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/Rhymond/go-money"
+	"github.com/Rshep3087/lunchtui/liability"
+	"github.com/Rshep3087/lunchtui/totals"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -24,13 +28,24 @@ const (
 	narrowViewportThreshold = 100
 	defaultViewportHeight   = 20
 	maxLen                  = 40
+	// sparklineWidth is how many net worth snapshots the trend shows
+	sparklineWidth    = 30
+	minTrendSnapshots = 2
 )
 
+// breakdownBoxStyle frames the spending breakdowns.
+var breakdownBoxStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#444444")).
+	Padding(0, 1)
+
 // Config holds the configuration for the overview model.
 type Config struct {
 	ShowUserInfo bool
 	// Colors can be provided to customize the theme
 	Colors *Colors
+	// Liabilities classifies the accounts subtracted from the net worth
+	Liabilities liability.Classifier
 }
 
 // Colors represents theme colors for the overview.
@@ -59,49 +74,96 @@ type Model struct {
 	currency           string
 	titleCaser         cases.Caser
 	user               *lm.User
+	// netWorthHistory is the net worth of each snapshot in the local history, oldest first
+	netWorthHistory []float64
+	keys            keyMap
+	// comparison is the period the current period is compared with
+	comparison             Comparison
+	comparisonTransactions []*lm.Transaction
+	// comparisonSummary is the summary of the comparison period, nil until its
+	// transactions are loaded
+	comparisonSummary *Summary
+	// breakdownRows are the groups and categories of the spending breakdown, as drawn
+	breakdownRows   []breakdownRow
+	breakdownCursor int
+	collapsedGroups map[int64]bool
+	// content is what the viewport shows, kept to find the row under the cursor
+	content string
+}
+
+// AccountDetailsMsg is sent when the user asks for the details of an account.
+type AccountDetailsMsg struct{}
+
+type keyMap struct {
+	compare        key.Binding
+	accounts       key.Binding
+	up             key.Binding
+	down           key.Binding
+	collapse       key.Binding
+	expand         key.Binding
+	selectCategory key.Binding
 }
 
 type spendingData struct {
 	categoryTotals      map[int64]*money.Money
 	groupTotals         map[int64]*money.Money
-	groupCategories     map[int64][]int64 // group_id -> []category_ids
-	groupNames          map[int64]string  // group_id -> group_name
-	ungroupedCategories []int64           // categories with GroupID == 0
-	totalSpending       *money.Money      // total spending for percentage calculations
+	groupCategories     map[int64][]int64    // group_id -> []category_ids
+	groupNames          map[int64]string     // group_id -> group_name
+	ungroupedCategories []int64              // categories with GroupID == 0
+	totalSpending       *totals.Sum          // total spending for percentage calculations
+	tagTotals           map[int]*money.Money // tag_id -> spending with the tag
+	tagNames            map[int]string       // tag_id -> tag_name
+	previous            *spendingData        // spending in the comparison period, nil when not comparing
 }
 
 func (m *Model) CalculateSpendingBreakdown() *tree.Tree {
+	return m.spendingBreakdownTree(m.currentSpendingData())
+}
+
+// currentSpendingData collects the spending of the current period, along with the
+// spending of the comparison period when it is loaded.
+func (m *Model) currentSpendingData() *spendingData {
+	data := m.collectSpendingData(m.transactions)
+	if m.comparing() {
+		data.previous = m.collectSpendingData(m.comparisonTransactions)
+	}
+	return data
+}
+
+func (m *Model) spendingBreakdownTree(data *spendingData) *tree.Tree {
+	m.breakdownRows = nil
 	spendingTree := tree.New()
 	spendingTree.Enumerator(tree.RoundedEnumerator)
 	spendingTree.Root("Categories")
 
-	data := m.collectSpendingData()
 	m.addUngroupedCategoriesToTree(spendingTree, data)
 	m.addGroupedCategoriesToTree(spendingTree, data)
 
 	return spendingTree
 }
 
-func (m *Model) collectSpendingData() *spendingData {
+func (m *Model) collectSpendingData(transactions []*lm.Transaction) *spendingData {
 	data := &spendingData{
 		categoryTotals:      make(map[int64]*money.Money),
 		groupTotals:         make(map[int64]*money.Money),
 		groupCategories:     make(map[int64][]int64),
 		groupNames:          make(map[int64]string),
 		ungroupedCategories: make([]int64, 0),
-		totalSpending:       money.New(0, m.currency),
+		totalSpending:       totals.New(m.baseCurrency()),
+		tagTotals:           make(map[int]*money.Money),
+		tagNames:            make(map[int]string),
 	}
 
 	// First pass: collect group information
 	for _, category := range m.categories {
 		if category.IsGroup {
 			data.groupNames[category.ID] = category.Name
-			data.groupTotals[category.ID] = money.New(0, m.currency)
+			data.groupTotals[category.ID] = money.New(0, m.baseCurrency())
 		}
 	}
 
 	// Second pass: calculate totals and organize categories
-	for _, t := range m.transactions {
+	for _, t := range transactions {
 		m.processTransaction(t, data)
 	}
 
@@ -119,10 +181,11 @@ func (m *Model) processTransaction(t *lm.Transaction, data *spendingData) {
 		return
 	}
 
-	amount = amount.Absolute()
-
-	// Track total spending for percentage calculations
-	data.totalSpending, _ = data.totalSpending.Add(amount)
+	// Track total spending for percentage calculations, in the primary currency
+	amount, ok := data.totalSpending.Add(amount.Absolute(), math.Abs(t.ToBase))
+	if !ok {
+		return
+	}
 
 	// Initialize category total if not exists
 	if _, exists := data.categoryTotals[category.ID]; !exists {
@@ -133,6 +196,7 @@ func (m *Model) processTransaction(t *lm.Transaction, data *spendingData) {
 	if data.categoryTotals[category.ID] != nil {
 		data.categoryTotals[category.ID], _ = data.categoryTotals[category.ID].Add(amount)
 	}
+	data.addTags(t, amount)
 
 	// Organize by group
 	if category.GroupID == 0 {
@@ -169,8 +233,8 @@ func (m *Model) addUngroupedCategoriesToTree(spendingTree *tree.Tree, data *spen
 		category := m.categories[categoryID]
 		total := data.categoryTotals[categoryID]
 		if category != nil && total != nil && total.Amount() > 0 {
-			percentage := formatPercentage(total, data.totalSpending)
-			bar := m.renderGroupBarChart(total, data.totalSpending, barMaxWidth)
+			percentage := formatPercentage(total, data.totalSpending.Total())
+			bar := m.renderGroupBarChart(total, data.totalSpending.Total(), barMaxWidth)
 			categoryName := truncateString(category.Name, maxLen)
 
 			// Pad the bar to fixed width so amounts and percentages align
@@ -181,7 +245,8 @@ func (m *Model) addUngroupedCategoriesToTree(spendingTree *tree.Tree, data *spen
 				total.Display(),
 				percentage,
 			)
-			spendingTree.Child(categoryText)
+			row := breakdownRow{categoryID: categoryID, name: category.Name}
+			spendingTree.Child(m.breakdownRowText(row, categoryText) + m.categorySpendingChange(data, categoryID))
 		}
 	}
 }
@@ -199,16 +264,25 @@ func (m *Model) addGroupedCategoriesToTree(spendingTree *tree.Tree, data *spendi
 			continue
 		}
 
-		groupPercentage := formatPercentage(groupTotal, data.totalSpending)
-		groupBar := m.renderGroupBarChart(groupTotal, data.totalSpending, barMaxWidth)
+		groupPercentage := formatPercentage(groupTotal, data.totalSpending.Total())
+		groupBar := m.renderGroupBarChart(groupTotal, data.totalSpending.Total(), barMaxWidth)
+		collapsed := m.collapsedGroups[groupID]
 		groupNameWithPrefix := "▼ " + groupName
+		if collapsed {
+			groupNameWithPrefix = "▶ " + groupName
+		}
 		groupNameTruncated := truncateString(groupNameWithPrefix, maxLen)
 
 		// Pad the bar to fixed width so amounts and percentages align
 		paddedGroupBar := fmt.Sprintf("%-*s", barMaxWidth, groupBar)
 		groupText := fmt.Sprintf("%-40s %s %15s %8s",
 			groupNameTruncated, paddedGroupBar, groupTotal.Display(), groupPercentage)
-		groupTree := tree.New().Root(groupText)
+		groupRow := breakdownRow{categoryID: groupID, name: groupName, group: true}
+		groupTree := tree.New().Root(m.breakdownRowText(groupRow, groupText) + m.groupSpendingChange(data, groupID))
+		if collapsed {
+			spendingTree.Child(groupTree)
+			continue
+		}
 
 		m.sortCategoriesByTotal(categoriesInGroup, data.categoryTotals)
 		for _, categoryID := range categoriesInGroup {
@@ -224,13 +298,30 @@ func (m *Model) addGroupedCategoriesToTree(spendingTree *tree.Tree, data *spendi
 				paddedCatBar := fmt.Sprintf("%-*s", barMaxWidth, catBar)
 				categoryText := fmt.Sprintf("%-40s %s %15s %8s",
 					categoryNameTruncated, paddedCatBar, total.Display(), catPercentage)
-				groupTree.Child(categoryText)
+				row := breakdownRow{categoryID: categoryID, name: category.Name}
+				groupTree.Child(m.breakdownRowText(row, categoryText) + m.categorySpendingChange(data, categoryID))
 			}
 		}
 		spendingTree.Child(groupTree)
 	}
 }
 
+// categorySpendingChange is the change column of a category in the spending breakdown.
+func (m *Model) categorySpendingChange(data *spendingData, categoryID int64) string {
+	if data.previous == nil {
+		return ""
+	}
+	return m.spendingChange(data, data.categoryTotals[categoryID], data.previous.categoryTotals[categoryID])
+}
+
+// groupSpendingChange is the change column of a category group in the spending breakdown.
+func (m *Model) groupSpendingChange(data *spendingData, groupID int64) string {
+	if data.previous == nil {
+		return ""
+	}
+	return m.spendingChange(data, data.groupTotals[groupID], data.previous.groupTotals[groupID])
+}
+
 func (m *Model) getSortedGroupIDs(data *spendingData) []int64 {
 	var sortedGroupIDs []int64
 	for groupID := range data.groupTotals {
@@ -301,53 +392,42 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-elipseLen] + "..."
 }
 
-func (m *Model) calculateNetWorth() *money.Money {
+// calculateNetWorth adds up the balances of the accounts in the primary currency, with
+// liabilities subtracted.
+func (m *Model) calculateNetWorth() *totals.Sum {
 	if m.currency == "" {
-		return money.New(0, "USD")
+		return totals.New("USD")
 	}
 
-	netWorth := money.New(0, m.currency)
-	netWorth = m.calculateAssetsNetWorth(netWorth, m.assets)
-	netWorth = m.calculateAccountsNetWorth(netWorth, m.plaidAccounts)
-
-	return netWorth
-}
-
-// calculateAssetsNetWorth calculates the net worth for assets.
-func (m *Model) calculateAssetsNetWorth(netWorth *money.Money, assets map[int64]*lm.Asset) *money.Money {
-	for _, asset := range assets {
-		amount := money.NewFromFloat(asset.ToBase, m.currency)
-		netWorth = m.updateNetWorth(netWorth, amount, asset.TypeName, asset.SubtypeName)
+	netWorth := totals.New(m.currency)
+	for _, asset := range m.assets {
+		balance, err := asset.ParsedAmount()
+		if err != nil {
+			log.Debug("parsing asset balance", "error", err)
+			continue
+		}
+		m.addToNetWorth(netWorth, balance, asset.ToBase, liability.FromAsset(asset))
 	}
-	return netWorth
-}
-
-// calculateAccountsNetWorth calculates the net worth for plaid accounts.
-func (m *Model) calculateAccountsNetWorth(netWorth *money.Money, accounts map[int64]*lm.PlaidAccount) *money.Money {
-	for _, account := range accounts {
-		amount := money.NewFromFloat(account.ToBase, m.currency)
-		netWorth = m.updateNetWorth(netWorth, amount, account.Type, account.Subtype)
+	for _, account := range m.plaidAccounts {
+		balance, err := account.ParsedAmount()
+		if err != nil {
+			log.Debug("parsing plaid account balance", "error", err)
+			continue
+		}
+		m.addToNetWorth(netWorth, balance, account.ToBase, liability.FromPlaidAccount(account))
 	}
+
 	return netWorth
 }
 
-// updateNetWorth updates net worth based on the type and subtype of the asset or account.
-func (m *Model) updateNetWorth(netWorth, amount *money.Money, assetType, subtype string) *money.Money {
-	var nwa *money.Money
-	var err error
-
-	if assetType == "credit" && subtype == "credit card" {
-		nwa, err = netWorth.Subtract(amount)
-	} else {
-		nwa, err = netWorth.Add(amount)
+// addToNetWorth adds the balance of an asset to the net worth, or subtracts it for a liability.
+func (m *Model) addToNetWorth(netWorth *totals.Sum, balance *money.Money, toBase float64, account liability.Account) {
+	if m.cfg.Liabilities.IsLiability(account) {
+		balance = balance.Negative()
 	}
-
-	if err != nil {
-		log.Debug("updating net worth", "error", err)
-		return netWorth
+	if _, ok := netWorth.Add(balance, toBase); !ok {
+		log.Debug("converting account balance", "account", account.ID, "currency", balance.Currency().Code)
 	}
-
-	return nwa
 }
 
 type Summary struct {
@@ -355,6 +435,9 @@ type Summary struct {
 	totalSpent        money.Money
 	netIncome         money.Money
 	savingsRate       float64
+	// income and spent keep the subtotal of each currency
+	income *totals.Sum
+	spent  *totals.Sum
 }
 
 type TransactionMetrics struct {
@@ -371,6 +454,8 @@ type Styles struct {
 	AssetTypeStyle lipgloss.Style
 	AccountStyle   lipgloss.Style
 	SummaryStyle   lipgloss.Style
+	// SelectedStyle highlights the row under the cursor in the spending breakdown
+	SelectedStyle lipgloss.Style
 	// SectionHeaderStyle is used for section headers in the overview
 	SectionHeaderStyle lipgloss.Style
 }
@@ -384,6 +469,7 @@ func defaultStyles() Styles {
 		AssetTypeStyle:     lipgloss.NewStyle().Foreground(lipgloss.Color("#bbbbbb")),
 		AccountStyle:       lipgloss.NewStyle().Foreground(lipgloss.Color("#d29b1d")),
 		SummaryStyle:       lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1),
+		SelectedStyle:      lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#d29b1d")),
 		SectionHeaderStyle: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00ffff")),
 	}
 }
@@ -397,6 +483,7 @@ func stylesFromColors(colors Colors) Styles {
 		AssetTypeStyle:     lipgloss.NewStyle().Foreground(colors.AssetType),
 		AccountStyle:       lipgloss.NewStyle().Foreground(colors.Account),
 		SummaryStyle:       lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1),
+		SelectedStyle:      lipgloss.NewStyle().Bold(true).Foreground(colors.Account),
 		SectionHeaderStyle: lipgloss.NewStyle().Bold(true).Foreground(colors.SectionHeader),
 	}
 }
@@ -411,6 +498,7 @@ func (m *Model) SetTransactions(transactions []*lm.Transaction) {
 func (m *Model) SetCategories(categories map[int64]*lm.Category) {
 	m.categories = categories
 	m.updateSummary()
+	m.updateComparisonSummary()
 	m.UpdateViewport()
 }
 
@@ -421,6 +509,14 @@ func (m *Model) SetAccounts(assets map[int64]*lm.Asset, plaidAccounts map[int64]
 	m.UpdateViewport()
 }
 
+// baseCurrency returns the primary currency totals are shown in, USD until it is set.
+func (m *Model) baseCurrency() string {
+	if m.currency == "" {
+		return "USD"
+	}
+	return m.currency
+}
+
 func (m *Model) SetCurrency(currency string) {
 	m.currency = currency
 	m.updateAccountTree()
@@ -432,6 +528,13 @@ func (m *Model) SetUser(user *lm.User) {
 	m.UpdateViewport()
 }
 
+// SetNetWorthHistory sets the net worth of the snapshots taken over time, oldest first,
+// which is drawn as a sparkline under the estimated net worth.
+func (m *Model) SetNetWorthHistory(values []float64) {
+	m.netWorthHistory = values
+	m.UpdateViewport()
+}
+
 func New(cfg Config) Model {
 	var styles Styles
 	if cfg.Colors != nil {
@@ -447,6 +550,16 @@ func New(cfg Config) Model {
 		accountTree: tree.New(),
 		titleCaser:  cases.Title(language.English),
 		cfg:         cfg,
+		keys: keyMap{
+			compare:        key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "compare")),
+			accounts:       key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "account details")),
+			up:             key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			down:           key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			collapse:       key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "collapse")),
+			expand:         key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "expand")),
+			selectCategory: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "transactions")),
+		},
+		collapsedGroups: make(map[int64]bool),
 	}
 
 	m.accountTree.Root(m.Styles.TreeRootStyle.Render("Accounts"))
@@ -460,6 +573,19 @@ func New(cfg Config) Model {
 }
 
 func (m *Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if key.Matches(keyMsg, m.keys.compare) {
+			cmd := m.switchComparison()
+			return *m, cmd
+		}
+		if key.Matches(keyMsg, m.keys.accounts) {
+			return *m, func() tea.Msg { return AccountDetailsMsg{} }
+		}
+		if cmd, handled := m.handleBreakdownKey(keyMsg); handled {
+			return *m, cmd
+		}
+	}
+
 	var cmd tea.Cmd
 	m.Viewport, cmd = m.Viewport.Update(msg)
 	return *m, cmd
@@ -523,6 +649,7 @@ func (m *Model) UpdateViewport() {
 		lipgloss.NewStyle().MarginTop(1).Render(mainRow),
 	)
 
+	m.content = finalContent
 	m.Viewport.SetContent(finalContent)
 }
 
@@ -595,8 +722,20 @@ func (m *Model) summaryHeroView() string {
 		return ""
 	}
 
-	incomeBox := m.createMetricBox("INCOME", m.summary.totalIncomeEarned.Display(), m.Styles.IncomeStyle)
-	spentBox := m.createMetricBox("SPENT", m.summary.totalSpent.Display(), m.Styles.SpentStyle)
+	var previous Summary
+	if m.comparing() {
+		previous = *m.comparisonSummary
+	}
+
+	// more income and less spending are improvements, whatever the sign of the amounts
+	income, previousIncome := &m.summary.totalIncomeEarned, &previous.totalIncomeEarned
+	spent, previousSpent := &m.summary.totalSpent, &previous.totalSpent
+	incomeBox := m.createMetricBox("INCOME", income.Display(), m.Styles.IncomeStyle,
+		m.subtotalsLine(m.summary.income),
+		m.metricChange(income, previousIncome, absAmount(income) >= absAmount(previousIncome)))
+	spentBox := m.createMetricBox("SPENT", spent.Display(), m.Styles.SpentStyle,
+		m.subtotalsLine(m.summary.spent),
+		m.metricChange(spent, previousSpent, absAmount(spent) <= absAmount(previousSpent)))
 
 	var netStyle lipgloss.Style
 	if m.summary.netIncome.IsNegative() {
@@ -604,7 +743,9 @@ func (m *Model) summaryHeroView() string {
 	} else {
 		netStyle = m.Styles.IncomeStyle
 	}
-	netBox := m.createMetricBox("NET", m.summary.netIncome.Display(), netStyle)
+	net := &m.summary.netIncome
+	netBox := m.createMetricBox("NET", net.Display(), netStyle, "",
+		m.metricChange(net, &previous.netIncome, net.Amount() >= previous.netIncome.Amount()))
 
 	var savingsStyle lipgloss.Style
 	if m.summary.savingsRate >= 0 {
@@ -612,7 +753,8 @@ func (m *Model) summaryHeroView() string {
 	} else {
 		savingsStyle = m.Styles.SpentStyle
 	}
-	savingsBox := m.createMetricBox("SAVINGS", fmt.Sprintf("%.1f%%", m.summary.savingsRate), savingsStyle)
+	savingsBox := m.createMetricBox("SAVINGS", fmt.Sprintf("%.1f%%", m.summary.savingsRate), savingsStyle, "",
+		m.savingsRateChange())
 
 	var heroRow string
 
@@ -631,18 +773,42 @@ func (m *Model) summaryHeroView() string {
 		)
 	}
 
+	if n := m.summary.income.Unconverted() + m.summary.spent.Unconverted(); n > 0 {
+		heroRow = lipgloss.JoinVertical(lipgloss.Center, heroRow, m.Styles.WarningStyle.Render(
+			fmt.Sprintf("⚠ %d transactions not converted to %s, left out of the totals",
+				n, m.summary.totalSpent.Currency().Code)))
+	}
+	heroRow = lipgloss.JoinVertical(lipgloss.Center, heroRow, m.comparisonHint())
+
 	return lipgloss.NewStyle().
 		Width(m.Viewport.Width).
 		Align(lipgloss.Center).
 		Render(heroRow)
 }
 
-func (m *Model) createMetricBox(label, value string, valueStyle lipgloss.Style) string {
+// subtotalsLine returns the subtotal of each currency of a hero box, or nothing when all
+// amounts are in the primary currency.
+func (m *Model) subtotalsLine(sum *totals.Sum) string {
+	if sum == nil || !sum.MultiCurrency() {
+		return ""
+	}
+	return sum.String()
+}
+
+// createMetricBox renders a hero metric, with the subtotal of each currency and the change
+// from the comparison period below it when there are any.
+func (m *Model) createMetricBox(label, value string, valueStyle lipgloss.Style, subtotals, change string) string {
 	labelStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#888888"))
 
 	// Combine label and value on the same line with a separator
 	content := fmt.Sprintf("%s %s", labelStyle.Render(label+":"), valueStyle.Bold(true).Render(value))
+	if subtotals != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, labelStyle.Render(subtotals))
+	}
+	if change != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, change)
+	}
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -652,7 +818,7 @@ func (m *Model) createMetricBox(label, value string, valueStyle lipgloss.Style)
 	return boxStyle.Render(content)
 }
 
-func (m *Model) buildAccountTreeSection(netWorth *money.Money) string {
+func (m *Model) buildAccountTreeSection(netWorth *totals.Sum) string {
 	return lipgloss.JoinVertical(lipgloss.Top,
 		m.Styles.SectionHeaderStyle.Render("Accounts Overview"),
 		lipgloss.NewStyle().
@@ -664,29 +830,70 @@ func (m *Model) buildAccountTreeSection(netWorth *money.Money) string {
 					lipgloss.NewStyle().MarginBottom(1).Render(m.accountTree.String()),
 					lipgloss.NewStyle().
 						MarginTop(1).
-						Render(fmt.Sprintf("Estimated Net Worth: %s", netWorth.Display())),
+						Render(fmt.Sprintf("Estimated Net Worth: %s", netWorth.Total().Display())),
+					m.currencySubtotalsView(netWorth, "accounts"),
+					m.netWorthTrendView(),
+					lipgloss.NewStyle().
+						Foreground(lipgloss.Color("#888888")).
+						Render(m.keys.accounts.Help().Key+" "+m.keys.accounts.Help().Desc),
 				),
 			),
 	)
 }
 
+// currencySubtotalsView shows the subtotal of each currency when the sum has amounts in
+// more than one, and warns about the amounts that could not be converted to the primary
+// currency. It is empty for a sum in the primary currency only.
+func (m *Model) currencySubtotalsView(sum *totals.Sum, items string) string {
+	var lines []string
+	if sum.MultiCurrency() {
+		lines = append(lines, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#888888")).
+			Render("By currency: "+sum.String()))
+	}
+	if n := sum.Unconverted(); n > 0 {
+		lines = append(lines, m.Styles.WarningStyle.Render(
+			fmt.Sprintf("⚠ %d %s not converted to %s, left out of the total", n, items, sum.Total().Currency().Code)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// netWorthTrendView draws the net worth history as a sparkline, once there are at least
+// two snapshots to compare.
+func (m *Model) netWorthTrendView() string {
+	if len(m.netWorthHistory) < minTrendSnapshots {
+		return ""
+	}
+	return "Trend: " + m.Styles.AccountStyle.Render(Sparkline(m.netWorthHistory, sparklineWidth))
+}
+
 func (m *Model) buildSpendingBreakdownSection() string {
-	spendingTree := m.CalculateSpendingBreakdown()
+	data := m.currentSpendingData()
+	spendingTree := m.spendingBreakdownTree(data)
 	var content string
 	if spendingTree != nil && spendingTree.Children().Length() > 0 {
-		content = spendingTree.String()
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			spendingTree.String(),
+			m.currencySubtotalsView(data.totalSpending, "transactions"),
+			m.breakdownHint(),
+		)
 	} else {
 		content = "No spending data available for this period"
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Top,
-		m.Styles.SectionHeaderStyle.Render("Spending Breakdown"),
-		lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#444444")).
-			Padding(0, 1).
-			Render(content),
-	)
+	header := "Spending Breakdown"
+	if m.comparison != NoComparison {
+		header += " " + m.comparison.String()
+	}
+
+	sections := []string{
+		m.Styles.SectionHeaderStyle.Render(header),
+		breakdownBoxStyle.Render(content),
+	}
+	if len(data.tagTotals) > 0 {
+		sections = append(sections, m.buildTagBreakdownSection(data))
+	}
+	return lipgloss.JoinVertical(lipgloss.Top, sections...)
 }
 
 func (m *Model) updateTransactionMetrics() {
@@ -714,9 +921,14 @@ func (m *Model) updateSummary() {
 		return
 	}
 
-	totalIncomeEarned, totalSpent := money.New(0, m.currency), money.New(0, m.currency)
+	m.summary = m.summarize(m.transactions)
+}
 
-	for _, t := range m.transactions {
+// summarize adds up the income and spending of the transactions.
+func (m *Model) summarize(transactions []*lm.Transaction) Summary {
+	income, spent := totals.New(m.baseCurrency()), totals.New(m.baseCurrency())
+
+	for _, t := range transactions {
 		category := m.categories[t.CategoryID]
 		if category == nil || category.ExcludeFromTotals {
 			continue
@@ -728,25 +940,16 @@ func (m *Model) updateSummary() {
 			continue
 		}
 
+		sum := spent
 		if category.IsIncome {
-			tie, additionError := totalIncomeEarned.Add(amount)
-			if additionError != nil {
-				log.Debug("adding amount to total income earned", "error", additionError)
-				continue
-			}
-
-			totalIncomeEarned = tie
-		} else {
-			tsa, additionError := totalSpent.Add(amount)
-			if additionError != nil {
-				log.Debug("adding amount to total spent", "error", additionError)
-				continue
-			}
-
-			totalSpent = tsa
+			sum = income
+		}
+		if _, ok := sum.Add(amount, t.ToBase); !ok {
+			log.Debug("converting amount", "transaction", t.ID, "currency", amount.Currency().Code)
 		}
 	}
 
+	totalIncomeEarned, totalSpent := income.Total(), spent.Total()
 	netIncome, _ := totalIncomeEarned.Add(totalSpent)
 
 	// Calculate savings rate as (net income / total income) * 100
@@ -755,12 +958,22 @@ func (m *Model) updateSummary() {
 		savingsRate = (float64(netIncome.Amount()) / float64(totalIncomeEarned.Amount())) * percentageMultiplier
 	}
 
-	m.summary = Summary{
+	return Summary{
 		totalIncomeEarned: *totalIncomeEarned,
 		totalSpent:        *totalSpent,
 		netIncome:         *netIncome,
 		savingsRate:       savingsRate,
+		income:            income,
+		spent:             spent,
+	}
+}
+
+// absAmount returns the amount in cents without its sign, zero for an unset amount.
+func absAmount(amount *money.Money) int64 {
+	if amount.Currency() == nil {
+		return 0
 	}
+	return amount.Absolute().Amount()
 }
 
 // accountItem is a helper struct to hold account information for the tree view.
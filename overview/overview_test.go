@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/Rshep3087/lunchtui/liability"
+	tea "github.com/charmbracelet/bubbletea"
 	lm "github.com/icco/lunchmoney"
 )
 
@@ -157,3 +159,216 @@ func TestUpdateAccountTree_OnlyAssets(t *testing.T) {
 		t.Error("Expected tree to contain 'Investment'")
 	}
 }
+
+func TestCalculateNetWorth_Liabilities(t *testing.T) {
+	classifier, err := liability.New([]liability.Override{{Account: "Loan to Sam", Liability: false}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(Config{Liabilities: classifier})
+	m.SetCurrency("USD")
+	m.SetAccounts(
+		map[int64]*lm.Asset{
+			1: {ID: 1, Name: "House", TypeName: "real estate", Balance: "300000", Currency: "usd"},
+			2: {ID: 2, Name: "Loan to Sam", TypeName: "loan", Balance: "500", Currency: "usd"},
+		},
+		map[int64]*lm.PlaidAccount{
+			3: {ID: 3, Name: "Mortgage", Type: "loan", Subtype: "mortgage", Balance: "200000", Currency: "usd"},
+			4: {ID: 4, Name: "Visa", Type: "credit", Subtype: "credit card", Balance: "1000", Currency: "usd"},
+		},
+	)
+
+	if got := m.calculateNetWorth().Total().Display(); got != "$99,500.00" {
+		t.Errorf("calculateNetWorth() = %s, want $99,500.00", got)
+	}
+}
+
+func TestMultiCurrencyTotals(t *testing.T) {
+	m := New(Config{})
+	m.SetSize(200, 40)
+	m.SetCurrency("usd")
+	m.SetCategories(map[int64]*lm.Category{
+		1: {ID: 1, Name: "Groceries"},
+		2: {ID: 2, Name: "Salary", IsIncome: true},
+	})
+	m.SetTransactions([]*lm.Transaction{
+		{ID: 1, CategoryID: 1, Amount: "40.00", Currency: "usd", ToBase: 40},
+		{ID: 2, CategoryID: 1, Amount: "10.00", Currency: "eur", ToBase: 11},
+		{ID: 3, CategoryID: 1, Amount: "5.00", Currency: "gbp"},
+		{ID: 4, CategoryID: 2, Amount: "-100.00", Currency: "usd", ToBase: -100},
+	})
+
+	view := m.View()
+	for _, want := range []string{
+		"SPENT: $51.00",
+		"$40.00 + €10.00 + £5.00",
+		"1 transactions not converted to USD",
+		"By currency: $40.00 + €10.00 + £5.00",
+		"Groceries",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected view to contain %q", want)
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		width    int
+		expected string
+	}{
+		{name: "empty", values: nil, width: 10, expected: ""},
+		{name: "rising", values: []float64{0, 1, 2, 3, 4, 5, 6, 7}, width: 10, expected: "▁▂▃▄▅▆▇█"},
+		{name: "flat", values: []float64{5, 5, 5}, width: 10, expected: "▄▄▄"},
+		{name: "last values", values: []float64{100, 0, 10}, width: 2, expected: "▁█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width); got != tt.expected {
+				t.Errorf("Sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestComparison(t *testing.T) {
+	m := New(Config{})
+	m.SetSize(200, 40)
+	m.SetCurrency("usd")
+	m.SetCategories(map[int64]*lm.Category{
+		1: {ID: 1, Name: "Groceries"},
+		2: {ID: 2, Name: "Dining"},
+		3: {ID: 3, Name: "Rent"},
+	})
+	m.SetTransactions([]*lm.Transaction{
+		{ID: 1, CategoryID: 1, Amount: "50.00", Currency: "usd"},
+		{ID: 2, CategoryID: 2, Amount: "20.00", Currency: "usd"},
+		{ID: 3, CategoryID: 3, Amount: "100.00", Currency: "usd"},
+	})
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if m.Comparison() != PreviousPeriod {
+		t.Fatalf("expected the c key to compare with the previous period, got %s", m.Comparison())
+	}
+	if msg, ok := cmd().(ComparisonChangedMsg); !ok || msg.Comparison != PreviousPeriod {
+		t.Errorf("expected a ComparisonChangedMsg for the previous period, got %#v", msg)
+	}
+	if strings.Contains(m.View(), "▲") {
+		t.Error("expected no changes before the comparison transactions are loaded")
+	}
+
+	m.SetComparisonTransactions([]*lm.Transaction{
+		{ID: 4, CategoryID: 1, Amount: "40.00", Currency: "usd"},
+		{ID: 5, CategoryID: 3, Amount: "200.00", Currency: "usd"},
+	})
+
+	view := m.View()
+	for _, want := range []string{
+		"c compare: vs previous period",
+		"Spending Breakdown vs previous period",
+		"▼ -$70.00 (-29.2%)",
+		"▲ 25%",
+		"new",
+		"▼ 50%",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected view to contain %q", want)
+		}
+	}
+
+	m.SetComparison(NoComparison)
+	if strings.Contains(m.View(), "▼ 50%") {
+		t.Error("expected no changes once the comparison is off")
+	}
+}
+
+func TestBreakdownNavigation(t *testing.T) {
+	m := New(Config{})
+	m.SetSize(200, 40)
+	m.SetCurrency("usd")
+	m.SetCategories(map[int64]*lm.Category{
+		1:  {ID: 1, Name: "Rent"},
+		10: {ID: 10, Name: "Food", IsGroup: true},
+		11: {ID: 11, Name: "Groceries", GroupID: 10},
+		12: {ID: 12, Name: "Dining", GroupID: 10},
+	})
+	m.SetTransactions([]*lm.Transaction{
+		{ID: 1, CategoryID: 1, Amount: "100.00", Currency: "usd"},
+		{ID: 2, CategoryID: 11, Amount: "50.00", Currency: "usd"},
+		{ID: 3, CategoryID: 12, Amount: "20.00", Currency: "usd"},
+	})
+
+	press := func(k tea.KeyMsg) tea.Cmd {
+		var cmd tea.Cmd
+		m, cmd = m.Update(k)
+		return cmd
+	}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	if row, _ := m.cursorRow(); row.name != "Rent" {
+		t.Fatalf("expected the cursor to start on Rent, got %q", row.name)
+	}
+
+	press(down)
+	press(down)
+	cmd := press(enter)
+	msg, ok := cmd().(CategorySelectedMsg)
+	if !ok || msg.CategoryID != 11 || msg.Name != "Groceries" {
+		t.Errorf("expected a CategorySelectedMsg for Groceries, got %#v", msg)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	if row, _ := m.cursorRow(); !row.group || row.name != "Food" {
+		t.Errorf("expected collapsing to move the cursor to the Food group, got %q", row.name)
+	}
+	view := m.View()
+	if !strings.Contains(view, "▶ Food") || strings.Contains(view, "Groceries") {
+		t.Error("expected the Food group to be collapsed")
+	}
+	if len(m.breakdownRows) != 2 {
+		t.Errorf("expected 2 rows with the group collapsed, got %d", len(m.breakdownRows))
+	}
+
+	if cmd := press(enter); cmd != nil {
+		t.Error("expected enter on a group to not select a category")
+	}
+	if !strings.Contains(m.View(), "▼ Food") || !strings.Contains(m.View(), "Groceries") {
+		t.Error("expected enter to expand the Food group")
+	}
+}
+
+func TestTagBreakdown(t *testing.T) {
+	m := New(Config{})
+	m.SetSize(200, 60)
+	m.SetCurrency("usd")
+	m.SetCategories(map[int64]*lm.Category{
+		1: {ID: 1, Name: "Groceries"},
+		2: {ID: 2, Name: "Salary", IsIncome: true},
+	})
+	m.SetTransactions([]*lm.Transaction{
+		{ID: 1, CategoryID: 1, Amount: "60.00", Currency: "usd", Tags: []lm.Tag{{ID: 7, Name: "vacation"}}},
+		{ID: 2, CategoryID: 1, Amount: "40.00", Currency: "usd",
+			Tags: []lm.Tag{{ID: 7, Name: "vacation"}, {ID: 8, Name: "shared"}}},
+		{ID: 3, CategoryID: 2, Amount: "-500.00", Currency: "usd", Tags: []lm.Tag{{ID: 9, Name: "work"}}},
+	})
+
+	view := m.View()
+	for _, want := range []string{"Spending by Tag", "vacation", "$100.00", "shared", "$40.00"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected view to contain %q", want)
+		}
+	}
+	if strings.Contains(view, "work") {
+		t.Error("expected tags of income to be left out")
+	}
+
+	m.SetTransactions([]*lm.Transaction{{ID: 1, CategoryID: 1, Amount: "60.00", Currency: "usd"}})
+	if strings.Contains(m.View(), "Spending by Tag") {
+		t.Error("expected no tag breakdown without tagged spending")
+	}
+}
@@ -0,0 +1,37 @@
+package overview
+
+import (
+	"math"
+	"strings"
+)
+
+// sparkTicks are the bars of a sparkline, from lowest to highest.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the last width values as a line of bars scaled between the lowest
+// and highest of them. A flat series is drawn at mid height.
+func Sparkline(values []float64, width int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lowest = math.Min(lowest, v)
+		highest = math.Max(highest, v)
+	}
+
+	var b strings.Builder
+	top := len(sparkTicks) - 1
+	for _, v := range values {
+		tick := top / 2
+		if highest > lowest {
+			tick = int(math.Round((v - lowest) / (highest - lowest) * float64(top)))
+		}
+		b.WriteRune(sparkTicks[tick])
+	}
+	return b.String()
+}
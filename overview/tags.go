@@ -0,0 +1,79 @@
+package overview
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Rhymond/go-money"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/tree"
+	lm "github.com/icco/lunchmoney"
+)
+
+// addTags adds the spending of a transaction, already in the primary currency, to each of
+// its tags. A transaction with several tags counts towards each of them.
+func (data *spendingData) addTags(t *lm.Transaction, amount *money.Money) {
+	for _, tag := range t.Tags {
+		data.tagNames[tag.ID] = tag.Name
+		total := data.tagTotals[tag.ID]
+		if total == nil {
+			total = money.New(0, amount.Currency().Code)
+		}
+		data.tagTotals[tag.ID], _ = total.Add(amount)
+	}
+}
+
+// sortedTagIDs returns the tags with spending, the most spent on first.
+func (data *spendingData) sortedTagIDs() []int {
+	ids := slices.Collect(maps.Keys(data.tagTotals))
+	slices.SortFunc(ids, func(a, b int) int {
+		return cmp.Or(
+			cmp.Compare(data.tagTotals[b].Amount(), data.tagTotals[a].Amount()),
+			strings.Compare(data.tagNames[a], data.tagNames[b]),
+		)
+	})
+	return ids
+}
+
+// tagSpendingChange is the change column of a tag in the tag breakdown.
+func (m *Model) tagSpendingChange(data *spendingData, tagID int) string {
+	if data.previous == nil {
+		return ""
+	}
+	return m.spendingChange(data, data.tagTotals[tagID], data.previous.tagTotals[tagID])
+}
+
+// buildTagBreakdownSection shows the spending with each tag, as a share of all spending.
+func (m *Model) buildTagBreakdownSection(data *spendingData) string {
+	const barMaxWidth = 12
+	total := data.totalSpending.Total()
+
+	tagTree := tree.New().Enumerator(tree.RoundedEnumerator).Root("Tags")
+	for _, id := range data.sortedTagIDs() {
+		amount := data.tagTotals[id]
+		bar := fmt.Sprintf("%-*s", barMaxWidth, m.renderBarChart(amount, total, barMaxWidth))
+		text := fmt.Sprintf("%-40s %s %15s %8s",
+			truncateString(data.tagNames[id], maxLen),
+			bar,
+			amount.Display(),
+			formatPercentage(amount, total),
+		)
+		tagTree.Child(text + m.tagSpendingChange(data, id))
+	}
+
+	header := "Spending by Tag"
+	if m.comparison != NoComparison {
+		header += " " + m.comparison.String()
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		m.Styles.SectionHeaderStyle.Render(header),
+		breakdownBoxStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+			tagTree.String(),
+			"Share of all spending, a transaction counts for each of its tags",
+		)),
+	)
+}
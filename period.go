@@ -1,10 +1,43 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
+const (
+	dateLayout   = "2006-01-02"
+	daysPerWeek  = 7
+	monthsPerQtr = 3
+	monthsPerYr  = 12
+)
+
+// periodTypes are the period types in the order the TUI switches between them. Custom
+// ranges are entered with the range picker instead.
+var periodTypes = []string{
+	weeklyPeriodType,
+	monthlyPeriodType,
+	quarterlyPeriodType,
+	annualPeriodType,
+	yearToDatePeriodType,
+	last30DaysPeriodType,
+	last90DaysPeriodType,
+}
+
+// isPeriodType reports whether t is a period type, including custom.
+func isPeriodType(t string) bool {
+	return t == customPeriodType || slices.Contains(periodTypes, t)
+}
+
+// nextPeriodType returns the period type after t in periodTypes.
+func nextPeriodType(t string) string {
+	i := slices.Index(periodTypes, t)
+	return periodTypes[(i+1)%len(periodTypes)]
+}
+
 type Period struct {
 	start time.Time
 	end   time.Time
@@ -22,17 +55,134 @@ func (p *Period) endDate() string {
 	return p.end.Format("2006-01-02")
 }
 
+// setPeriod sets the period of the given type that contains current. The rolling
+// periods, year to date and the last days, end on current.
 func (p *Period) setPeriod(current time.Time, periodType string) {
+	day := startOfDay(current)
 	switch periodType {
-	case monthlyPeriodType:
-		p.start = time.Date(current.Year(), current.Month(), 1, 0, 0, 0, 0, current.Location())
-		p.end = time.Date(current.Year(), current.Month()+1, 1, 0, 0, 0, 0, current.Location()).Add(-time.Second)
+	case weeklyPeriodType:
+		// weeks start on Monday
+		p.start = day.AddDate(0, 0, -((int(day.Weekday()) + daysPerWeek - 1) % daysPerWeek))
+		p.end = p.start.AddDate(0, 0, daysPerWeek).Add(-time.Second)
+	case quarterlyPeriodType:
+		month := (current.Month()-1)/monthsPerQtr*monthsPerQtr + 1
+		p.start = time.Date(current.Year(), month, 1, 0, 0, 0, 0, current.Location())
+		p.end = p.start.AddDate(0, monthsPerQtr, 0).Add(-time.Second)
 	case annualPeriodType:
 		p.start = time.Date(current.Year(), 1, 1, 0, 0, 0, 0, current.Location())
 		p.end = time.Date(current.Year()+1, 1, 1, 0, 0, 0, 0, current.Location()).Add(-time.Second)
+	case yearToDatePeriodType:
+		p.start = time.Date(current.Year(), 1, 1, 0, 0, 0, 0, current.Location())
+		p.end = day.AddDate(0, 0, 1).Add(-time.Second)
+	case last30DaysPeriodType, last90DaysPeriodType:
+		p.end = day.AddDate(0, 0, 1).Add(-time.Second)
+		p.start = day.AddDate(0, 0, 1-rollingDays(periodType))
 	default:
 		// default to month
 		p.start = time.Date(current.Year(), current.Month(), 1, 0, 0, 0, 0, current.Location())
 		p.end = time.Date(current.Year(), current.Month()+1, 1, 0, 0, 0, 0, current.Location()).Add(-time.Second)
 	}
 }
+
+// rollingDays returns the number of days of a last days period type.
+func rollingDays(periodType string) int {
+	if periodType == last90DaysPeriodType {
+		return 90
+	}
+	return 30
+}
+
+// newCustomPeriod returns the period from the start of the start day to the end of the
+// end day.
+func newCustomPeriod(start, end time.Time) Period {
+	return Period{start: startOfDay(start), end: startOfDay(end).AddDate(0, 0, 1).Add(-time.Second)}
+}
+
+// days returns the number of calendar days in the period. The days are counted between
+// UTC midnights, as a day in the local time zone can be 23 or 25 hours long around DST changes.
+func (p *Period) days() int {
+	const hoursPerDay = 24
+	start := time.Date(p.start.Year(), p.start.Month(), p.start.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(p.end.Year(), p.end.Month(), p.end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours())/hoursPerDay + 1
+}
+
+// shift returns the period moved by n times its length, for stepping through custom ranges.
+func (p *Period) shift(n int) Period {
+	days := p.days() * n
+	return newCustomPeriod(p.start.AddDate(0, 0, days), p.end.AddDate(0, 0, days))
+}
+
+// shiftPeriod moves current, the date a period is set from, by n periods of the type.
+func shiftPeriod(current time.Time, periodType string, n int) time.Time {
+	switch periodType {
+	case weeklyPeriodType:
+		return current.AddDate(0, 0, daysPerWeek*n)
+	case quarterlyPeriodType:
+		return addMonths(current, monthsPerQtr*n)
+	case annualPeriodType, yearToDatePeriodType:
+		return addMonths(current, monthsPerYr*n)
+	case last30DaysPeriodType, last90DaysPeriodType:
+		return current.AddDate(0, 0, rollingDays(periodType)*n)
+	default:
+		return addMonths(current, n)
+	}
+}
+
+// addMonths adds n months to t, keeping the day within the month so that stepping from
+// the 31st does not skip the shorter months.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	first = first.AddDate(0, n, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+// parsePeriod returns the period type and the period selected by the --period, --start and
+// --end flags. An empty period type selects the current month. A start date selects a
+// custom range ending on the end date, or today when there is none.
+func parsePeriod(periodType, start, end string, now time.Time) (string, Period, error) {
+	periodType = strings.ToLower(strings.TrimSpace(periodType))
+	if start == "" && end == "" {
+		if periodType == "" {
+			periodType = monthlyPeriodType
+		}
+		if periodType == customPeriodType {
+			return "", Period{}, fmt.Errorf("a %s period needs --start", customPeriodType)
+		}
+		if !isPeriodType(periodType) {
+			return "", Period{}, fmt.Errorf("invalid period: %s (must be one of %s or %s)",
+				periodType, strings.Join(periodTypes, ", "), customPeriodType)
+		}
+		var p Period
+		p.setPeriod(now, periodType)
+		return periodType, p, nil
+	}
+
+	if start == "" {
+		return "", Period{}, errors.New("--end needs --start")
+	}
+	if periodType != "" && periodType != customPeriodType {
+		return "", Period{}, fmt.Errorf("--start and --end cannot be combined with a %s period", periodType)
+	}
+
+	startDate, err := time.ParseInLocation(dateLayout, start, now.Location())
+	if err != nil {
+		return "", Period{}, fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", start)
+	}
+	endDate := now
+	if end != "" {
+		if endDate, err = time.ParseInLocation(dateLayout, end, now.Location()); err != nil {
+			return "", Period{}, fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", end)
+		}
+	}
+	if endDate.Before(startDate) {
+		return "", Period{}, fmt.Errorf("invalid period: %s is before %s", endDate.Format(dateLayout), start)
+	}
+	return customPeriodType, newCustomPeriod(startDate, endDate), nil
+}
+
+// startOfDay returns midnight of the day of t.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+)
+
+// periodPick holds the values of the range picker form.
+type periodPick struct {
+	periodType string
+	start      string
+	end        string
+}
+
+// periodTypeLabels are the names of the period types in the range picker.
+var periodTypeLabels = map[string]string{
+	weeklyPeriodType:     "Week",
+	monthlyPeriodType:    "Month",
+	quarterlyPeriodType:  "Quarter",
+	annualPeriodType:     "Year",
+	yearToDatePeriodType: "Year to date",
+	last30DaysPeriodType: "Last 30 days",
+	last90DaysPeriodType: "Last 90 days",
+	customPeriodType:     "Custom range",
+}
+
+// showPeriodForm opens the range picker, filled in with the selected period.
+func showPeriodForm(m *model) (tea.Model, tea.Cmd) {
+	current := m.selectedPeriod()
+	pick := &periodPick{
+		periodType: m.periodType,
+		start:      current.startDate(),
+		end:        current.endDate(),
+	}
+
+	m.periodPick = pick
+	m.periodForm = newPeriodForm(pick)
+	m.previousSessionState = m.sessionState
+	m.sessionState = periodPickerState
+	return m, m.periodForm.Init()
+}
+
+func newPeriodForm(pick *periodPick) *huh.Form {
+	options := make([]huh.Option[string], 0, len(periodTypes)+1)
+	for _, t := range append(periodTypes, customPeriodType) {
+		options = append(options, huh.NewOption(periodTypeLabels[t], t))
+	}
+
+	validateDates := func(string) error {
+		_, _, err := parsePeriod(customPeriodType, pick.start, pick.end, time.Now())
+		return err
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Period").Options(options...).Value(&pick.periodType),
+		),
+		huh.NewGroup(
+			huh.NewInput().Title("Start date").Description("YYYY-MM-DD").Value(&pick.start).Validate(validateDates),
+			huh.NewInput().Title("End date").Description("YYYY-MM-DD").Value(&pick.end).Validate(validateDates),
+		).WithHideFunc(func() bool { return pick.periodType != customPeriodType }),
+	).WithShowHelp(true).WithShowErrors(true)
+}
+
+func (m model) handlePeriodFormState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.periodForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.periodForm = f
+	} else {
+		log.Debug("periodForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.periodForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	pick := m.periodPick
+	m.periodPick = nil
+	m.sessionState = m.previousSessionState
+
+	if pick.periodType == customPeriodType {
+		_, period, err := parsePeriod(customPeriodType, pick.start, pick.end, time.Now())
+		if err != nil {
+			// the form validates the dates, so this only happens if they changed since
+			log.Debug("invalid custom period", "start", pick.start, "end", pick.end, "error", err)
+			return m, nil
+		}
+		m.customPeriod = period
+	} else if m.periodType == customPeriodType {
+		m.currentPeriod = m.customPeriod.start
+	}
+	m.periodType = pick.periodType
+
+	return reloadPeriod(&m)
+}
@@ -89,6 +89,41 @@ func TestPeriodSetPeriod(t *testing.T) {
 			expectStart: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
 			expectEnd:   time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC),
 		},
+		{
+			name:        "weekly period starts on monday",
+			current:     time.Date(2024, 3, 17, 10, 30, 0, 0, time.UTC),
+			periodType:  weeklyPeriodType,
+			expectStart: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 3, 17, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:        "quarterly period",
+			current:     time.Date(2024, 5, 20, 10, 30, 0, 0, time.UTC),
+			periodType:  quarterlyPeriodType,
+			expectStart: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:        "year to date",
+			current:     time.Date(2024, 5, 20, 10, 30, 0, 0, time.UTC),
+			periodType:  yearToDatePeriodType,
+			expectStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 5, 20, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:        "last 30 days",
+			current:     time.Date(2024, 5, 20, 10, 30, 0, 0, time.UTC),
+			periodType:  last30DaysPeriodType,
+			expectStart: time.Date(2024, 4, 21, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 5, 20, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:        "last 90 days",
+			current:     time.Date(2024, 5, 20, 10, 30, 0, 0, time.UTC),
+			periodType:  last90DaysPeriodType,
+			expectStart: time.Date(2024, 2, 21, 0, 0, 0, 0, time.UTC),
+			expectEnd:   time.Date(2024, 5, 20, 23, 59, 59, 0, time.UTC),
+		},
 		{
 			name:        "default to monthly",
 			current:     time.Date(2023, 12, 15, 10, 30, 0, 0, time.UTC),
@@ -108,3 +143,173 @@ func TestPeriodSetPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestShiftPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    time.Time
+		periodType string
+		n          int
+		expected   time.Time
+	}{
+		{
+			name:       "next week",
+			current:    time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC),
+			periodType: weeklyPeriodType,
+			n:          1,
+			expected:   time.Date(2024, 3, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "next month from the 31st",
+			current:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			periodType: monthlyPeriodType,
+			n:          1,
+			expected:   time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "previous quarter",
+			current:    time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+			periodType: quarterlyPeriodType,
+			n:          -1,
+			expected:   time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "previous year to date",
+			current:    time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+			periodType: yearToDatePeriodType,
+			n:          -1,
+			expected:   time.Date(2023, 5, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "previous 90 days",
+			current:    time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+			periodType: last90DaysPeriodType,
+			n:          -1,
+			expected:   time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be.Equal(t, tt.expected, shiftPeriod(tt.current, tt.periodType, tt.n))
+		})
+	}
+}
+
+func TestPeriodShift(t *testing.T) {
+	p := newCustomPeriod(
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+	)
+	be.Equal(t, 10, p.days())
+
+	next := p.shift(1)
+	be.Equal(t, "2024-03-11 - 2024-03-20", next.String())
+
+	previous := p.shift(-1)
+	be.Equal(t, "2024-02-20 - 2024-02-29", previous.String())
+}
+
+func TestPeriodShiftAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+
+	// the clocks go forward on 2026-03-08, so the period is an hour short of 10 days
+	p := newCustomPeriod(time.Date(2026, 3, 1, 0, 0, 0, 0, loc), time.Date(2026, 3, 10, 0, 0, 0, 0, loc))
+	be.Equal(t, 10, p.days())
+
+	next := p.shift(1)
+	be.Equal(t, "2026-03-11 - 2026-03-20", next.String())
+	previous := next.shift(-1)
+	be.Equal(t, "2026-03-01 - 2026-03-10", previous.String())
+}
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2024, 5, 20, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		periodType   string
+		start        string
+		end          string
+		expectedType string
+		expected     string
+		expectErr    bool
+	}{
+		{
+			name:         "defaults to the current month",
+			expectedType: monthlyPeriodType,
+			expected:     "2024-05-01 - 2024-05-31",
+		},
+		{
+			name:         "quarter",
+			periodType:   "Quarter",
+			expectedType: quarterlyPeriodType,
+			expected:     "2024-04-01 - 2024-06-30",
+		},
+		{
+			name:         "start and end",
+			start:        "2024-01-15",
+			end:          "2024-02-15",
+			expectedType: customPeriodType,
+			expected:     "2024-01-15 - 2024-02-15",
+		},
+		{
+			name:         "start ends today",
+			periodType:   customPeriodType,
+			start:        "2024-05-01",
+			expectedType: customPeriodType,
+			expected:     "2024-05-01 - 2024-05-20",
+		},
+		{
+			name:       "invalid period type",
+			periodType: "fortnight",
+			expectErr:  true,
+		},
+		{
+			name:       "custom without start",
+			periodType: customPeriodType,
+			expectErr:  true,
+		},
+		{
+			name:      "end without start",
+			end:       "2024-05-01",
+			expectErr: true,
+		},
+		{
+			name:       "start with another period type",
+			periodType: annualPeriodType,
+			start:      "2024-05-01",
+			expectErr:  true,
+		},
+		{
+			name:      "invalid date",
+			start:     "05/01/2024",
+			expectErr: true,
+		},
+		{
+			name:      "end before start",
+			start:     "2024-05-01",
+			end:       "2024-04-01",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periodType, p, err := parsePeriod(tt.periodType, tt.start, tt.end, now)
+			if tt.expectErr {
+				be.Nonzero(t, err)
+				return
+			}
+			be.NilErr(t, err)
+			be.Equal(t, tt.expectedType, periodType)
+			be.Equal(t, tt.expected, p.String())
+		})
+	}
+
+	// a start in the future ends today
+	_, _, err := parsePeriod("", "2024-06-01", "", now)
+	be.Equal(t, "invalid period: 2024-05-20 is before 2024-06-01", err.Error())
+}
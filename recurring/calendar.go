@@ -0,0 +1,298 @@
+package recurring
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/icco/lunchmoney"
+)
+
+const (
+	dateFormat   = "2006-01-02"
+	daysPerWeek  = 7
+	monthsInYear = 12
+	// twiceMonthlyOffset is the number of days between the two bills of a twice a month cadence
+	twiceMonthlyOffset = 15
+	// postedWindow is how many days a transaction can post before or after its due date
+	postedWindow = 3
+)
+
+// Cadence is how often a recurring expense repeats, either every number of days or
+// every number of months.
+type Cadence struct {
+	days   int
+	months int
+	// twiceMonthly bills on the billing day and again 15 days later every month
+	twiceMonthly bool
+}
+
+// ParseCadence parses a Lunch Money cadence such as "monthly" or "every 3 months".
+func ParseCadence(cadence string) (Cadence, error) {
+	switch strings.ToLower(strings.TrimSpace(cadence)) {
+	case "once a week", "weekly":
+		return Cadence{days: daysPerWeek}, nil
+	case "every 2 weeks", "biweekly":
+		return Cadence{days: 2 * daysPerWeek}, nil
+	case "twice a month":
+		return Cadence{months: 1, twiceMonthly: true}, nil
+	case "monthly":
+		return Cadence{months: 1}, nil
+	case "every 2 months":
+		return Cadence{months: 2}, nil
+	case "every 3 months", "quarterly":
+		return Cadence{months: 3}, nil
+	case "every 4 months":
+		return Cadence{months: 4}, nil
+	case "twice a year", "every 6 months":
+		return Cadence{months: monthsInYear / 2}, nil
+	case "yearly", "annually":
+		return Cadence{months: monthsInYear}, nil
+	default:
+		return Cadence{}, fmt.Errorf("unknown cadence %q", cadence)
+	}
+}
+
+// Occurrences returns the dates the recurring expense is due between from and to, inclusive.
+func Occurrences(r *lunchmoney.RecurringExpense, from, to time.Time) ([]time.Time, error) {
+	cadence, err := ParseCadence(r.Cadence)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor, err := billingAnchor(r, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// the expense does not repeat before it starts or after it ends
+	if start, parseErr := time.ParseInLocation(dateFormat, r.StartDate, from.Location()); parseErr == nil &&
+		start.After(from) {
+		from = start
+	}
+	if end, parseErr := time.ParseInLocation(dateFormat, r.EndDate, from.Location()); parseErr == nil &&
+		end.Before(to) {
+		to = end
+	}
+
+	var dates []time.Time
+	switch {
+	case cadence.days > 0:
+		dates = everyDays(anchor, cadence.days, from, to)
+	case cadence.twiceMonthly:
+		dates = append(everyMonths(anchor, 1, from, to),
+			everyMonths(anchor.AddDate(0, 0, twiceMonthlyOffset), 1, from, to)...)
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	default:
+		dates = everyMonths(anchor, cadence.months, from, to)
+	}
+	return dates, nil
+}
+
+// billingAnchor returns a date the expense is billed on. The billing date is either a
+// full date or the day of the month, in which case it is anchored on the start date or
+// the month of from.
+func billingAnchor(r *lunchmoney.RecurringExpense, from time.Time) (time.Time, error) {
+	loc := from.Location()
+	if anchor, err := time.ParseInLocation(dateFormat, r.BillingDate, loc); err == nil {
+		return anchor, nil
+	}
+
+	day, err := strconv.Atoi(strings.TrimSpace(r.BillingDate))
+	if err != nil || day < 1 || day > 31 {
+		if start, startErr := time.ParseInLocation(dateFormat, r.StartDate, loc); startErr == nil {
+			return start, nil
+		}
+		return time.Time{}, errors.New("no billing date")
+	}
+
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, loc)
+	if start, startErr := time.ParseInLocation(dateFormat, r.StartDate, loc); startErr == nil {
+		month = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, loc)
+	}
+	return addMonths(month, 0, day), nil
+}
+
+// everyDays returns the dates every n days from anchor that fall between from and to.
+func everyDays(anchor time.Time, n int, from, to time.Time) []time.Time {
+	// move the anchor to the first date on or after from
+	if anchor.Before(from) {
+		periods := (daysBetween(anchor, from) + n - 1) / n
+		anchor = anchor.AddDate(0, 0, periods*n)
+	} else {
+		periods := daysBetween(from, anchor) / n
+		anchor = anchor.AddDate(0, 0, -periods*n)
+	}
+
+	var dates []time.Time
+	for d := anchor; !d.After(to); d = d.AddDate(0, 0, n) {
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// everyMonths returns the dates every n months from anchor that fall between from and to.
+// Billing days past the end of a month, such as the 31st, fall on its last day.
+func everyMonths(anchor time.Time, n int, from, to time.Time) []time.Time {
+	// start from the period that contains from, counted from the anchor
+	months := (from.Year()-anchor.Year())*monthsInYear + int(from.Month()-anchor.Month())
+	k := months / n
+	if months < 0 && months%n != 0 {
+		k--
+	}
+	k--
+
+	var dates []time.Time
+	for ; ; k++ {
+		d := addMonths(anchor, k*n, anchor.Day())
+		if d.After(to) {
+			return dates
+		}
+		if !d.Before(from) {
+			dates = append(dates, d)
+		}
+	}
+}
+
+// addMonths adds months to the month of t and returns the given day of that month,
+// or its last day when the month is shorter.
+func addMonths(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// daysBetween returns the number of calendar days from a to b. The dates are compared
+// at UTC midnight, as a day in their location can be 23 or 25 hours long around DST changes.
+func daysBetween(a, b time.Time) int {
+	const hoursPerDay = 24
+	utcDate := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return int(utcDate(b).Sub(utcDate(a)).Hours()) / hoursPerDay
+}
+
+// Occurrence is a date a recurring expense is due.
+type Occurrence struct {
+	Expense *lunchmoney.RecurringExpense
+	Date    time.Time
+	Amount  *money.Money
+	// Posted is set when a transaction of the expense was found around the date
+	Posted bool
+}
+
+// Missing reports whether the occurrence was due by today without a transaction posted.
+func (o Occurrence) Missing(today time.Time) bool {
+	return !o.Posted && !o.Date.After(today)
+}
+
+// Project returns every occurrence of the recurring expenses between from and to, ordered
+// by date. Expenses with an unknown cadence or amount are left out.
+func Project(expenses []*lunchmoney.RecurringExpense, from, to time.Time) []Occurrence {
+	var occurrences []Occurrence
+	for _, r := range expenses {
+		amount, err := r.ParsedAmount()
+		if err != nil {
+			continue
+		}
+
+		dates, err := Occurrences(r, from, to)
+		if err != nil {
+			continue
+		}
+
+		for _, d := range dates {
+			occurrences = append(occurrences, Occurrence{Expense: r, Date: d, Amount: amount})
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Date.Before(occurrences[j].Date)
+	})
+	return occurrences
+}
+
+// MatchTransactions marks the occurrences that have a transaction posted within a few days
+// of their date. Transactions match on their recurring expense, or failing that on payee,
+// and each transaction matches one occurrence at most.
+func MatchTransactions(occurrences []Occurrence, ts []*lunchmoney.Transaction) {
+	used := make(map[int64]bool)
+	for i := range occurrences {
+		o := &occurrences[i]
+		for _, t := range ts {
+			if used[t.ID] || !matchesExpense(t, o.Expense) {
+				continue
+			}
+
+			date, err := time.ParseInLocation(dateFormat, t.Date, o.Date.Location())
+			if err != nil {
+				continue
+			}
+
+			if days := daysBetween(o.Date, date); days >= -postedWindow && days <= postedWindow {
+				o.Posted = true
+				used[t.ID] = true
+				break
+			}
+		}
+	}
+}
+
+func matchesExpense(t *lunchmoney.Transaction, r *lunchmoney.RecurringExpense) bool {
+	if t.RecurringID != 0 {
+		return t.RecurringID == r.ID
+	}
+	return strings.EqualFold(t.Payee, r.Payee) ||
+		(r.OriginalName != "" && strings.EqualFold(t.OriginalName, r.OriginalName))
+}
+
+// WeekTotal is the amount due in a week starting on Monday, with a total per currency.
+type WeekTotal struct {
+	Start  time.Time
+	Totals []*money.Money
+}
+
+// String formats the totals of the week, such as "$12.00 + €5.00".
+func (w WeekTotal) String() string {
+	totals := make([]string, len(w.Totals))
+	for i, total := range w.Totals {
+		totals[i] = total.Display()
+	}
+	return strings.Join(totals, " + ")
+}
+
+// WeeklyTotals adds up the occurrences of each week. The occurrences must be ordered by date.
+func WeeklyTotals(occurrences []Occurrence) []WeekTotal {
+	var weeks []WeekTotal
+	for _, o := range occurrences {
+		start := StartOfWeek(o.Date)
+		if len(weeks) == 0 || !weeks[len(weeks)-1].Start.Equal(start) {
+			weeks = append(weeks, WeekTotal{Start: start})
+		}
+
+		week := &weeks[len(weeks)-1]
+		added := false
+		for i, total := range week.Totals {
+			if sum, err := total.Add(o.Amount); err == nil {
+				week.Totals[i] = sum
+				added = true
+				break
+			}
+		}
+		if !added {
+			week.Totals = append(week.Totals, o.Amount)
+		}
+	}
+	return weeks
+}
+
+// StartOfWeek returns the Monday of the week of t.
+func StartOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + daysPerWeek - 1) % daysPerWeek
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,279 @@
+package recurring
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/lunchmoney"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse(dateFormat, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func formatDates(dates []time.Time) []string {
+	formatted := make([]string, len(dates))
+	for i, d := range dates {
+		formatted[i] = d.Format(dateFormat)
+	}
+	return formatted
+}
+
+func TestParseCadence(t *testing.T) {
+	tests := []struct {
+		cadence  string
+		expected Cadence
+		wantErr  bool
+	}{
+		{cadence: "once a week", expected: Cadence{days: 7}},
+		{cadence: "every 2 weeks", expected: Cadence{days: 14}},
+		{cadence: "twice a month", expected: Cadence{months: 1, twiceMonthly: true}},
+		{cadence: "Monthly", expected: Cadence{months: 1}},
+		{cadence: "every 3 months", expected: Cadence{months: 3}},
+		{cadence: "twice a year", expected: Cadence{months: 6}},
+		{cadence: "yearly", expected: Cadence{months: 12}},
+		{cadence: "every full moon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cadence, func(t *testing.T) {
+			cadence, err := ParseCadence(tt.cadence)
+			if tt.wantErr {
+				be.Nonzero(t, err)
+				return
+			}
+			be.NilErr(t, err)
+			be.Equal(t, tt.expected, cadence)
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name     string
+		expense  *lunchmoney.RecurringExpense
+		from, to string
+		expected []string
+	}{
+		{
+			name:     "monthly on a billing date",
+			expense:  &lunchmoney.RecurringExpense{Cadence: "monthly", BillingDate: "2024-01-15"},
+			from:     "2025-01-01",
+			to:       "2025-03-31",
+			expected: []string{"2025-01-15", "2025-02-15", "2025-03-15"},
+		},
+		{
+			name:     "monthly on a billing day",
+			expense:  &lunchmoney.RecurringExpense{Cadence: "monthly", BillingDate: "5"},
+			from:     "2025-01-10",
+			to:       "2025-03-10",
+			expected: []string{"2025-02-05", "2025-03-05"},
+		},
+		{
+			name:     "end of month",
+			expense:  &lunchmoney.RecurringExpense{Cadence: "monthly", BillingDate: "2024-12-31"},
+			from:     "2025-01-01",
+			to:       "2025-03-31",
+			expected: []string{"2025-01-31", "2025-02-28", "2025-03-31"},
+		},
+		{
+			name:     "weekly",
+			expense:  &lunchmoney.RecurringExpense{Cadence: "once a week", BillingDate: "2025-01-01"},
+			from:     "2025-01-10",
+			to:       "2025-01-31",
+			expected: []string{"2025-01-15", "2025-01-22", "2025-01-29"},
+		},
+		{
+			name:     "weekly before the billing date",
+			expense:  &lunchmoney.RecurringExpense{Cadence: "every 2 weeks", BillingDate: "2025-02-12"},
+			from:     "2025-01-01",
+			to:       "2025-01-31",
+			expected: []string{"2025-01-01", "2025-01-15", "2025-01-29"},
+		},
+		{
+			name:     "twice a month",
+			expense:  &lunchmoney.RecurringExpense{Cadence: "twice a month", BillingDate: "2024-06-01"},
+			from:     "2025-01-01",
+			to:       "2025-02-10",
+			expected: []string{"2025-01-01", "2025-01-16", "2025-02-01"},
+		},
+		{
+			name:     "quarterly",
+			expense:  &lunchmoney.RecurringExpense{Cadence: "every 3 months", BillingDate: "2024-11-20"},
+			from:     "2025-01-01",
+			to:       "2025-12-31",
+			expected: []string{"2025-02-20", "2025-05-20", "2025-08-20", "2025-11-20"},
+		},
+		{
+			name: "ends",
+			expense: &lunchmoney.RecurringExpense{
+				Cadence: "monthly", BillingDate: "2024-01-15", EndDate: "2025-02-01",
+			},
+			from:     "2025-01-01",
+			to:       "2025-03-31",
+			expected: []string{"2025-01-15"},
+		},
+		{
+			name: "starts",
+			expense: &lunchmoney.RecurringExpense{
+				Cadence: "yearly", BillingDate: "2024-03-01", StartDate: "2026-03-01",
+			},
+			from:     "2025-01-01",
+			to:       "2027-12-31",
+			expected: []string{"2026-03-01", "2027-03-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dates, err := Occurrences(tt.expense, date(tt.from), date(tt.to))
+			be.NilErr(t, err)
+			be.AllEqual(t, tt.expected, formatDates(dates))
+		})
+	}
+}
+
+func TestOccurrencesAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	inNewYork := func(s string) time.Time {
+		d, parseErr := time.ParseInLocation(dateFormat, s, loc)
+		be.NilErr(t, parseErr)
+		return d
+	}
+
+	// the clocks go forward on 2026-03-08, so the fortnights around it are an hour short
+	tests := []struct {
+		name        string
+		billingDate string
+		from, to    string
+		expected    []string
+	}{
+		{
+			name:        "anchor before from",
+			billingDate: "2026-03-01",
+			from:        "2026-03-16",
+			to:          "2026-04-15",
+			expected:    []string{"2026-03-29", "2026-04-12"},
+		},
+		{
+			name:        "anchor after from",
+			billingDate: "2026-03-15",
+			from:        "2026-03-01",
+			to:          "2026-03-20",
+			expected:    []string{"2026-03-01", "2026-03-15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expense := &lunchmoney.RecurringExpense{Cadence: "every 2 weeks", BillingDate: tt.billingDate}
+			dates, err := Occurrences(expense, inNewYork(tt.from), inNewYork(tt.to))
+			be.NilErr(t, err)
+			be.AllEqual(t, tt.expected, formatDates(dates))
+		})
+	}
+}
+
+func TestMatchTransactions(t *testing.T) {
+	netflix := &lunchmoney.RecurringExpense{
+		ID: 1, Payee: "Netflix", Cadence: "monthly", BillingDate: "2025-01-05", Amount: "15.99", Currency: "usd",
+	}
+	rent := &lunchmoney.RecurringExpense{
+		ID: 2, Payee: "Rent", Cadence: "monthly", BillingDate: "2025-01-01", Amount: "1500", Currency: "usd",
+	}
+
+	occurrences := Project([]*lunchmoney.RecurringExpense{netflix, rent}, date("2025-01-01"), date("2025-02-28"))
+	MatchTransactions(occurrences, []*lunchmoney.Transaction{
+		// matched on the recurring expense, two days late
+		{ID: 10, Date: "2025-01-07", Payee: "NETFLIX.COM", RecurringID: 1},
+		// matched on payee
+		{ID: 11, Date: "2025-01-01", Payee: "rent"},
+		// too far from the due date
+		{ID: 12, Date: "2025-02-10", Payee: "Netflix"},
+	})
+
+	posted := make([]string, len(occurrences))
+	for i, o := range occurrences {
+		posted[i] = o.Date.Format(dateFormat) + " " + o.Expense.Payee + " " + map[bool]string{
+			true: "posted", false: "missing",
+		}[o.Posted]
+	}
+	be.AllEqual(t, []string{
+		"2025-01-01 Rent posted",
+		"2025-01-05 Netflix posted",
+		"2025-02-01 Rent missing",
+		"2025-02-05 Netflix missing",
+	}, posted)
+
+	be.True(t, occurrences[2].Missing(date("2025-02-03")))
+	be.False(t, occurrences[3].Missing(date("2025-02-03")))
+}
+
+func TestWeeklyTotals(t *testing.T) {
+	expenses := []*lunchmoney.RecurringExpense{
+		{Payee: "Netflix", Cadence: "monthly", BillingDate: "2025-01-06", Amount: "15.99", Currency: "usd"},
+		{Payee: "Gym", Cadence: "monthly", BillingDate: "2025-01-12", Amount: "40", Currency: "usd"},
+		{Payee: "Spotify", Cadence: "monthly", BillingDate: "2025-01-08", Amount: "9.99", Currency: "eur"},
+		{Payee: "Rent", Cadence: "monthly", BillingDate: "2025-01-15", Amount: "1500", Currency: "usd"},
+	}
+
+	weeks := WeeklyTotals(Project(expenses, date("2025-01-01"), date("2025-01-31")))
+
+	be.Equal(t, 2, len(weeks))
+	be.Equal(t, "2025-01-06", weeks[0].Start.Format(dateFormat))
+	be.Equal(t, "$55.99 + €9.99", weeks[0].String())
+	be.Equal(t, "2025-01-13", weeks[1].Start.Format(dateFormat))
+	be.Equal(t, "$1,500.00", weeks[1].String())
+}
+
+func TestCalendarView(t *testing.T) {
+	model := New(Colors{Primary: "#ff0000", Warning: "#ffff00"})
+	model.now = func() time.Time { return date("2025-01-08") }
+	model.SetSize(100, 20)
+	model.SetRecurringExpenses([]*lunchmoney.RecurringExpense{
+		{ID: 1, Payee: "Netflix", Cadence: "monthly", BillingDate: "2025-01-06", Amount: "15.99", Currency: "usd"},
+		{ID: 2, Payee: "Gym", Cadence: "monthly", BillingDate: "2025-01-07", Amount: "40", Currency: "usd"},
+	})
+	netflix := []*lunchmoney.Transaction{{ID: 1, Date: "2025-01-06", Payee: "Netflix"}}
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	be.Nonzero(t, cmd)
+	msg, ok := cmd().(CalendarShownMsg)
+	be.True(t, ok)
+	// from a few days before the Monday of this week until today
+	be.Equal(t, CalendarShownMsg{Start: "2025-01-03", End: "2025-01-08"}, msg)
+
+	// transactions loaded for the selected period rather than the calendar are ignored
+	model.SetTransactions("2025-01-01", "2025-01-31", netflix)
+	be.In(t, "2 due and not posted", model.View())
+
+	model.SetTransactions(msg.Start, msg.End, netflix)
+	view := model.View()
+
+	be.In(t, "4 bills due in the next 30 days", view)
+	be.In(t, "1 due and not posted", view)
+	be.In(t, "Week of Jan 6", view)
+	be.In(t, "$55.99", view)
+	be.In(t, "posted", view)
+	be.In(t, "not posted", view)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	be.In(t, "next 37 days", model.View())
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	be.True(t, strings.Contains(model.View(), "Billing Day"))
+
+	// the transactions of the calendar are only loaded once
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	be.Zero(t, cmd)
+}
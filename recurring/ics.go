@@ -0,0 +1,87 @@
+package recurring
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	icsDateFormat     = "20060102"
+	icsDateTimeFormat = "20060102T150405Z"
+	// icsLineLength is the maximum length of a calendar line in octets, before folding
+	icsLineLength = 75
+)
+
+// WriteICS writes the occurrences as all day events of an iCalendar file. Each event has
+// a stable UID, so importing an updated file replaces the events instead of duplicating them.
+func WriteICS(w io.Writer, occurrences []Occurrence, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//lunchtui//recurring expenses//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Bills",
+	}
+
+	stamp := now.UTC().Format(icsDateTimeFormat)
+	for _, o := range occurrences {
+		summary := fmt.Sprintf("%s %s", o.Expense.Payee, o.Amount.Display())
+		description := fmt.Sprintf("%s recurring expense", o.Expense.Cadence)
+		if o.Expense.Description != "" {
+			description = o.Expense.Description + "\n" + description
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:recurring-%d-%s@lunchtui", o.Expense.ID, o.Date.Format(icsDateFormat)),
+			"DTSTAMP:"+stamp,
+			"DTSTART;VALUE=DATE:"+o.Date.Format(icsDateFormat),
+			"DTEND;VALUE=DATE:"+o.Date.AddDate(0, 0, 1).Format(icsDateFormat),
+			"SUMMARY:"+escapeICSText(summary),
+			"DESCRIPTION:"+escapeICSText(description),
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := bw.WriteString(foldICSLine(line) + "\r\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// escapeICSText escapes a text value as described in RFC 5545.
+func escapeICSText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// foldICSLine splits lines longer than 75 octets, continuing them on lines that start
+// with a space. Lines are only split between UTF-8 characters.
+func foldICSLine(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > icsLineLength {
+			b.WriteString("\r\n ")
+			// the leading space counts towards the length of the continued line
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
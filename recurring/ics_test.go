@@ -0,0 +1,57 @@
+package recurring
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/icco/lunchmoney"
+)
+
+func TestWriteICS(t *testing.T) {
+	expenses := []*lunchmoney.RecurringExpense{
+		{
+			ID: 7, Payee: "Rent; Flat 2", Description: "Paid to landlord, by transfer", Cadence: "monthly",
+			BillingDate: "2025-01-01", Amount: "1500", Currency: "usd",
+		},
+	}
+
+	var out bytes.Buffer
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	be.NilErr(t, WriteICS(&out, Project(expenses, date("2025-01-15"), date("2025-02-15")), now))
+
+	be.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//lunchtui//recurring expenses//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Bills",
+		"BEGIN:VEVENT",
+		"UID:recurring-7-20250201@lunchtui",
+		"DTSTAMP:20250115T103000Z",
+		"DTSTART;VALUE=DATE:20250201",
+		"DTEND;VALUE=DATE:20250202",
+		`SUMMARY:Rent\; Flat 2 $1\,500.00`,
+		`DESCRIPTION:Paid to landlord\, by transfer\nmonthly recurring expense`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), out.String())
+}
+
+func TestFoldICSLine(t *testing.T) {
+	short := "SUMMARY:Netflix"
+	be.Equal(t, short, foldICSLine(short))
+
+	long := "DESCRIPTION:" + strings.Repeat("é", 50)
+	folded := strings.Split(foldICSLine(long), "\r\n")
+	be.Equal(t, 2, len(folded))
+	for _, line := range folded {
+		be.True(t, len(line) <= icsLineLength)
+	}
+	be.True(t, strings.HasPrefix(folded[1], " "))
+	be.Equal(t, long, folded[0]+strings.TrimPrefix(folded[1], " "))
+}
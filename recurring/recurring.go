@@ -1,6 +1,10 @@
 package recurring
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -13,14 +17,44 @@ const (
 	repeatsWidth     = 10
 	billingDayWidth  = 12
 	amountWidth      = 10
+
+	dueDateWidth = 16
+	statusWidth  = 12
+
+	// DefaultCalendarDays is how many days ahead the calendar projects by default
+	DefaultCalendarDays = 30
+	maxCalendarDays     = 365
 )
 
 type Colors struct {
 	Primary string
+	Warning string
 }
 
 type Model struct {
 	recurringExpenses table.Model
+	// calendar lists the upcoming occurrences of the recurring expenses by week
+	calendar     table.Model
+	showCalendar bool
+	calendarDays int
+	keys         keyMap
+	warningStyle lipgloss.Style
+
+	expenses     []*lunchmoney.RecurringExpense
+	transactions []*lunchmoney.Transaction
+	occurrences  []Occurrence
+	today        time.Time
+
+	// transactionsStart and transactionsEnd are the range the transactions were loaded for
+	transactionsStart, transactionsEnd string
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+type keyMap struct {
+	toggleCalendar key.Binding
+	moreDays       key.Binding
+	fewerDays      key.Binding
 }
 
 func New(colors Colors) Model {
@@ -40,20 +74,46 @@ func New(colors Colors) Model {
 
 	recurringExpenses.SetStyles(tableStyle)
 
-	return Model{recurringExpenses: recurringExpenses}
+	calendar := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Due", Width: dueDateWidth},
+			{Title: "Merchant", Width: merchantWidth},
+			{Title: "Amount", Width: amountWidth},
+			{Title: "Status", Width: statusWidth},
+		}),
+	)
+	calendar.SetStyles(tableStyle)
+
+	return Model{
+		recurringExpenses: recurringExpenses,
+		calendar:          calendar,
+		calendarDays:      DefaultCalendarDays,
+		keys: keyMap{
+			toggleCalendar: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "toggle calendar")),
+			moreDays:       key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "one more week")),
+			fewerDays:      key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "one less week")),
+		},
+		warningStyle: lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Warning)),
+		now:          time.Now,
+	}
 }
 
 func (m *Model) SetFocus(focus bool) {
 	if focus {
 		m.recurringExpenses.Focus()
+		m.calendar.Focus()
 	} else {
 		m.recurringExpenses.Blur()
+		m.calendar.Blur()
 	}
 }
 
 func (m *Model) SetSize(width, height int) {
-	m.recurringExpenses.SetHeight(height)
+	// the tables share their height with the key hints, and the calendar with its summary too
+	m.recurringExpenses.SetHeight(height - 1)
 	m.recurringExpenses.SetWidth(width)
+	m.calendar.SetHeight(height - 2)
+	m.calendar.SetWidth(width)
 }
 
 func (m *Model) SetRecurringExpenses(re []*lunchmoney.RecurringExpense) {
@@ -73,6 +133,110 @@ func (m *Model) SetRecurringExpenses(re []*lunchmoney.RecurringExpense) {
 	}
 
 	m.recurringExpenses.SetRows(rows)
+	m.expenses = re
+	m.updateCalendar()
+}
+
+// CalendarShownMsg is sent when the calendar is shown, so that the transactions of the
+// range returned by TransactionsRange can be loaded.
+type CalendarShownMsg struct {
+	Start, End string
+}
+
+// TransactionsRange returns the dates of the transactions matched against the calendar:
+// from a few days before the start of this week, for the bills that posted early, until
+// today.
+func (m *Model) TransactionsRange() (string, string) {
+	today, from, _ := m.calendarRange()
+	return from.AddDate(0, 0, -postedWindow).Format(dateFormat), today.Format(dateFormat)
+}
+
+// SetTransactions sets the transactions loaded for a range, which are matched against the
+// calendar to find the expenses that have not posted yet. Transactions of another range
+// than the calendar's are ignored.
+func (m *Model) SetTransactions(start, end string, ts []*lunchmoney.Transaction) {
+	if wantStart, wantEnd := m.TransactionsRange(); start != wantStart || end != wantEnd {
+		return
+	}
+	m.transactions = ts
+	m.transactionsStart, m.transactionsEnd = start, end
+	m.updateCalendar()
+}
+
+// showCalendarCmd asks for the transactions of the calendar unless they are loaded.
+func (m *Model) showCalendarCmd() tea.Cmd {
+	start, end := m.TransactionsRange()
+	if m.transactionsStart == start && m.transactionsEnd == end {
+		return nil
+	}
+	return func() tea.Msg {
+		return CalendarShownMsg{Start: start, End: end}
+	}
+}
+
+// calendarRange returns the dates covered by the calendar, from the start of this week
+// until calendarDays from today.
+func (m *Model) calendarRange() (time.Time, time.Time, time.Time) {
+	now := m.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return today, StartOfWeek(today), today.AddDate(0, 0, m.calendarDays)
+}
+
+// updateCalendar projects the occurrences of the recurring expenses into the calendar,
+// with the total due of each week before its occurrences. The calendar is only projected
+// while it is shown.
+func (m *Model) updateCalendar() {
+	if !m.showCalendar {
+		return
+	}
+
+	today, from, to := m.calendarRange()
+	occurrences := Project(m.expenses, from, to)
+	MatchTransactions(occurrences, m.transactions)
+	m.occurrences, m.today = occurrences, today
+
+	weeks := WeeklyTotals(occurrences)
+	rows := make([]table.Row, 0, len(occurrences)+len(weeks))
+	for _, week := range weeks {
+		rows = append(rows, table.Row{"Week of " + week.Start.Format("Jan 2"), "", week.String(), "total"})
+		for _, o := range occurrences {
+			if !StartOfWeek(o.Date).Equal(week.Start) {
+				continue
+			}
+
+			var status string
+			switch {
+			case o.Posted:
+				status = "posted"
+			case o.Missing(today):
+				status = "not posted"
+			}
+			rows = append(rows, table.Row{
+				"  " + o.Date.Format("Mon Jan 2"),
+				o.Expense.Payee,
+				o.Amount.Display(),
+				status,
+			})
+		}
+	}
+
+	m.calendar.SetRows(rows)
+}
+
+// calendarSummary describes the calendar and how many due expenses have not posted.
+func (m *Model) calendarSummary() string {
+	missing := 0
+	for _, o := range m.occurrences {
+		if o.Missing(m.today) {
+			missing++
+		}
+	}
+
+	summary := fmt.Sprintf("%d bills due in the next %d days", len(m.occurrences), m.calendarDays)
+	if missing > 0 {
+		summary += " | " + m.warningStyle.Render(fmt.Sprintf("%d due and not posted", missing))
+	}
+	return summary
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -80,11 +244,45 @@ func (m *Model) Init() tea.Cmd {
 }
 
 func (m *Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.keys.toggleCalendar):
+			m.showCalendar = !m.showCalendar
+			m.updateCalendar()
+			if m.showCalendar {
+				return *m, m.showCalendarCmd()
+			}
+			return *m, nil
+		case m.showCalendar && key.Matches(keyMsg, m.keys.moreDays):
+			m.calendarDays = min(m.calendarDays+daysPerWeek, maxCalendarDays)
+			m.updateCalendar()
+			return *m, nil
+		case m.showCalendar && key.Matches(keyMsg, m.keys.fewerDays):
+			m.calendarDays = max(m.calendarDays-daysPerWeek, daysPerWeek)
+			m.updateCalendar()
+			return *m, nil
+		}
+	}
+
 	var cmd tea.Cmd
-	m.recurringExpenses, cmd = m.recurringExpenses.Update(msg)
+	if m.showCalendar {
+		m.calendar, cmd = m.calendar.Update(msg)
+	} else {
+		m.recurringExpenses, cmd = m.recurringExpenses.Update(msg)
+	}
 	return *m, cmd
 }
 
 func (m *Model) View() string {
-	return m.recurringExpenses.View()
+	if !m.showCalendar {
+		return lipgloss.JoinVertical(lipgloss.Left, m.recurringExpenses.View(), helpText(m.keys.toggleCalendar))
+	}
+
+	hints := fmt.Sprintf("%s • %s • %s",
+		helpText(m.keys.toggleCalendar), helpText(m.keys.moreDays), helpText(m.keys.fewerDays))
+	return lipgloss.JoinVertical(lipgloss.Left, m.calendarSummary(), m.calendar.View(), hints)
+}
+
+func helpText(b key.Binding) string {
+	return b.Help().Key + " " + b.Help().Desc
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/Rshep3087/lunchtui/recurring"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lm "github.com/icco/lunchmoney"
+)
+
+// getCalendarTransactionsMsg holds the transactions matched against the recurring
+// expenses calendar, which covers its own dates rather than the selected period.
+type getCalendarTransactionsMsg struct {
+	cacheInfo
+	ts         []*lm.Transaction
+	start, end string
+}
+
+func (m model) getCalendarTransactions(start, end string) tea.Cmd {
+	query := m.lmCache.transactionsQuery(start, end, m.debitsAsNegative)
+	return loadCached(m.lmCache, query, func(ts []*lm.Transaction, info cacheInfo) tea.Msg {
+		return getCalendarTransactionsMsg{cacheInfo: info, ts: ts, start: start, end: end}
+	})
+}
+
+func (m model) handleCalendarShown(msg recurring.CalendarShownMsg) (tea.Model, tea.Cmd) {
+	return m, m.getCalendarTransactions(msg.Start, msg.End)
+}
+
+func (m model) handleGetCalendarTransactions(msg getCalendarTransactionsMsg) (tea.Model, tea.Cmd) {
+	// the calendar ignores the transactions if its dates changed since they were requested
+	m.recurringExpenses.SetTransactions(msg.start, msg.end, msg.ts)
+	return m, msg.refresh
+}
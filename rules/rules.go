@@ -0,0 +1,219 @@
+// Package rules matches transactions against user defined rules from the
+// `[[rules]]` section of the config file and reports the changes they make.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/icco/lunchmoney"
+)
+
+// Valid statuses a rule can set.
+const (
+	statusCleared   = "cleared"
+	statusUncleared = "uncleared"
+	statusPending   = "pending"
+)
+
+// Rule is a single `[[rules]]` entry. A transaction matches when it satisfies every
+// condition that is set. Categories, tags and accounts may be given by name or ID.
+type Rule struct {
+	// Name identifies the rule in previews.
+	Name string `mapstructure:"name"`
+
+	// Payee is a case-insensitive regular expression matched against the payee.
+	Payee string `mapstructure:"payee"`
+	// Notes is a case-insensitive regular expression matched against the notes.
+	Notes string `mapstructure:"notes"`
+	// MinAmount and MaxAmount bound the amount, inclusive.
+	MinAmount *float64 `mapstructure:"min_amount"`
+	MaxAmount *float64 `mapstructure:"max_amount"`
+	// Account is the name or ID of the plaid account or asset.
+	Account string `mapstructure:"account"`
+
+	// Category is set on matching transactions.
+	Category string `mapstructure:"category"`
+	// Tags are added to matching transactions.
+	Tags []string `mapstructure:"tags"`
+	// Status (cleared or uncleared) is set on matching transactions.
+	Status string `mapstructure:"status"`
+}
+
+// Action is what a matching rule changes on a transaction.
+type Action struct {
+	// Rule is the name of the rule that matched.
+	Rule       string
+	CategoryID int64
+	TagIDs     []int
+	Status     string
+}
+
+type compiledRule struct {
+	payee     *regexp.Regexp
+	notes     *regexp.Regexp
+	minAmount *float64
+	maxAmount *float64
+	account   string
+	action    Action
+}
+
+// Engine matches transactions against compiled rules.
+type Engine struct {
+	rules []compiledRule
+}
+
+// Compile validates the rules and resolves category and tag names to IDs.
+func Compile(rules []Rule, categories []*lunchmoney.Category, tags []*lunchmoney.Tag) (*Engine, error) {
+	categoryIDs := make(map[string]int64, len(categories))
+	for _, c := range categories {
+		if c.IsGroup {
+			continue
+		}
+		categoryIDs[strings.ToLower(html.UnescapeString(c.Name))] = c.ID
+		categoryIDs[strconv.FormatInt(c.ID, 10)] = c.ID
+	}
+
+	tagIDs := make(map[string]int, len(tags))
+	for _, t := range tags {
+		tagIDs[strings.ToLower(html.UnescapeString(t.Name))] = t.ID
+		tagIDs[strconv.Itoa(t.ID)] = t.ID
+	}
+
+	e := &Engine{rules: make([]compiledRule, 0, len(rules))}
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+
+		cr, err := compileRule(name, r, categoryIDs, tagIDs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		e.rules = append(e.rules, cr)
+	}
+
+	return e, nil
+}
+
+func compileRule(name string, r Rule, categoryIDs map[string]int64, tagIDs map[string]int) (compiledRule, error) {
+	cr := compiledRule{
+		minAmount: r.MinAmount,
+		maxAmount: r.MaxAmount,
+		account:   strings.ToLower(r.Account),
+		action:    Action{Rule: name, Status: r.Status},
+	}
+
+	var err error
+	if r.Payee != "" {
+		if cr.payee, err = regexp.Compile("(?i)" + r.Payee); err != nil {
+			return cr, fmt.Errorf("invalid payee pattern: %w", err)
+		}
+	}
+	if r.Notes != "" {
+		if cr.notes, err = regexp.Compile("(?i)" + r.Notes); err != nil {
+			return cr, fmt.Errorf("invalid notes pattern: %w", err)
+		}
+	}
+
+	if r.Payee == "" && r.Notes == "" && r.MinAmount == nil && r.MaxAmount == nil && r.Account == "" {
+		return cr, errors.New("rule has no conditions")
+	}
+
+	if r.Category != "" {
+		id, ok := categoryIDs[strings.ToLower(r.Category)]
+		if !ok {
+			return cr, fmt.Errorf("unknown category %q", r.Category)
+		}
+		cr.action.CategoryID = id
+	}
+
+	for _, tag := range r.Tags {
+		id, ok := tagIDs[strings.ToLower(tag)]
+		if !ok {
+			return cr, fmt.Errorf("unknown tag %q", tag)
+		}
+		cr.action.TagIDs = append(cr.action.TagIDs, id)
+	}
+
+	if r.Status != "" && r.Status != statusCleared && r.Status != statusUncleared {
+		return cr, fmt.Errorf("invalid status %q (must be cleared or uncleared)", r.Status)
+	}
+
+	if cr.action.CategoryID == 0 && len(cr.action.TagIDs) == 0 && r.Status == "" {
+		return cr, errors.New("rule sets no category, tags or status")
+	}
+
+	return cr, nil
+}
+
+// Len returns the number of rules in the engine.
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+// Match returns the action of the first rule that matches the transaction.
+// Pending transactions never match because they cannot be updated.
+func (e *Engine) Match(t *lunchmoney.Transaction) (Action, bool) {
+	if t.Status == statusPending {
+		return Action{}, false
+	}
+
+	for _, r := range e.rules {
+		if r.matches(t) {
+			return r.action, true
+		}
+	}
+	return Action{}, false
+}
+
+func (r compiledRule) matches(t *lunchmoney.Transaction) bool {
+	if r.payee != nil && !r.payee.MatchString(html.UnescapeString(t.Payee)) {
+		return false
+	}
+	if r.notes != nil && !r.notes.MatchString(t.Notes) {
+		return false
+	}
+
+	if r.minAmount != nil || r.maxAmount != nil {
+		amount, err := strconv.ParseFloat(t.Amount, 64)
+		if err != nil {
+			return false
+		}
+		if r.minAmount != nil && amount < *r.minAmount {
+			return false
+		}
+		if r.maxAmount != nil && amount > *r.maxAmount {
+			return false
+		}
+	}
+
+	if r.account != "" && !matchesAccount(r.account, t) {
+		return false
+	}
+
+	return true
+}
+
+// matchesAccount reports whether the lowercased account name or ID refers to the transaction's account.
+func matchesAccount(account string, t *lunchmoney.Transaction) bool {
+	candidates := []string{
+		t.PlaidAccountName,
+		t.PlaidAccountDisplayName,
+		t.AssetName,
+		t.AssetDisplayName,
+	}
+	for _, c := range candidates {
+		if c != "" && strings.ToLower(html.UnescapeString(c)) == account {
+			return true
+		}
+	}
+
+	id, err := strconv.ParseInt(account, 10, 64)
+	return err == nil && (id == t.PlaidAccountID || id == t.AssetID)
+}
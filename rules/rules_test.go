@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/icco/lunchmoney"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var (
+	testCategories = []*lunchmoney.Category{
+		{ID: 10, Name: "Coffee Shops"},
+		{ID: 11, Name: "Groceries"},
+		{ID: 12, Name: "Food", IsGroup: true},
+	}
+	testTags = []*lunchmoney.Tag{
+		{ID: 1, Name: "coffee"},
+		{ID: 2, Name: "Work &amp; Travel"},
+	}
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{
+			name: "category and tags by name",
+			rule: Rule{Payee: "starbucks", Category: "coffee shops", Tags: []string{"Coffee", "work & travel"}},
+		},
+		{
+			name: "category by id",
+			rule: Rule{Payee: "starbucks", Category: "10"},
+		},
+		{
+			name:    "invalid payee pattern",
+			rule:    Rule{Payee: "(", Category: "10"},
+			wantErr: "rule 1: invalid payee pattern: error parsing regexp: missing closing ): `(?i)(`",
+		},
+		{
+			name:    "no conditions",
+			rule:    Rule{Name: "empty", Category: "10"},
+			wantErr: "empty: rule has no conditions",
+		},
+		{
+			name:    "no actions",
+			rule:    Rule{Payee: "starbucks"},
+			wantErr: "rule 1: rule sets no category, tags or status",
+		},
+		{
+			name:    "category groups cannot be set",
+			rule:    Rule{Payee: "starbucks", Category: "Food"},
+			wantErr: `rule 1: unknown category "Food"`,
+		},
+		{
+			name:    "unknown tag",
+			rule:    Rule{Payee: "starbucks", Tags: []string{"nope"}},
+			wantErr: `rule 1: unknown tag "nope"`,
+		},
+		{
+			name:    "invalid status",
+			rule:    Rule{Payee: "starbucks", Status: "pending"},
+			wantErr: `rule 1: invalid status "pending" (must be cleared or uncleared)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]Rule{tt.rule}, testCategories, testTags)
+			if tt.wantErr != "" {
+				be.Nonzero(t, err)
+				be.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			be.NilErr(t, err)
+		})
+	}
+}
+
+func TestEngineMatch(t *testing.T) {
+	engine, err := Compile([]Rule{
+		{Name: "small coffee", Payee: "^starbucks", MaxAmount: ptr(10.0), Category: "Coffee Shops"},
+		{Name: "big coffee", Payee: "starbucks", MinAmount: ptr(10.0), Tags: []string{"coffee"}},
+		{Name: "groceries", Account: "chase checking", Notes: "weekly", Category: "Groceries", Status: "cleared"},
+		{Name: "by account id", Account: "42", Status: "cleared"},
+	}, testCategories, testTags)
+	be.NilErr(t, err)
+	be.Equal(t, 4, engine.Len())
+
+	tests := []struct {
+		name     string
+		trans    *lunchmoney.Transaction
+		expected string
+	}{
+		{
+			name:     "payee is case insensitive",
+			trans:    &lunchmoney.Transaction{Payee: "STARBUCKS #123", Amount: "4.50"},
+			expected: "small coffee",
+		},
+		{
+			name:     "first matching rule wins",
+			trans:    &lunchmoney.Transaction{Payee: "Starbucks", Amount: "10.00"},
+			expected: "small coffee",
+		},
+		{
+			name:     "amount range",
+			trans:    &lunchmoney.Transaction{Payee: "Starbucks", Amount: "25.00"},
+			expected: "big coffee",
+		},
+		{
+			name: "account name and notes",
+			trans: &lunchmoney.Transaction{
+				Payee:                   "Market",
+				Amount:                  "80.00",
+				Notes:                   "Weekly shop",
+				PlaidAccountName:        "checking",
+				PlaidAccountDisplayName: "Chase Checking",
+			},
+			expected: "groceries",
+		},
+		{
+			name:     "account id",
+			trans:    &lunchmoney.Transaction{Payee: "Market", Amount: "1.00", AssetID: 42},
+			expected: "by account id",
+		},
+		{
+			name:  "no match",
+			trans: &lunchmoney.Transaction{Payee: "Market", Amount: "1.00"},
+		},
+		{
+			name:  "pending transactions never match",
+			trans: &lunchmoney.Transaction{Payee: "Starbucks", Amount: "4.50", Status: "pending"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, ok := engine.Match(tt.trans)
+			be.Equal(t, tt.expected != "", ok)
+			be.Equal(t, tt.expected, action.Rule)
+		})
+	}
+}
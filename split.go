@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Rhymond/go-money"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+)
+
+// transactionSplit holds the values entered in the split form. The form is built
+// for the maximum number of parts and hides the parts past count.
+type transactionSplit struct {
+	item       transactionItem
+	count      int
+	categories [maxSplitParts]int64
+	amounts    [maxSplitParts]string
+	notes      [maxSplitParts]string
+}
+
+// splitTransactionMsg is sent once a split has been submitted.
+type splitTransactionMsg struct {
+	payee string
+	parts int
+	err   error
+}
+
+// newTransactionSplit starts a split of the transaction into two parts of its category.
+func newTransactionSplit(item transactionItem) *transactionSplit {
+	split := &transactionSplit{item: item, count: minSplitParts}
+	for i := range split.categories {
+		split.categories[i] = item.t.CategoryID
+	}
+	return split
+}
+
+// currency returns the currency of the transaction being split.
+func (s *transactionSplit) currency() string {
+	if s.item.t.Currency == "" {
+		return "usd"
+	}
+	return s.item.t.Currency
+}
+
+// parts validates the entered parts and returns them for the API. Every part needs an
+// amount, and together the parts must add up to the amount of the transaction.
+func (s *transactionSplit) parts() ([]transactionSplitPart, error) {
+	total, err := parseAmount(s.item.t.Amount, s.currency())
+	if err != nil {
+		return nil, fmt.Errorf("transaction amount: %w", err)
+	}
+
+	sum := money.New(0, s.currency())
+	parts := make([]transactionSplitPart, 0, s.count)
+	for i := range s.count {
+		amount, parseErr := parseAmount(s.amounts[i], s.currency())
+		if parseErr != nil {
+			return nil, fmt.Errorf("part %d: %w", i+1, parseErr)
+		}
+		if amount.IsZero() {
+			return nil, fmt.Errorf("part %d: amount cannot be zero", i+1)
+		}
+
+		if sum, err = sum.Add(amount); err != nil {
+			return nil, fmt.Errorf("part %d: %w", i+1, err)
+		}
+
+		part := transactionSplitPart{
+			Amount: strings.TrimSpace(s.amounts[i]),
+			Notes:  strings.TrimSpace(s.notes[i]),
+		}
+		if s.categories[i] != 0 {
+			part.CategoryID = ptr(s.categories[i])
+		}
+		parts = append(parts, part)
+	}
+
+	if equal, _ := sum.Equals(total); !equal {
+		remaining, _ := total.Subtract(sum)
+		return nil, fmt.Errorf("parts add up to %s, not %s (%s remaining)",
+			sum.Display(), total.Display(), remaining.Display())
+	}
+
+	return parts, nil
+}
+
+// showSplitForm opens the split form for the transaction in the detail view.
+func showSplitForm(m model) (tea.Model, tea.Cmd) {
+	if m.currentTransaction == nil || !canSplit(m.currentTransaction) {
+		return m, nil
+	}
+
+	log.Debug("splitting transaction", "transaction_id", m.currentTransaction.t.ID)
+
+	m.split = newTransactionSplit(*m.currentTransaction)
+	m.splitForm = m.newSplitForm(m.split)
+	m.previousSessionState = m.sessionState
+	m.sessionState = splitTransactionState
+	return m, tea.Batch(m.splitForm.Init(), tea.WindowSize())
+}
+
+// canSplit reports whether the transaction can be split. Groups and the parts of
+// another split cannot be split again.
+func canSplit(t *transactionItem) bool {
+	return !t.t.IsGroup && t.t.ParentID == 0
+}
+
+func (m model) newSplitForm(split *transactionSplit) *huh.Form {
+	amount, err := parseAmount(split.item.t.Amount, split.currency())
+	total := split.item.t.Amount
+	if err == nil {
+		total = amount.Display()
+	}
+
+	countOpts := make([]huh.Option[int], 0, maxSplitParts-minSplitParts+1)
+	for n := minSplitParts; n <= maxSplitParts; n++ {
+		countOpts = append(countOpts, huh.NewOption(fmt.Sprintf("%d parts", n), n))
+	}
+
+	groups := []*huh.Group{
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title(fmt.Sprintf("Split %s (%s)", split.item.t.Payee, total)).
+				Description("Number of transactions to split it into").
+				Options(countOpts...).
+				Value(&split.count),
+		),
+	}
+
+	for i := range maxSplitParts {
+		groups = append(groups, huh.NewGroup(
+			huh.NewSelect[int64]().Title(fmt.Sprintf("Part %d category", i+1)).
+				Height(categoryFormHeight).Options(m.generateCategoryOptions()...).
+				Value(&split.categories[i]),
+			huh.NewInput().Title(fmt.Sprintf("Part %d amount", i+1)).
+				Description("Enter the amount (e.g., 10.00)").
+				Value(&split.amounts[i]).
+				Validate(func(s string) error {
+					_, parseErr := parseAmount(s, split.currency())
+					return parseErr
+				}),
+			huh.NewInput().Title(fmt.Sprintf("Part %d notes", i+1)).Value(&split.notes[i]),
+		).WithHideFunc(func() bool { return i >= split.count }))
+	}
+
+	groups = append(groups, huh.NewGroup(
+		huh.NewConfirm().Title("Split transaction?").Key("submit").
+			DescriptionFunc(func() string {
+				if _, partsErr := split.parts(); partsErr != nil {
+					return partsErr.Error()
+				}
+				return fmt.Sprintf("The parts add up to %s", total)
+			}, &split.amounts).
+			Validate(func(submit bool) error {
+				if !submit {
+					return nil
+				}
+				_, partsErr := split.parts()
+				return partsErr
+			}),
+	))
+
+	return huh.NewForm(groups...).WithShowHelp(true).WithShowErrors(true)
+}
+
+func (m model) handleSplitFormState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.splitForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.splitForm = f
+	} else {
+		log.Debug("splitForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.splitForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	m.previousSessionState = m.sessionState
+	if !m.splitForm.GetBool("submit") {
+		m.sessionState = detailedTransaction
+		return m, nil
+	}
+
+	m.sessionState = transactions
+	return m, m.submitSplit(m.split)
+}
+
+// submitSplit splits the transaction through the API.
+func (m model) submitSplit(split *transactionSplit) tea.Cmd {
+	return func() tea.Msg {
+		msg := splitTransactionMsg{payee: split.item.t.Payee}
+
+		parts, err := split.parts()
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.parts = len(parts)
+
+		ctx, cancel := context.WithTimeout(context.Background(), transactionLoadTimeout)
+		defer cancel()
+
+		resp, err := splitTransaction(ctx, m.lmc, split.item.t.ID, parts, m.debitsAsNegative)
+		if err == nil && len(resp.Split) == 0 {
+			err = errors.New("transaction not split")
+		}
+		if err != nil {
+			log.Debug("error splitting transaction", "error", err)
+			msg.err = err
+			return msg
+		}
+
+		log.Debug("transaction split", "transaction_id", split.item.t.ID, "ids", resp.Split)
+		return msg
+	}
+}
+
+func (m model) handleSplitTransactionMsg(msg splitTransactionMsg) (tea.Model, tea.Cmd) {
+	m.split = nil
+	if msg.err != nil {
+		return m, m.transactions.NewStatusMessage(
+			fmt.Sprintf("Error splitting transaction: %s", msg.err.Error()),
+		)
+	}
+	return m, tea.Batch(m.getTransactions,
+		m.transactions.NewStatusMessage(fmt.Sprintf("Split %s into %d transactions", msg.payee, msg.parts)),
+	)
+}
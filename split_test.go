@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestTransactionSplitParts(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  string
+		amounts []string
+		wantErr string
+	}{
+		{
+			name:    "parts add up",
+			amount:  "100.0000",
+			amounts: []string{"60", "40.00"},
+		},
+		{
+			name:    "cents are not lost to rounding",
+			amount:  "0.87",
+			amounts: []string{"0.29", "0.29", "0.29"},
+		},
+		{
+			name:    "negative parts",
+			amount:  "-25.5000",
+			amounts: []string{"-20.25", "-5.25"},
+		},
+		{
+			name:    "parts add up to less",
+			amount:  "100.0000",
+			amounts: []string{"60", "30"},
+			wantErr: "parts add up to $90.00, not $100.00 ($10.00 remaining)",
+		},
+		{
+			name:    "invalid amount",
+			amount:  "100.0000",
+			amounts: []string{"60", "forty"},
+			wantErr: `part 2: invalid amount "forty"`,
+		},
+		{
+			name:    "zero amount",
+			amount:  "100.0000",
+			amounts: []string{"100", "0"},
+			wantErr: "part 2: amount cannot be zero",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := newTransactionSplit(transactionItem{
+				t: &lm.Transaction{ID: 1, Amount: tt.amount, Currency: "usd", CategoryID: 7},
+			})
+			split.count = len(tt.amounts)
+			copy(split.amounts[:], tt.amounts)
+
+			parts, err := split.parts()
+			if tt.wantErr != "" {
+				be.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+
+			be.NilErr(t, err)
+			be.Equal(t, len(tt.amounts), len(parts))
+			for i, part := range parts {
+				be.Equal(t, tt.amounts[i], part.Amount)
+				be.Equal(t, int64(7), *part.CategoryID)
+			}
+		})
+	}
+}
+
+func TestTransactionSplitPartsUncategorized(t *testing.T) {
+	split := newTransactionSplit(transactionItem{t: &lm.Transaction{Amount: "10", Currency: "usd"}})
+	split.amounts = [maxSplitParts]string{"4", "6 "}
+	split.notes = [maxSplitParts]string{" shampoo ", ""}
+
+	parts, err := split.parts()
+	be.NilErr(t, err)
+	be.True(t, parts[0].CategoryID == nil)
+	be.Equal(t, "shampoo", parts[0].Notes)
+	be.Equal(t, "6", parts[1].Amount)
+}
+
+func TestCanSplit(t *testing.T) {
+	be.True(t, canSplit(&transactionItem{t: &lm.Transaction{ID: 1}}))
+	be.False(t, canSplit(&transactionItem{t: &lm.Transaction{ID: 1, IsGroup: true}}))
+	be.False(t, canSplit(&transactionItem{t: &lm.Transaction{ID: 1, ParentID: 2}}))
+}
+
+func TestSplitTransaction(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		be.Equal(t, http.MethodPut, r.Method)
+		be.Equal(t, "/v1/transactions/42", r.URL.Path)
+		data, _ := io.ReadAll(r.Body)
+		be.NilErr(t, json.Unmarshal(data, &body))
+		_, _ = w.Write([]byte(`{"updated": true, "split": [43, 44]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	lmc, err := lm.NewClient("token")
+	be.NilErr(t, err)
+	lmc.Base, err = url.Parse(server.URL)
+	be.NilErr(t, err)
+
+	resp, err := splitTransaction(t.Context(), lmc, 42, []transactionSplitPart{
+		{Amount: "6", CategoryID: ptr(int64(3))},
+		{Amount: "4", Notes: "gift"},
+	}, true)
+	be.NilErr(t, err)
+	be.AllEqual(t, []int{43, 44}, resp.Split)
+
+	be.Equal(t, true, body["debit_as_negative"])
+	_, hasTransaction := body["transaction"]
+	be.False(t, hasTransaction)
+	split, ok := body["split"].([]any)
+	be.True(t, ok)
+	be.Equal(t, 2, len(split))
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Rshep3087/lunchtui/cache"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+	lm "github.com/icco/lunchmoney"
+)
+
+// tagEdit is a change to the tags of a transaction.
+type tagEdit struct {
+	item   transactionItem
+	tagIDs []int
+	// newTags is a comma separated list of tags to add, created when they do not exist
+	newTags string
+	// err is why the last attempt to save the tags failed
+	err error
+}
+
+// updateTagsMsg is sent once the tags of a transaction have been replaced.
+type updateTagsMsg struct {
+	edit *tagEdit
+	// tags are the tags of the transaction now, new tags without an ID
+	tags    []lm.Tag
+	created bool
+	err     error
+}
+
+// showTagForm opens the form to add and remove the tags of the current transaction.
+func showTagForm(m model) (tea.Model, tea.Cmd) {
+	if m.currentTransaction == nil {
+		return m, nil
+	}
+
+	edit := &tagEdit{item: *m.currentTransaction}
+	for _, tag := range m.currentTransaction.t.Tags {
+		edit.tagIDs = append(edit.tagIDs, tag.ID)
+	}
+	return openTagForm(m, edit)
+}
+
+func openTagForm(m model, edit *tagEdit) (tea.Model, tea.Cmd) {
+	m.tagEdit = edit
+	m.tagForm = m.newTagForm(edit)
+	m.previousSessionState = detailedTransaction
+	m.sessionState = editTagsState
+	return m, tea.Batch(m.tagForm.Init(), tea.WindowSize())
+}
+
+func (m model) newTagForm(edit *tagEdit) *huh.Form {
+	description := "Comma separated, tags that do not exist yet are created"
+	if edit.err != nil {
+		description = "Error saving the tags: " + edit.err.Error()
+	}
+
+	var fields []huh.Field
+	if options := m.sortedTagOptions(); len(options) > 0 {
+		fields = append(fields, huh.NewMultiSelect[int]().
+			Title(fmt.Sprintf("Tags of %s", edit.item.t.Payee)).
+			Options(options...).
+			Value(&edit.tagIDs))
+	}
+	fields = append(fields,
+		huh.NewInput().Title("New tags").Description(description).Value(&edit.newTags),
+		huh.NewConfirm().Title("Save").Key("submit"),
+	)
+
+	return huh.NewForm(huh.NewGroup(fields...)).WithShowHelp(true).WithShowErrors(true)
+}
+
+// sortedTagOptions lists the tags by name.
+func (m model) sortedTagOptions() []huh.Option[int] {
+	options := m.generateTagOptions()
+	slices.SortFunc(options, func(a, b huh.Option[int]) int {
+		return cmp.Compare(strings.ToLower(a.Key), strings.ToLower(b.Key))
+	})
+	return options
+}
+
+func (m model) handleTagFormState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, formCmd := m.tagForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.tagForm = f
+	} else {
+		log.Debug("tagForm did not return a form, returning nil")
+		return m, nil
+	}
+
+	if m.tagForm.State != huh.StateCompleted {
+		return m, formCmd
+	}
+
+	m.previousSessionState = m.sessionState
+	m.sessionState = detailedTransaction
+	edit := m.tagEdit
+	m.tagEdit = nil
+	if !m.tagForm.GetBool("submit") {
+		return m, nil
+	}
+	return m, m.submitTagEdit(edit)
+}
+
+// submitTagEdit replaces the tags of the transaction through the API.
+func (m model) submitTagEdit(edit *tagEdit) tea.Cmd {
+	tagIDs, newTags := resolveTagNames(edit.tagIDs, edit.newTags, m.tags)
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), transactionLoadTimeout)
+		defer cancel()
+
+		msg := updateTagsMsg{edit: edit, created: len(newTags) > 0}
+		msg.err = setTransactionTags(ctx, m.lmc, edit.item.t.ID, tagIDs, newTags)
+		if msg.err != nil {
+			log.Debug("error setting tags", "transaction", edit.item.t.ID, "error", msg.err)
+			return msg
+		}
+
+		for _, id := range tagIDs {
+			if tag, ok := m.tags[id]; ok {
+				msg.tags = append(msg.tags, *tag)
+			}
+		}
+		for _, name := range newTags {
+			msg.tags = append(msg.tags, lm.Tag{Name: name})
+		}
+		return msg
+	}
+}
+
+// resolveTagNames adds the tags named in newTags to the selected tag IDs. Names of existing
+// tags are matched regardless of case, and the other names are returned to be created.
+func resolveTagNames(tagIDs []int, newTags string, tags map[int]*lm.Tag) ([]int, []string) {
+	ids := slices.Clone(tagIDs)
+	var names []string
+	for name := range strings.SplitSeq(newTags, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		id := 0
+		for _, tag := range tags {
+			if strings.EqualFold(tag.Name, name) {
+				id = tag.ID
+				break
+			}
+		}
+		switch {
+		case id == 0 && !slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) }):
+			names = append(names, name)
+		case id != 0 && !slices.Contains(ids, id):
+			ids = append(ids, id)
+		}
+	}
+	return ids, names
+}
+
+func (m model) handleUpdateTagsMsg(msg updateTagsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		// open the form again with the error so the changes are not lost
+		msg.edit.err = msg.err
+		return openTagForm(m, msg.edit)
+	}
+
+	// the list shares the transaction, so it shows the new tags too
+	msg.edit.item.t.Tags = msg.tags
+	if m.currentTransaction != nil && m.currentTransaction.t.ID == msg.edit.item.t.ID {
+		m.currentTransaction.tags = transactionTags(msg.edit.item.t)
+	}
+
+	cmds := []tea.Cmd{m.getTransactions}
+	if msg.created {
+		// creating tags through a transaction does not invalidate the cached tags
+		m.lmCache.invalidate(cache.Tags)
+		cmds = append(cmds, m.getTags)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// transactionTags returns the tags of a transaction for its list item.
+func transactionTags(t *lm.Transaction) []*lm.Tag {
+	tags := make([]*lm.Tag, len(t.Tags))
+	for i := range t.Tags {
+		tags[i] = &t.Tags[i]
+	}
+	return tags
+}
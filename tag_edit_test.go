@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	lm "github.com/icco/lunchmoney"
+)
+
+func TestResolveTagNames(t *testing.T) {
+	tags := map[int]*lm.Tag{
+		1: {ID: 1, Name: "Vacation"},
+		2: {ID: 2, Name: "shared"},
+	}
+
+	tests := []struct {
+		name          string
+		tagIDs        []int
+		newTags       string
+		expectedIDs   []int
+		expectedNames []string
+	}{
+		{
+			name:        "no new tags",
+			tagIDs:      []int{1},
+			newTags:     "",
+			expectedIDs: []int{1},
+		},
+		{
+			name:        "existing tag in another case",
+			tagIDs:      []int{2},
+			newTags:     " vacation ",
+			expectedIDs: []int{2, 1},
+		},
+		{
+			name:        "existing tag already selected",
+			tagIDs:      []int{1},
+			newTags:     "VACATION",
+			expectedIDs: []int{1},
+		},
+		{
+			name:          "new tags deduplicated",
+			tagIDs:        nil,
+			newTags:       "work, Work,, home,shared",
+			expectedIDs:   []int{2},
+			expectedNames: []string{"work", "home"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, names := resolveTagNames(tt.tagIDs, tt.newTags, tags)
+			be.AllEqual(t, tt.expectedIDs, ids)
+			be.AllEqual(t, tt.expectedNames, names)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package totals adds up amounts in several currencies. Amounts are converted to the
+// primary currency with the base amount Lunch Money returns with them, and subtotals are
+// kept in the currency of each amount.
+package totals
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Rhymond/go-money"
+)
+
+// Convert returns the amount in currency, using toBase, the amount already converted to
+// the primary currency by Lunch Money, when the amount is in another currency. The sign of
+// the amount is kept. It reports false when the amount is in another currency without a
+// base amount.
+func Convert(amount *money.Money, toBase float64, currency string) (*money.Money, bool) {
+	if strings.EqualFold(amount.Currency().Code, currency) {
+		return amount, true
+	}
+	if amount.IsZero() {
+		return money.New(0, currency), true
+	}
+	if toBase == 0 {
+		return nil, false
+	}
+
+	minorUnits := math.Pow10(money.New(0, currency).Currency().Fraction)
+	converted := money.New(int64(math.Round(math.Abs(toBase)*minorUnits)), currency)
+	if amount.IsNegative() {
+		converted = converted.Negative()
+	}
+	return converted, true
+}
+
+// Sum is a total in the primary currency with a subtotal for each currency added to it.
+type Sum struct {
+	currency    string
+	total       *money.Money
+	subtotals   map[string]*money.Money
+	unconverted int
+}
+
+// New returns an empty sum in the primary currency.
+func New(currency string) *Sum {
+	return &Sum{
+		currency:  currency,
+		total:     money.New(0, currency),
+		subtotals: make(map[string]*money.Money),
+	}
+}
+
+// Add adds the amount, with its base amount as described in Convert, and returns it in
+// the primary currency. Amounts that cannot be converted are counted in their subtotal
+// but left out of the total, and Add reports false.
+func (s *Sum) Add(amount *money.Money, toBase float64) (*money.Money, bool) {
+	code := amount.Currency().Code
+	if subtotal, ok := s.subtotals[code]; ok {
+		s.subtotals[code], _ = subtotal.Add(amount)
+	} else {
+		s.subtotals[code] = amount
+	}
+
+	converted, ok := Convert(amount, toBase, s.currency)
+	if !ok {
+		s.unconverted++
+		return nil, false
+	}
+	s.total, _ = s.total.Add(converted)
+	return converted, true
+}
+
+// Total returns the total in the primary currency.
+func (s *Sum) Total() *money.Money {
+	return s.total
+}
+
+// Unconverted returns how many amounts were left out of the total.
+func (s *Sum) Unconverted() int {
+	return s.unconverted
+}
+
+// MultiCurrency reports whether any amount was in another currency than the primary one.
+func (s *Sum) MultiCurrency() bool {
+	for code := range s.subtotals {
+		if !strings.EqualFold(code, s.currency) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subtotals returns the subtotal of each currency, the primary currency first and the
+// others by code.
+func (s *Sum) Subtotals() []*money.Money {
+	subtotals := make([]*money.Money, 0, len(s.subtotals))
+	for _, subtotal := range s.subtotals {
+		subtotals = append(subtotals, subtotal)
+	}
+
+	sort.Slice(subtotals, func(i, j int) bool {
+		a, b := subtotals[i].Currency().Code, subtotals[j].Currency().Code
+		if primary := strings.EqualFold(a, s.currency); primary != strings.EqualFold(b, s.currency) {
+			return primary
+		}
+		return a < b
+	})
+	return subtotals
+}
+
+// String formats the subtotals, such as "$12.00 + €5.00".
+func (s *Sum) String() string {
+	subtotals := s.Subtotals()
+	formatted := make([]string, len(subtotals))
+	for i, subtotal := range subtotals {
+		formatted[i] = subtotal.Display()
+	}
+	return strings.Join(formatted, " + ")
+}
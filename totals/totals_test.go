@@ -0,0 +1,51 @@
+package totals
+
+import (
+	"testing"
+
+	"github.com/Rhymond/go-money"
+	"github.com/carlmjohnson/be"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   *money.Money
+		toBase   float64
+		expected string
+		ok       bool
+	}{
+		{name: "primary currency", amount: money.New(1234, "usd"), toBase: 99, expected: "$12.34", ok: true},
+		{name: "base amount", amount: money.New(1000, "EUR"), toBase: 10.855, expected: "$10.86", ok: true},
+		{name: "keeps the sign", amount: money.New(-1000, "EUR"), toBase: 10.85, expected: "-$10.85", ok: true},
+		{name: "zero", amount: money.New(0, "EUR"), expected: "$0.00", ok: true},
+		{name: "no base amount", amount: money.New(1000, "EUR"), ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, ok := Convert(tt.amount, tt.toBase, "USD")
+			be.Equal(t, tt.ok, ok)
+			if ok {
+				be.Equal(t, tt.expected, converted.Display())
+			}
+		})
+	}
+}
+
+func TestSum(t *testing.T) {
+	sum := New("USD")
+	be.False(t, sum.MultiCurrency())
+
+	sum.Add(money.New(1000, "USD"), 10)
+	sum.Add(money.New(500, "EUR"), 5.5)
+	sum.Add(money.New(250, "CAD"), 1.8)
+	sum.Add(money.New(500, "EUR"), 5.5)
+	_, ok := sum.Add(money.New(700, "GBP"), 0)
+
+	be.False(t, ok)
+	be.Equal(t, "$22.80", sum.Total().Display())
+	be.Equal(t, 1, sum.Unconverted())
+	be.True(t, sum.MultiCurrency())
+	be.Equal(t, "$10.00 + $2.50 + €10.00 + £7.00", sum.String())
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Rshep3087/lunchtui/cache"
+	"github.com/Rshep3087/lunchtui/overview"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -24,10 +26,24 @@ type transactionItem struct {
 	plaidAccount *lm.PlaidAccount
 	asset        *lm.Asset
 	tags         []*lm.Tag
+	// marked is set when the transaction is marked for a bulk action
+	marked bool
+	// children are the grouped transactions collapsed under a group transaction
+	children []*lm.Transaction
 }
 
 func (t transactionItem) Title() string {
-	return fmt.Sprintf("%s (%d)", t.t.Payee, t.t.ID)
+	title := fmt.Sprintf("%s (%d)", t.t.Payee, t.t.ID)
+	switch {
+	case len(t.children) > 0:
+		title = fmt.Sprintf("▸ %s [group of %d]", title, len(t.children))
+	case t.t.IsGroup:
+		title = fmt.Sprintf("▸ %s [group]", title)
+	}
+	if t.marked {
+		return "✓ " + title
+	}
+	return title
 }
 
 func (t transactionItem) Description() string {
@@ -86,6 +102,11 @@ type transactionListKeyMap struct {
 	refreshTransactions   key.Binding
 	showDetailed          key.Binding
 	insertTransaction     key.Binding
+	toggleMark            key.Binding
+	markAll               key.Binding
+	bulkActions           key.Binding
+	applyRules            key.Binding
+	aiCategorize          key.Binding
 }
 
 func newTransactionListKeyMap() *transactionListKeyMap {
@@ -114,6 +135,26 @@ func newTransactionListKeyMap() *transactionListKeyMap {
 			key.WithKeys("i"),
 			key.WithHelp("i", "insert new transaction"),
 		),
+		toggleMark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark transaction"),
+		),
+		markAll: key.NewBinding(
+			key.WithKeys("ctrl+a"),
+			key.WithHelp("ctrl+a", "mark all visible transactions"),
+		),
+		bulkActions: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "bulk actions for marked transactions"),
+		),
+		applyRules: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "apply rules to visible transactions"),
+		),
+		aiCategorize: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "AI categorize visible uncategorized transactions"),
+		),
 	}
 }
 
@@ -171,6 +212,26 @@ func updateTransactions(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			return showDetailedTransaction(m)
 		}
 
+		if key.Matches(msg, m.transactionsListKeys.toggleMark) {
+			return toggleMark(m)
+		}
+
+		if key.Matches(msg, m.transactionsListKeys.markAll) {
+			return toggleMarkAll(m)
+		}
+
+		if key.Matches(msg, m.transactionsListKeys.bulkActions) {
+			return showBulkActionForm(m)
+		}
+
+		if key.Matches(msg, m.transactionsListKeys.applyRules) {
+			return applyRulesToVisible(m)
+		}
+
+		if key.Matches(msg, m.transactionsListKeys.aiCategorize) {
+			return startAICategorization(m)
+		}
+
 		if key.Matches(msg, m.transactionsListKeys.insertTransaction) {
 			log.Debug("switching to insert transaction form")
 			m.previousSessionState = m.sessionState
@@ -393,8 +454,32 @@ func filterUncategorizedTransactions(m model) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// filterCategoryTransactions shows the transactions of a category picked in the spending
+// breakdown of the overview, which covers the same period as the transactions.
+func filterCategoryTransactions(m model, msg overview.CategorySelectedMsg) (tea.Model, tea.Cmd) {
+	categoryItems := make([]list.Item, 0)
+	for _, item := range m.originalTransactions {
+		if t, ok := item.(transactionItem); ok && t.t.CategoryID == msg.CategoryID {
+			categoryItems = append(categoryItems, item)
+		}
+	}
+	m.transactions.SetItems(categoryItems)
+	// Reset the uncleared filter state since we're applying a different filter
+	m.isFilteredUncleared = false
+	m.transactionsStats = newTransactionStats(m.transactions.Items())
+
+	m.previousSessionState = m.sessionState
+	m.sessionState = transactions
+
+	status := fmt.Sprintf("Showing %d %s transactions, esc to go back", len(categoryItems), msg.Name)
+	return m, m.transactions.NewStatusMessage(status)
+}
+
 func refreshTransactions(m model) (tea.Model, tea.Cmd) {
 	log.Debug("refreshing transactions")
+	// skip the cache so the transactions come from the API
+	m.lmCache.invalidate(cache.Transactions)
+
 	// Set loading state and switch to loading view
 	m.loadingState.unset("transactions")
 	m.previousSessionState = m.sessionState
@@ -438,6 +523,10 @@ func newTransactionStats(ts []list.Item) *transactionsStats {
 			continue
 		}
 
+		if ti.marked {
+			stats.marked++
+		}
+
 		switch ti.t.Status {
 		case pendingStatus:
 			stats.pending++
@@ -455,6 +544,7 @@ type transactionsStats struct {
 	pending   int
 	uncleared int
 	cleared   int
+	marked    int
 }
 
 // View renders the transactions stats in a single line.
@@ -474,7 +564,14 @@ func (t transactionsStats) View(theme Theme) string {
 		MarginRight(standardMargin).
 		Render(fmt.Sprintf("%d cleared", t.cleared))
 
-	transactionStatus := lipgloss.JoinHorizontal(lipgloss.Left, pending, uncleared, cleared)
+	parts := []string{pending, uncleared, cleared}
+	if t.marked > 0 {
+		parts = append(parts, lipgloss.NewStyle().
+			Foreground(theme.Primary).
+			Render(fmt.Sprintf("%d marked", t.marked)))
+	}
+
+	transactionStatus := lipgloss.JoinHorizontal(lipgloss.Left, parts...)
 	return lipgloss.NewStyle().
 		MarginTop(1).
 		MarginLeft(standardMargin).
@@ -539,6 +636,12 @@ func updateDetailedTransaction(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			log.Debug("detailed transaction session state changed to categorizeTransaction")
 
 			return m, tea.Batch(m.categoryForm.Init(), tea.WindowSize())
+		case "S":
+			return showSplitForm(m)
+		case "#":
+			return showTagForm(m)
+		case "u":
+			return showUngroupForm(m)
 		}
 	}
 
@@ -607,7 +710,7 @@ func detailedTransactionView(m model) string {
 	header := styles.headerStyle.Render("Transaction Details")
 	details := buildTransactionDetailsWithNotes(data, styles, m)
 	content := lipgloss.JoinVertical(lipgloss.Left, details...)
-	instructions := createInstructionsWithNotes(styles, m.isEditingNotes)
+	instructions := createInstructionsWithNotes(styles, m.isEditingNotes, m.currentTransaction)
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		header,
@@ -747,6 +850,7 @@ func buildTransactionDetailsWithNotes(data transactionDisplayData, styles detail
 			styles.labelStyle.Render("Status:"),
 			data.statusStyle.Render(" "+t.t.Status),
 		),
+		createDetailRow("Tags:", data.tagsStr, styles),
 	}
 
 	// Add optional fields
@@ -792,15 +896,34 @@ func appendOptionalFields(details []string, t *transactionItem, styles detailedT
 	if t.t.IsGroup {
 		details = append(details, createDetailRow("Group Transaction:", "Yes", styles))
 	}
+
+	label := "Grouped:"
+	for _, child := range t.children {
+		amount := child.Amount
+		if parsed, err := child.ParsedAmount(); err == nil {
+			amount = parsed.Display()
+		}
+		row := fmt.Sprintf("%s  %s  %s", child.Date, child.Payee, amount)
+		details = append(details, createDetailRow(label, row, styles))
+		label = ""
+	}
 	return details
 }
 
 // createInstructionsWithNotes creates instructions with notes editing support.
-func createInstructionsWithNotes(styles detailedTransactionStyles, isEditingNotes bool) string {
+func createInstructionsWithNotes(styles detailedTransactionStyles, isEditingNotes bool, t *transactionItem) string {
 	if isEditingNotes {
 		return styles.instructionStyle.Render("Press 'enter' to save notes, 'esc' to cancel")
 	}
+
+	instructions := []string{"'n' to edit notes", "'c' to categorize transaction", "'#' to edit tags"}
+	if canSplit(t) {
+		instructions = append(instructions, "'S' to split transaction")
+	}
+	if t.t.IsGroup {
+		instructions = append(instructions, "'u' to ungroup transactions")
+	}
 	return styles.instructionStyle.Render(
-		"'n' to edit notes, 'c' to categorize transaction,\n'esc' to return to transaction list",
+		strings.Join(instructions, ", ") + ",\n'esc' to return to transaction list",
 	)
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lm "github.com/icco/lunchmoney"
+)
+
+// getTrendsMsg holds the transactions of the months shown in the trends.
+type getTrendsMsg struct {
+	cacheInfo
+	ts     []*lm.Transaction
+	period Period
+}
+
+// trendsPeriod returns the months shown in the trends, ending with the month the selected
+// period ends in.
+func (m model) trendsPeriod() Period {
+	end := m.selectedPeriod().end
+	lastMonth := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, end.Location())
+	start := lastMonth.AddDate(0, 1-m.trends.Months(), 0)
+	return newCustomPeriod(start, lastMonth.AddDate(0, 1, -1))
+}
+
+func (m model) getTrends() tea.Msg {
+	period := m.trendsPeriod()
+
+	query := m.lmCache.transactionsQuery(period.startDate(), period.endDate(), m.debitsAsNegative)
+	return loadCached(m.lmCache, query, func(ts []*lm.Transaction, info cacheInfo) tea.Msg {
+		return getTrendsMsg{cacheInfo: info, ts: ts, period: period}
+	})()
+}
+
+func (m model) handleGetTrends(msg getTrendsMsg) (tea.Model, tea.Cmd) {
+	// the user moved to another period or range since the transactions were requested
+	if expected := m.trendsPeriod(); expected.String() != msg.period.String() {
+		return m, nil
+	}
+
+	var currency string
+	if m.user != nil {
+		currency = m.user.PrimaryCurrency
+	}
+	ts, _ := collapseGroups(m.withoutHiddenTransactions(msg.ts))
+	m.trends.SetTransactions(ts, m.idToCategory, msg.period.start, currency)
+	m.period = m.selectedPeriod()
+	return m, msg.refresh
+}
@@ -0,0 +1,194 @@
+package trends
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	eighthsPerCell = 8
+	// minLabelWidth is the width a month label needs, with a space before the next one
+	minLabelWidth = 4
+)
+
+// barTicks are the partial blocks that end a bar, from one eighth to a full cell.
+var barTicks = []rune("▁▂▃▄▅▆▇█")
+
+// noSeries marks a cell that is not part of a bar or line.
+const noSeries = -1
+
+// cell is one character of a chart, drawn in the style of its series.
+type cell struct {
+	r      rune
+	series int
+}
+
+// grid holds the cells of a chart, rows from top to bottom.
+type grid [][]cell
+
+func newGrid(height, width int) grid {
+	g := make(grid, height)
+	for y := range g {
+		g[y] = make([]cell, width)
+		for x := range g[y] {
+			g[y][x] = cell{r: ' ', series: noSeries}
+		}
+	}
+	return g
+}
+
+// set sets the cell at column x and row y, counting rows from the bottom.
+func (g grid) set(x, y int, r rune, series int) {
+	row := len(g) - 1 - y
+	if row < 0 || row >= len(g) || x < 0 || x >= len(g[row]) {
+		return
+	}
+	g[row][x] = cell{r: r, series: series}
+}
+
+// empty reports whether the cell at column x and row y, from the bottom, is not drawn.
+func (g grid) empty(x, y int) bool {
+	row := len(g) - 1 - y
+	if row < 0 || row >= len(g) || x < 0 || x >= len(g[row]) {
+		return false
+	}
+	return g[row][x].series == noSeries
+}
+
+// barGrid draws one vertical bar per value, scaled so highest fills the height. Each bar
+// takes colWidth columns, less one for the gap to the next bar.
+func barGrid(values []float64, highest float64, height, colWidth int) grid {
+	g := newGrid(height, len(values)*colWidth)
+	if highest <= 0 {
+		return g
+	}
+
+	barWidth := max(colWidth-1, 1)
+	for i, v := range values {
+		fill := int(math.Round(math.Max(v, 0) / highest * float64(height*eighthsPerCell)))
+		for y := range height {
+			eighths := min(fill-y*eighthsPerCell, eighthsPerCell)
+			if eighths <= 0 {
+				break
+			}
+			for x := range barWidth {
+				g.set(i*colWidth+x, y, barTicks[eighths-1], 0)
+			}
+		}
+	}
+	return g
+}
+
+// lineGrid draws each series as a line through a point per value, scaled so highest is on
+// the top row. The points are drawn over the lines, and later series over earlier ones.
+func lineGrid(series [][]float64, highest float64, height, colWidth int) grid {
+	g := newGrid(height, colWidth*longest(series))
+	if highest <= 0 {
+		return g
+	}
+
+	rowOf := func(v float64) float64 {
+		return math.Max(v, 0) / highest * float64(height-1)
+	}
+	for s, values := range series {
+		for i := 1; i < len(values); i++ {
+			x0, x1 := i*colWidth-colWidth+colWidth/2, i*colWidth+colWidth/2
+			y0, y1 := rowOf(values[i-1]), rowOf(values[i])
+			yAt := func(x int) int {
+				return int(math.Round(y0 + (y1-y0)*float64(x-x0)/float64(x1-x0)))
+			}
+			// fill the rows between neighbouring columns so steep lines stay connected
+			for x := x0 + 1; x <= x1; x++ {
+				from, to := min(yAt(x-1), yAt(x)), max(yAt(x-1), yAt(x))
+				for y := from; y <= to; y++ {
+					if g.empty(x, y) {
+						g.set(x, y, '·', s)
+					}
+				}
+			}
+		}
+	}
+	for s, values := range series {
+		for i, v := range values {
+			g.set(i*colWidth+colWidth/2, int(math.Round(rowOf(v))), '●', s)
+		}
+	}
+	return g
+}
+
+func longest(series [][]float64) int {
+	n := 0
+	for _, values := range series {
+		n = max(n, len(values))
+	}
+	return n
+}
+
+// render draws the grid with the amount axis on the left and the month labels below. The
+// cells of each series are drawn in its style.
+func (g grid) render(styles []lipgloss.Style, top, bottom string, labels []string, colWidth int) string {
+	axisWidth := max(lipgloss.Width(top), lipgloss.Width(bottom))
+
+	var b strings.Builder
+	for y, row := range g {
+		label := ""
+		switch y {
+		case 0:
+			label = top
+		case len(g) - 1:
+			label = bottom
+		}
+		b.WriteString(fmt.Sprintf("%*s ┤", axisWidth, label))
+		for _, c := range row {
+			if c.series == noSeries || c.series >= len(styles) {
+				b.WriteRune(c.r)
+				continue
+			}
+			b.WriteString(styles[c.series].Render(string(c.r)))
+		}
+		b.WriteString("\n")
+	}
+
+	width := 0
+	if len(g) > 0 {
+		width = len(g[0])
+	}
+	b.WriteString(strings.Repeat(" ", axisWidth+1) + "└" + strings.Repeat("─", width) + "\n")
+	b.WriteString(strings.Repeat(" ", axisWidth+2) + monthLabels(labels, colWidth))
+	return b.String()
+}
+
+// monthLabels places a label under each month, skipping months when the columns are too
+// narrow for every label.
+func monthLabels(labels []string, colWidth int) string {
+	every := 1
+	if colWidth < minLabelWidth {
+		every = (minLabelWidth + colWidth - 1) / colWidth
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(labels); i += every {
+		width := colWidth * min(every, len(labels)-i)
+		label := labels[i]
+		if len(label) > width {
+			label = label[:width]
+		}
+		b.WriteString(fmt.Sprintf("%-*s", width, label))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// compactAmount formats an amount for the axis, in thousands or millions when it is large.
+func compactAmount(symbol string, amount float64) string {
+	const thousand, million = 1_000, 1_000_000
+	switch {
+	case amount >= million:
+		return fmt.Sprintf("%s%.1fM", symbol, amount/million)
+	case amount >= thousand:
+		return fmt.Sprintf("%s%.1fk", symbol, amount/thousand)
+	}
+	return fmt.Sprintf("%s%.0f", symbol, amount)
+}
@@ -0,0 +1,56 @@
+package trends
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+)
+
+// rows returns the runes of the grid, top row first.
+func rows(g grid) []string {
+	lines := make([]string, len(g))
+	for y, row := range g {
+		var b strings.Builder
+		for _, c := range row {
+			b.WriteRune(c.r)
+		}
+		lines[y] = b.String()
+	}
+	return lines
+}
+
+func TestBarGrid(t *testing.T) {
+	g := barGrid([]float64{0, 5, 10, 1}, 10, 2, 2)
+	be.AllEqual(t, []string{
+		"    █   ",
+		"  █ █ ▂ ",
+	}, rows(g))
+}
+
+func TestBarGridNothingSpent(t *testing.T) {
+	g := barGrid([]float64{0, 0}, 0, 2, 2)
+	be.AllEqual(t, []string{"    ", "    "}, rows(g))
+}
+
+func TestLineGrid(t *testing.T) {
+	g := lineGrid([][]float64{{0, 10, 10}}, 10, 3, 3)
+	be.AllEqual(t, []string{
+		"    ●··● ",
+		"  ···    ",
+		" ●·      ",
+	}, rows(g))
+}
+
+func TestMonthLabels(t *testing.T) {
+	labels := []string{"Jan", "Feb", "Mar", "Apr"}
+	be.Equal(t, "Jan Feb Mar Apr", monthLabels(labels, 4))
+	// two columns a month only fit every other label
+	be.Equal(t, "Jan Mar", monthLabels(labels, 2))
+}
+
+func TestCompactAmount(t *testing.T) {
+	be.Equal(t, "$950", compactAmount("$", 950))
+	be.Equal(t, "$1.2k", compactAmount("$", 1234))
+	be.Equal(t, "€2.5M", compactAmount("€", 2_500_000))
+}
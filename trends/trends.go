@@ -0,0 +1,437 @@
+// Package trends shows how spending changed over the last months, as a bar chart of the
+// total spending and a line chart of the categories selected to overlay.
+package trends
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/Rshep3087/lunchtui/totals"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	lm "github.com/icco/lunchmoney"
+)
+
+const (
+	// DefaultMonths is how many months the trends show by default
+	DefaultMonths = 12
+	minMonths     = 3
+	maxMonths     = 36
+	monthsStep    = 3
+
+	// defaultOverlays is how many of the top categories are overlaid when the data loads
+	defaultOverlays = 3
+	categoryWidth   = 32
+	minChartHeight  = 4
+	// chartRows is the rows each chart takes besides its grid, the title, axis and labels
+	chartRows = 3
+	// hintRows is the rows taken by the range summary and the key hints
+	hintRows = 2
+)
+
+type Colors struct {
+	Primary string
+	Muted   string
+	// Overlays are the colors of the overlaid categories, used in turn
+	Overlays []string
+}
+
+// series is the spending of a category in each month.
+type series struct {
+	id     int64
+	name   string
+	values []*money.Money
+	total  *money.Money
+}
+
+// MonthsChangedMsg is sent when the user changes how many months are shown, so that the
+// transactions of the new range can be loaded.
+type MonthsChangedMsg struct {
+	Months int
+}
+
+type Model struct {
+	width  int
+	height int
+	keys   keyMap
+
+	primaryStyle  lipgloss.Style
+	mutedStyle    lipgloss.Style
+	overlayStyles []lipgloss.Style
+
+	// months is the first day of each month shown, oldest first
+	months     []time.Time
+	monthCount int
+	currency   string
+	loaded     bool
+	total      series
+	categories []series
+	// unconverted counts the transactions left out for lack of a base amount
+	unconverted int
+
+	cursor int
+	// overlays are the IDs of the categories drawn on the line chart, in the order they
+	// were selected, nil until the user picks some
+	overlays []int64
+}
+
+type keyMap struct {
+	up           key.Binding
+	down         key.Binding
+	toggle       key.Binding
+	moreMonths   key.Binding
+	fewerMonths  key.Binding
+	clearOverlay key.Binding
+}
+
+func New(colors Colors) Model {
+	overlayStyles := make([]lipgloss.Style, 0, len(colors.Overlays))
+	for _, c := range colors.Overlays {
+		overlayStyles = append(overlayStyles, lipgloss.NewStyle().Foreground(lipgloss.Color(c)))
+	}
+
+	return Model{
+		monthCount: DefaultMonths,
+		keys: keyMap{
+			up:           key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			down:         key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			toggle:       key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "overlay category")),
+			clearOverlay: key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "clear overlays")),
+			moreMonths:   key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "more months")),
+			fewerMonths:  key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "fewer months")),
+		},
+		primaryStyle:  lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Primary)),
+		mutedStyle:    lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Muted)),
+		overlayStyles: overlayStyles,
+	}
+}
+
+// Months returns how many months the trends show.
+func (m *Model) Months() int {
+	return m.monthCount
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetLoading clears the data shown until the transactions of a new range are set.
+func (m *Model) SetLoading() {
+	m.loaded = false
+}
+
+// SetTransactions sets the transactions of the months starting with start, adding up the
+// spending of each category in the currency. Income, groups and categories excluded from
+// totals are left out, like in the overview.
+func (m *Model) SetTransactions(
+	transactions []*lm.Transaction,
+	categories map[int64]*lm.Category,
+	start time.Time,
+	currency string,
+) {
+	if currency == "" {
+		currency = money.USD
+	}
+	m.currency = currency
+	m.months = make([]time.Time, m.monthCount)
+	for i := range m.months {
+		m.months[i] = start.AddDate(0, i, 0)
+	}
+
+	m.total = newSeries(0, "Total", len(m.months), currency)
+	byCategory := make(map[int64]*series)
+	m.unconverted = 0
+
+	for _, t := range transactions {
+		category := categories[t.CategoryID]
+		if category == nil || category.ExcludeFromTotals || category.IsIncome || category.IsGroup {
+			continue
+		}
+
+		month := m.monthIndex(t.Date)
+		if month < 0 {
+			continue
+		}
+
+		amount, err := t.ParsedAmount()
+		if err != nil {
+			continue
+		}
+		amount, ok := totals.Convert(amount.Absolute(), math.Abs(t.ToBase), currency)
+		if !ok {
+			m.unconverted++
+			continue
+		}
+
+		s, exists := byCategory[category.ID]
+		if !exists {
+			created := newSeries(category.ID, category.Name, len(m.months), currency)
+			s = &created
+			byCategory[category.ID] = s
+		}
+		s.add(month, amount)
+		m.total.add(month, amount)
+	}
+
+	m.categories = make([]series, 0, len(byCategory))
+	for _, s := range byCategory {
+		m.categories = append(m.categories, *s)
+	}
+	slices.SortFunc(m.categories, func(a, b series) int {
+		return cmp.Or(cmp.Compare(b.total.Amount(), a.total.Amount()), strings.Compare(a.name, b.name))
+	})
+
+	// keep the overlays of the categories still there, or start with the top categories
+	// until the user picks some
+	m.overlays = slices.DeleteFunc(m.overlays, func(id int64) bool { return byCategory[id] == nil })
+	if m.overlays == nil {
+		for _, s := range m.categories[:min(defaultOverlays, len(m.categories))] {
+			m.overlays = append(m.overlays, s.id)
+		}
+	}
+	m.cursor = min(m.cursor, max(len(m.categories)-1, 0))
+	m.loaded = true
+}
+
+func newSeries(id int64, name string, months int, currency string) series {
+	s := series{id: id, name: name, values: make([]*money.Money, months), total: money.New(0, currency)}
+	for i := range s.values {
+		s.values[i] = money.New(0, currency)
+	}
+	return s
+}
+
+func (s *series) add(month int, amount *money.Money) {
+	s.values[month], _ = s.values[month].Add(amount)
+	s.total, _ = s.total.Add(amount)
+}
+
+// floats returns the values in major units, for charting.
+func (s *series) floats() []float64 {
+	values := make([]float64, len(s.values))
+	for i, v := range s.values {
+		values[i] = v.AsMajorUnits()
+	}
+	return values
+}
+
+// monthIndex returns the index of the month of a transaction date, or -1 when it is not
+// one of the months shown.
+func (m *Model) monthIndex(date string) int {
+	d, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return -1
+	}
+	for i, month := range m.months {
+		if d.Year() == month.Year() && d.Month() == month.Month() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return *m, nil
+	}
+
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(keyMsg, m.keys.up):
+		m.cursor = max(m.cursor-1, 0)
+	case key.Matches(keyMsg, m.keys.down):
+		m.cursor = min(m.cursor+1, max(len(m.categories)-1, 0))
+	case key.Matches(keyMsg, m.keys.toggle):
+		m.toggleOverlay()
+	case key.Matches(keyMsg, m.keys.clearOverlay):
+		m.overlays = []int64{}
+	case key.Matches(keyMsg, m.keys.moreMonths):
+		cmd = m.setMonths(m.monthCount + monthsStep)
+	case key.Matches(keyMsg, m.keys.fewerMonths):
+		cmd = m.setMonths(m.monthCount - monthsStep)
+	}
+	return *m, cmd
+}
+
+func (m *Model) toggleOverlay() {
+	if m.cursor >= len(m.categories) {
+		return
+	}
+	id := m.categories[m.cursor].id
+	if i := slices.Index(m.overlays, id); i >= 0 {
+		m.overlays = slices.Delete(m.overlays, i, i+1)
+		return
+	}
+	m.overlays = append(m.overlays, id)
+}
+
+// setMonths changes how many months are shown and asks for their transactions.
+func (m *Model) setMonths(months int) tea.Cmd {
+	months = min(max(months, minMonths), maxMonths)
+	if months == m.monthCount {
+		return nil
+	}
+	m.monthCount = months
+	return func() tea.Msg {
+		return MonthsChangedMsg{Months: months}
+	}
+}
+
+func (m *Model) View() string {
+	if !m.loaded {
+		return "Loading spending trends..."
+	}
+	if len(m.categories) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, "No spending in these months", m.hints())
+	}
+
+	charts := lipgloss.JoinVertical(lipgloss.Left, m.totalChart(), "", m.overlayChart())
+	body := lipgloss.JoinHorizontal(lipgloss.Top, m.categoryList(), "  ", charts)
+	return lipgloss.JoinVertical(lipgloss.Left, m.summary(), body, m.hints())
+}
+
+// chartSize returns the rows of each chart grid and the columns of each month.
+func (m *Model) chartSize() (int, int) {
+	// the axis labels are at most 8 wide, with the axis line
+	const axisWidth = 10
+	height := max((m.height-hintRows)/2-chartRows, minChartHeight)
+	colWidth := max((m.width-categoryWidth-axisWidth)/max(len(m.months), 1), 1)
+	return height, colWidth
+}
+
+func (m *Model) summary() string {
+	if len(m.months) == 0 {
+		return ""
+	}
+	average := money.New(m.total.total.Amount()/int64(len(m.months)), m.currency)
+
+	text := fmt.Sprintf("%s – %s: %s spent, %s a month on average",
+		m.months[0].Format("Jan 2006"), m.months[len(m.months)-1].Format("Jan 2006"),
+		m.total.total.Display(), average.Display())
+	if m.unconverted > 0 {
+		text += fmt.Sprintf(" (%d transactions not converted to %s left out)",
+			m.unconverted, strings.ToUpper(m.currency))
+	}
+	return m.mutedStyle.Render(text)
+}
+
+func (m *Model) totalChart() string {
+	height, colWidth := m.chartSize()
+	values := m.total.floats()
+	highest := slices.Max(values)
+	g := barGrid(values, highest, height, colWidth)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		"Total spending",
+		g.render([]lipgloss.Style{m.primaryStyle}, m.axisLabel(highest), m.axisLabel(0), m.monthLabels(), colWidth),
+	)
+}
+
+func (m *Model) overlayChart() string {
+	height, colWidth := m.chartSize()
+	if len(m.overlays) == 0 {
+		return m.mutedStyle.Render("Press space on a category to overlay it")
+	}
+
+	var (
+		lines   [][]float64
+		styles  []lipgloss.Style
+		legend  []string
+		highest float64
+	)
+	for _, id := range m.overlays {
+		s := m.category(id)
+		if s == nil {
+			continue
+		}
+		style := m.overlayStyle(id)
+		values := s.floats()
+		lines = append(lines, values)
+		styles = append(styles, style)
+		legend = append(legend, style.Render("● "+s.name))
+		highest = math.Max(highest, slices.Max(values))
+	}
+
+	g := lineGrid(lines, highest, height, colWidth)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		"By category  "+strings.Join(legend, "  "),
+		g.render(styles, m.axisLabel(highest), m.axisLabel(0), m.monthLabels(), colWidth),
+	)
+}
+
+func (m *Model) categoryList() string {
+	// the list is as high as both charts, less the header
+	rows := max(m.height-hintRows-1, 1)
+	first := max(min(m.cursor-rows/2, len(m.categories)-rows), 0)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%-*s\n", categoryWidth, "Categories"))
+	for i := first; i < min(first+rows, len(m.categories)); i++ {
+		s := m.categories[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = m.primaryStyle.Render("▸ ")
+		}
+		marker := "○"
+		if slices.Contains(m.overlays, s.id) {
+			marker = m.overlayStyle(s.id).Render("●")
+		}
+
+		// the cursor, marker and spaces take 5 columns
+		const amountWidth, nameWidth = 11, categoryWidth - 11 - 5
+		name := []rune(s.name)
+		if len(name) > nameWidth {
+			name = append(name[:nameWidth-1], '…')
+		}
+		fmt.Fprintf(&b, "%s%s %-*s %*s\n", cursor, marker, nameWidth, string(name), amountWidth, s.total.Display())
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// category returns the series of a category, nil when it has no spending.
+func (m *Model) category(id int64) *series {
+	for i := range m.categories {
+		if m.categories[i].id == id {
+			return &m.categories[i]
+		}
+	}
+	return nil
+}
+
+// overlayStyle returns the style of an overlaid category, by the order it was selected.
+func (m *Model) overlayStyle(id int64) lipgloss.Style {
+	i := slices.Index(m.overlays, id)
+	if i < 0 || len(m.overlayStyles) == 0 {
+		return m.primaryStyle
+	}
+	return m.overlayStyles[i%len(m.overlayStyles)]
+}
+
+func (m *Model) axisLabel(amount float64) string {
+	return compactAmount(money.New(0, m.currency).Currency().Grapheme, amount)
+}
+
+func (m *Model) monthLabels() []string {
+	labels := make([]string, len(m.months))
+	for i, month := range m.months {
+		labels[i] = month.Format("Jan")
+	}
+	return labels
+}
+
+func (m *Model) hints() string {
+	bindings := []key.Binding{m.keys.toggle, m.keys.clearOverlay, m.keys.moreMonths, m.keys.fewerMonths}
+	hints := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		hints = append(hints, b.Help().Key+" "+b.Help().Desc)
+	}
+	return m.mutedStyle.Render(strings.Join(hints, " • "))
+}
@@ -0,0 +1,107 @@
+package trends
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	tea "github.com/charmbracelet/bubbletea"
+	lm "github.com/icco/lunchmoney"
+)
+
+var testCategories = map[int64]*lm.Category{
+	1: {ID: 1, Name: "Groceries"},
+	2: {ID: 2, Name: "Dining"},
+	3: {ID: 3, Name: "Salary", IsIncome: true},
+	4: {ID: 4, Name: "Transfers", ExcludeFromTotals: true},
+	5: {ID: 5, Name: "Rent"},
+	6: {ID: 6, Name: "Travel"},
+}
+
+var testTransactions = []*lm.Transaction{
+	{ID: 1, CategoryID: 1, Date: "2024-08-03", Amount: "40.00", Currency: "usd"},
+	{ID: 2, CategoryID: 1, Date: "2024-09-03", Amount: "60.00", Currency: "usd"},
+	{ID: 3, CategoryID: 2, Date: "2024-09-10", Amount: "20.00", Currency: "eur", ToBase: 22},
+	{ID: 4, CategoryID: 2, Date: "2024-10-10", Amount: "5.00", Currency: "gbp"},
+	{ID: 5, CategoryID: 3, Date: "2024-10-01", Amount: "-1000.00", Currency: "usd"},
+	{ID: 6, CategoryID: 4, Date: "2024-10-01", Amount: "500.00", Currency: "usd"},
+	{ID: 7, CategoryID: 5, Date: "2024-10-01", Amount: "900.00", Currency: "usd"},
+	{ID: 8, CategoryID: 6, Date: "2024-07-31", Amount: "300.00", Currency: "usd"},
+	{ID: 9, CategoryID: 6, Date: "2024-10-20", Amount: "-15.00", Currency: "usd"},
+}
+
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	m := New(Colors{Overlays: []string{"1", "2"}})
+	m.SetSize(120, 40)
+	start := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	be.Equal(t, minMonths, m.Months())
+
+	m.SetTransactions(testTransactions, testCategories, start, "usd")
+	return m
+}
+
+func display(s series) []string {
+	values := make([]string, len(s.values))
+	for i, v := range s.values {
+		values[i] = v.Display()
+	}
+	return values
+}
+
+func TestSetTransactions(t *testing.T) {
+	m := newTestModel(t)
+
+	be.AllEqual(t, []string{"$40.00", "$82.00", "$915.00"}, display(m.total))
+	be.Equal(t, 1, m.unconverted)
+
+	names := make([]string, len(m.categories))
+	for i, s := range m.categories {
+		names[i] = s.name
+	}
+	be.AllEqual(t, []string{"Rent", "Groceries", "Dining", "Travel"}, names)
+	be.AllEqual(t, []string{"$40.00", "$60.00", "$0.00"}, display(m.categories[1]))
+
+	// the top categories are overlaid until the user picks some
+	be.AllEqual(t, []int64{5, 1, 2}, m.overlays)
+}
+
+func TestOverlays(t *testing.T) {
+	m := newTestModel(t)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	be.Equal(t, 0, len(m.overlays))
+	be.True(t, strings.Contains(m.View(), "Press space on a category to overlay it"))
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	be.AllEqual(t, []int64{1}, m.overlays)
+	be.True(t, strings.Contains(m.View(), "By category  ● Groceries"))
+
+	// a reload keeps the overlays the user picked
+	m.SetTransactions(testTransactions, testCategories, m.months[0], "usd")
+	be.AllEqual(t, []int64{1}, m.overlays)
+
+	m.SetTransactions(nil, testCategories, m.months[0], "usd")
+	be.True(t, strings.Contains(m.View(), "No spending in these months"))
+}
+
+func TestSetMonths(t *testing.T) {
+	m := New(Colors{})
+	be.Equal(t, DefaultMonths, m.Months())
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	be.Equal(t, DefaultMonths+monthsStep, m.Months())
+	be.Equal[tea.Msg](t, MonthsChangedMsg{Months: DefaultMonths + monthsStep}, cmd())
+
+	for range maxMonths {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	}
+	be.Equal(t, maxMonths, m.Months())
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	be.Zero(t, cmd)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Rshep3087/lunchtui/trends"
+	"github.com/carlmjohnson/be"
+)
+
+func TestTrendsPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		periodType string
+		current    time.Time
+		expected   string
+	}{
+		{
+			name:       "ends with the month",
+			periodType: monthlyPeriodType,
+			current:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			expected:   "2023-04-01 - 2024-03-31",
+		},
+		{
+			name:       "ends with the month the year ends in",
+			periodType: annualPeriodType,
+			current:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			expected:   "2024-01-01 - 2024-12-31",
+		},
+		{
+			name:       "ends with the month of the week",
+			periodType: weeklyPeriodType,
+			current:    time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			expected:   "2023-04-01 - 2024-03-31",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{periodType: tt.periodType, currentPeriod: tt.current, trends: trends.New(trends.Colors{})}
+			p := m.trendsPeriod()
+			be.Equal(t, tt.expected, p.String())
+		})
+	}
+}
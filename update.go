@@ -5,6 +5,10 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Rshep3087/lunchtui/overview"
+	"github.com/Rshep3087/lunchtui/recurring"
+	"github.com/Rshep3087/lunchtui/trends"
+
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
@@ -56,6 +60,39 @@ func (m model) handleMessages(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
 	case getBudgetsMsg:
 		model, cmd := m.handleGetBudgets(msg)
 		return model, cmd, true
+	case getNetWorthHistoryMsg:
+		model, cmd := m.handleGetNetWorthHistory(msg)
+		return model, cmd, true
+	case overview.ComparisonChangedMsg:
+		model, cmd := m.handleComparisonChanged(msg)
+		return model, cmd, true
+	case overview.CategorySelectedMsg:
+		model, cmd := filterCategoryTransactions(m, msg)
+		return model, cmd, true
+	case recurring.CalendarShownMsg:
+		model, cmd := m.handleCalendarShown(msg)
+		return model, cmd, true
+	case getCalendarTransactionsMsg:
+		model, cmd := m.handleGetCalendarTransactions(msg)
+		return model, cmd, true
+	case getComparisonTransactionsMsg:
+		model, cmd := m.handleGetComparisonTransactions(msg)
+		return model, cmd, true
+	case trends.MonthsChangedMsg:
+		m.trends.SetLoading()
+		return m, m.getTrends, true
+	case overview.AccountDetailsMsg:
+		model, cmd := showAccountForm(&m)
+		return model, cmd, true
+	case saveAssetMsg:
+		model, cmd := m.handleSaveAssetMsg(msg)
+		return model, cmd, true
+	case getAccountTransactionsMsg:
+		model, cmd := m.handleGetAccountTransactions(msg)
+		return model, cmd, true
+	case getTrendsMsg:
+		model, cmd := m.handleGetTrends(msg)
+		return model, cmd, true
 	case authErrorMsg:
 		m.sessionState = errorState
 		m.errorMsg = fmt.Sprintf("Check your API token: %s", msg.err.Error())
@@ -63,6 +100,27 @@ func (m model) handleMessages(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
 	case insertTransactionMsg:
 		model, cmd := m.handleInsertTransactionMsg(msg)
 		return model, cmd, true
+	case bulkUpdateMsg:
+		model, cmd := m.handleBulkUpdateMsg(msg)
+		return model, cmd, true
+	case aiBatchMsg:
+		model, cmd := m.handleAIBatchMsg(msg)
+		return model, cmd, true
+	case splitTransactionMsg:
+		model, cmd := m.handleSplitTransactionMsg(msg)
+		return model, cmd, true
+	case groupTransactionsMsg:
+		model, cmd := m.handleGroupTransactionsMsg(msg)
+		return model, cmd, true
+	case ungroupTransactionMsg:
+		model, cmd := m.handleUngroupTransactionMsg(msg)
+		return model, cmd, true
+	case updateTagsMsg:
+		model, cmd := m.handleUpdateTagsMsg(msg)
+		return model, cmd, true
+	case updateBudgetMsg:
+		model, cmd := m.handleUpdateBudgetMsg(msg)
+		return model, cmd, true
 	}
 	return m, nil, false
 }
@@ -93,6 +151,9 @@ func (m model) handleSessionState(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case recurringExpenses:
 		m.recurringExpenses, cmd = m.recurringExpenses.Update(msg)
 		return m, cmd
+	case trendsState:
+		m.trends, cmd = m.trends.Update(msg)
+		return m, cmd
 	case insertTransaction:
 		return m.handleInsertTransactionState(msg)
 	case budgets:
@@ -100,6 +161,30 @@ func (m model) handleSessionState(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case configView:
 		m.configView, cmd = m.configView.Update(msg)
 		return m, cmd
+	case bulkActions:
+		return m.handleBulkActionFormState(msg)
+	case bulkUpdating:
+		return updateBulkUpdating(msg, m)
+	case aiReviewState:
+		return updateAIReview(msg, m)
+	case splitTransactionState:
+		return m.handleSplitFormState(msg)
+	case editBudgetState:
+		return m.handleBudgetFormState(msg)
+	case periodPickerState:
+		return m.handlePeriodFormState(msg)
+	case accountPickerState:
+		return m.handleAccountFormState(msg)
+	case accountDetailState:
+		return updateAccountDetail(msg, m)
+	case assetFormState:
+		return m.handleAssetFormState(msg)
+	case editTagsState:
+		return m.handleTagFormState(msg)
+	case rulesPreviewState:
+		return m.handleRulesPreviewState(msg)
+	case ungroupTransactionState:
+		return m.handleUngroupFormState(msg)
 	case loading:
 		m.loadingSpinner, cmd = m.loadingSpinner.Update(msg)
 		return m, cmd
@@ -28,6 +28,32 @@ func (m model) View() string {
 		b.WriteString(budgetsView(m))
 	case configView:
 		b.WriteString(m.configView.View())
+	case bulkActions:
+		b.WriteString(m.bulkActionForm.View())
+	case bulkUpdating:
+		b.WriteString(bulkUpdatingView(m))
+	case aiReviewState:
+		b.WriteString(aiReviewView(m))
+	case splitTransactionState:
+		b.WriteString(m.splitForm.View())
+	case editBudgetState:
+		b.WriteString(m.budgetForm.View())
+	case periodPickerState:
+		b.WriteString(m.periodForm.View())
+	case trendsState:
+		b.WriteString(m.trends.View())
+	case accountPickerState:
+		b.WriteString(m.accountForm.View())
+	case accountDetailState:
+		b.WriteString(accountDetailView(m))
+	case assetFormState:
+		b.WriteString(m.assetForm.View())
+	case editTagsState:
+		b.WriteString(m.tagForm.View())
+	case rulesPreviewState:
+		b.WriteString(rulesPreviewView(m))
+	case ungroupTransactionState:
+		b.WriteString(m.ungroupForm.View())
 	case loading:
 		b.WriteString(fmt.Sprintf("%s Loading data...", m.loadingSpinner.View()))
 	case errorState:
@@ -54,6 +80,13 @@ func (m model) renderTitle() string {
 		title = append(title, m.user.BudgetName)
 	}
 
+	if m.lmCache.isOffline() {
+		title = append(title, "offline")
+	}
+	if !m.cachedAt.IsZero() {
+		title = append(title, "cached "+m.cachedAt.Format("Jan 2 15:04"))
+	}
+
 	b.WriteString(m.styles.titleStyle.Render(strings.Join(title, " | ")))
 
 	return b.String()